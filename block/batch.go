@@ -31,6 +31,13 @@ func (bq *BatchQueue) AddBatch(batch BatchWithTime) {
 	}
 }
 
+// Len returns the number of batches in the queue
+func (bq *BatchQueue) Len() int {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+	return len(bq.queue)
+}
+
 // Next returns the next batch in the queue
 func (bq *BatchQueue) Next() *BatchWithTime {
 	bq.mu.Lock()
@@ -42,6 +42,7 @@ func TestBatchQueue_AddBatch(t *testing.T) {
 	bq.AddBatch(batch2)
 	require.Len(t, bq.queue, 2, "BatchQueue should have 2 batches after adding another")
 	require.Equal(t, batch2, bq.queue[1], "The second batch should match the one added")
+	require.Equal(t, 2, bq.Len(), "Len should return the number of batches")
 }
 
 func TestBatchQueue_Next(t *testing.T) {
@@ -0,0 +1,153 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// maxBlockPartSize is the maximum accepted size of serialized header or data, if DA max blob size is not known.
+const maxBlockPartSize = 128 << 20
+
+// ErrEmptyDataMismatch is returned when block data is omitted, but header doesn't commit to empty transactions.
+var ErrEmptyDataMismatch = errors.New("block data omitted for header with non-empty data hash")
+
+// validateReceivedBlock checks that block received from a peer is signed by the expected sequencer and data
+// matches the header. Data may be omitted (nil) only for blocks without transactions.
+func (m *Manager) validateReceivedBlock(header *types.SignedHeader, data *types.Data) error {
+	if !m.isUsingExpectedCentralizedSequencer(header) {
+		return errors.New("unexpected sequencer")
+	}
+	if data == nil {
+		if !bytes.Equal(header.DataHash, dataHashForEmptyTxs) {
+			return ErrEmptyDataMismatch
+		}
+		return nil
+	}
+	return types.Validate(header, data)
+}
+
+// sendReceivedBlock passes validated block to the sync loop. If data is omitted, it's recreated by the sync loop.
+func (m *Manager) sendReceivedBlock(header *types.SignedHeader, data *types.Data) {
+	daHeight := atomic.LoadUint64(&m.daHeight)
+	m.headerInCh <- NewHeaderEvent{header, daHeight}
+	if data != nil {
+		m.dataInCh <- NewDataEvent{data, daHeight}
+	}
+}
+
+// trySendReceivedBlock passes validated block to the sync loop without blocking. It returns false if header can't
+// be passed, because the channel is full. Data that can't be passed is dropped, and retrieved from DA later.
+func (m *Manager) trySendReceivedBlock(header *types.SignedHeader, data *types.Data) bool {
+	daHeight := atomic.LoadUint64(&m.daHeight)
+	select {
+	case m.headerInCh <- NewHeaderEvent{header, daHeight}:
+	default:
+		return false
+	}
+	if data != nil {
+		select {
+		case m.dataInCh <- NewDataEvent{data, daHeight}:
+		default:
+			m.logger.Debug("dropping gossiped block data, sync loop is busy", "height", header.Height())
+		}
+	}
+	return true
+}
+
+// writeBlock writes length-prefixed header and data. Data of blocks without transactions is omitted (written as
+// empty part), as it can be recreated from header and previous block.
+func writeBlock(w io.Writer, header *types.SignedHeader, data *types.Data) error {
+	headerBytes, err := header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	var dataBytes []byte
+	if !bytes.Equal(header.DataHash, dataHashForEmptyTxs) {
+		dataBytes, err = data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+	for _, part := range [][]byte{headerBytes, dataBytes} {
+		if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(part)))); err != nil {
+			return err
+		}
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// blockPartLimit returns the maximum accepted size of serialized header or data received from peers. Valid block
+// parts never exceed DA max blob size.
+func (m *Manager) blockPartLimit() uint64 {
+	if m.maxBlobSize == 0 {
+		return maxBlockPartSize
+	}
+	return min(m.maxBlobSize, maxBlockPartSize)
+}
+
+// unmarshalBlock reads block written with writeBlock, rejecting header or data larger than limit bytes. Returned
+// data is nil if it was omitted.
+func unmarshalBlock(r byteReader, limit uint64) (*types.SignedHeader, *types.Data, error) {
+	headerBytes, err := readBlockPart(r, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	dataBytes, err := readBlockPart(r, limit)
+	if err != nil {
+		return nil, nil, noEOF(err)
+	}
+	header := new(types.SignedHeader)
+	if err := header.UnmarshalBinary(headerBytes); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal header: %w", err)
+	}
+	if len(dataBytes) == 0 {
+		return header, nil, nil
+	}
+	data := new(types.Data)
+	if err := data.UnmarshalBinary(dataBytes); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	return header, data, nil
+}
+
+// readBlockPart reads length-prefixed block part. Part is read incrementally, so memory is allocated only for
+// bytes actually sent by the peer, not for the announced size.
+func readBlockPart(r byteReader, limit uint64) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > limit {
+		return nil, fmt.Errorf("block part too large: %d bytes", size)
+	}
+	buf, err := io.ReadAll(io.LimitReader(r, int64(size))) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(buf)) < size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return buf, nil
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF, for errors in the middle of a block.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestBlockCodec(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	chainID := "TestBlockCodec"
+
+	header, data := types.GetRandomBlock(1, 5, chainID)
+	var buf bytes.Buffer
+	require.NoError(writeBlock(&buf, header, data))
+	decodedHeader, decodedData, err := unmarshalBlock(bytes.NewReader(buf.Bytes()), maxBlockPartSize)
+	require.NoError(err)
+	assert.Equal(header.Hash(), decodedHeader.Hash())
+	require.NotNil(decodedData)
+	assert.Equal(data.Hash(), decodedData.Hash())
+
+	// data of block without transactions is omitted
+	header, data = types.GetRandomBlock(2, 0, chainID)
+	header.DataHash = dataHashForEmptyTxs
+	buf.Reset()
+	require.NoError(writeBlock(&buf, header, data))
+	decodedHeader, decodedData, err = unmarshalBlock(bytes.NewReader(buf.Bytes()), maxBlockPartSize)
+	require.NoError(err)
+	assert.Equal(header.Hash(), decodedHeader.Hash())
+	assert.Nil(decodedData)
+
+	// truncated message
+	_, _, err = unmarshalBlock(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), maxBlockPartSize)
+	assert.Error(err)
+
+	// header larger than limit
+	_, _, err = unmarshalBlock(bytes.NewReader(buf.Bytes()), 10)
+	assert.Error(err)
+
+	// announced size larger than sent bytes doesn't allocate announced size
+	_, _, err = unmarshalBlock(bytes.NewReader(binary.AppendUvarint(nil, maxBlockPartSize)), maxBlockPartSize)
+	assert.ErrorIs(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,93 @@
+package block
+
+import (
+	"bytes"
+	"context"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/types"
+)
+
+// BlockGossiper publishes combined blocks (header and data in a single message) to the P2P network.
+type BlockGossiper interface {
+	GossipBlock(ctx context.Context, block []byte) error
+}
+
+// SetBlockGossiper sets gossiper used to publish produced blocks on the combined block topic.
+// It must be called before block production is started.
+func (m *Manager) SetBlockGossiper(g BlockGossiper) {
+	m.blockGossiper = g
+}
+
+// DataStoreWriter appends block data to the data store, served to peers on request.
+type DataStoreWriter interface {
+	WriteToStore(ctx context.Context, data *types.Data) error
+}
+
+// SetDataStoreWriter sets writer of synced block data. With combined block gossip, data is not gossiped on the data
+// topic, so full nodes write data of synced blocks to the data store themselves.
+// It must be called before the sync loop is started.
+func (m *Manager) SetDataStoreWriter(w DataStoreWriter) {
+	m.dataStoreWriter = w
+}
+
+// BlockGossipValidator returns validator of combined block messages.
+//
+// Messages published by the node itself (self is its host peer ID) are accepted, all other messages are validated.
+// Valid blocks are passed to the sync loop, unless the node is the proposer. Validator never blocks: if the sync loop
+// is lagging behind, block is dropped locally (it will be retrieved from DA anyway), but still accepted, so it's
+// forwarded to other peers.
+func (m *Manager) BlockGossipValidator(self peer.ID) p2p.GossipValidator {
+	return func(msg *p2p.GossipMessage) pubsub.ValidationResult {
+		if msg.From == self {
+			return pubsub.ValidationAccept
+		}
+		header, data, err := unmarshalBlock(bytes.NewReader(msg.Data), m.blockPartLimit())
+		if err == nil {
+			err = m.validateReceivedBlock(header, data)
+		}
+		if err != nil {
+			m.logger.Debug("rejecting gossiped block", "peer", msg.From, "error", err)
+			return pubsub.ValidationReject
+		}
+		if m.isProposer {
+			return pubsub.ValidationAccept
+		}
+		if !m.trySendReceivedBlock(header, data) {
+			m.logger.Debug("dropping gossiped block, sync loop is busy", "peer", msg.From, "height", header.Height())
+			return pubsub.ValidationAccept
+		}
+		m.logger.Debug("block received via gossip", "peer", msg.From, "height", header.Height())
+		return pubsub.ValidationAccept
+	}
+}
+
+// writeSyncedData writes data of synced block to the data store, unless it was already synced by the data syncer.
+// Errors are only logged, as data store is only used to serve data to peers.
+func (m *Manager) writeSyncedData(ctx context.Context, data *types.Data) {
+	if m.dataStoreWriter == nil {
+		return
+	}
+	if m.dataStore != nil && data.Height() <= m.dataStore.Height() {
+		return
+	}
+	if err := m.dataStoreWriter.WriteToStore(ctx, data); err != nil {
+		m.logger.Debug("failed to write synced data to data store", "height", data.Height(), "error", err)
+	}
+}
+
+// gossipBlock publishes block on the combined block topic. Errors are only logged, as block header is also gossiped
+// in header topic and block can be retrieved from DA.
+func (m *Manager) gossipBlock(ctx context.Context, header *types.SignedHeader, data *types.Data) {
+	var buf bytes.Buffer
+	if err := writeBlock(&buf, header, data); err != nil {
+		m.logger.Error("failed to marshal block for gossiping", "height", header.Height(), "error", err)
+		return
+	}
+	if err := m.blockGossiper.GossipBlock(ctx, buf.Bytes()); err != nil {
+		m.logger.Error("failed to gossip block", "height", header.Height(), "error", err)
+	}
+}
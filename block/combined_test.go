@@ -0,0 +1,94 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestBlockGossipValidator(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "TestBlockGossipValidator"
+	genesis, privKey := types.GetGenesisWithPrivkey(types.DefaultSigningKeyType, chainID)
+	m := getManager(t, nil)
+	m.genesis = genesis
+	m.headerInCh = make(chan NewHeaderEvent, 1)
+	m.dataInCh = make(chan NewDataEvent, 1)
+	self, other := peer.ID("self"), peer.ID("other")
+	validator := m.BlockGossipValidator(self)
+
+	encode := func(header *types.SignedHeader, data *types.Data) *p2p.GossipMessage {
+		var buf bytes.Buffer
+		require.NoError(writeBlock(&buf, header, data))
+		return &p2p.GossipMessage{Data: buf.Bytes(), From: other}
+	}
+
+	header, data, _ := types.GenerateRandomBlockCustom(&types.BlockConfig{Height: 1, NTxs: 2, PrivKey: privKey}, chainID)
+	assert.Equal(pubsub.ValidationAccept, validator(encode(header, data)))
+	assert.Equal(header.Hash(), (<-m.headerInCh).Header.Hash())
+	assert.Equal(data.Hash(), (<-m.dataInCh).Data.Hash())
+
+	// block from unexpected proposer
+	header, data = types.GetRandomBlock(2, 2, chainID)
+	assert.Equal(pubsub.ValidationReject, validator(encode(header, data)))
+
+	// data not matching the header
+	header, _, _ = types.GenerateRandomBlockCustom(&types.BlockConfig{Height: 2, NTxs: 2, PrivKey: privKey}, chainID)
+	_, data = types.GetRandomBlock(2, 2, chainID)
+	assert.Equal(pubsub.ValidationReject, validator(encode(header, data)))
+
+	// garbage
+	assert.Equal(pubsub.ValidationReject, validator(&p2p.GossipMessage{Data: []byte{0xff}, From: other}))
+	assert.Empty(m.headerInCh)
+	assert.Empty(m.dataInCh)
+
+	// valid block is dropped locally, instead of blocking, if sync loop is busy, but it's still forwarded to peers
+	header, data, _ = types.GenerateRandomBlockCustom(&types.BlockConfig{Height: 2, NTxs: 2, PrivKey: privKey}, chainID)
+	m.headerInCh <- NewHeaderEvent{}
+	assert.Equal(pubsub.ValidationAccept, validator(encode(header, data)))
+	assert.Empty(m.dataInCh)
+	<-m.headerInCh
+
+	// own messages are accepted without validation
+	assert.Equal(pubsub.ValidationAccept, validator(&p2p.GossipMessage{Data: []byte{0xff}, From: self}))
+
+	// proposer validates messages of other peers, but doesn't sync them
+	m.isProposer = true
+	assert.Equal(pubsub.ValidationReject, validator(&p2p.GossipMessage{Data: []byte{0xff}, From: other}))
+	assert.Equal(pubsub.ValidationAccept, validator(encode(header, data)))
+	assert.Empty(m.headerInCh)
+	assert.Empty(m.dataInCh)
+}
+
+type testDataStoreWriter struct {
+	written []uint64
+}
+
+func (w *testDataStoreWriter) WriteToStore(_ context.Context, data *types.Data) error {
+	w.written = append(w.written, data.Height())
+	return nil
+}
+
+func TestWriteSyncedData(t *testing.T) {
+	ctx := context.Background()
+	m := getManager(t, nil)
+	// no writer set
+	m.writeSyncedData(ctx, &types.Data{Metadata: &types.Metadata{Height: 1}})
+
+	w := &testDataStoreWriter{}
+	m.SetDataStoreWriter(w)
+	for h := uint64(1); h <= 3; h++ {
+		m.writeSyncedData(ctx, &types.Data{Metadata: &types.Metadata{Height: h}})
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, w.written)
+}
@@ -0,0 +1,76 @@
+package block
+
+import (
+	"sync"
+	"time"
+)
+
+// daHeadTracker keeps track of the latest DA height known to be produced, used to avoid requesting DA heights that
+// don't exist yet. A nil *daHeadTracker doesn't limit anything.
+type daHeadTracker struct {
+	blockTime time.Duration
+
+	mtx    sync.Mutex
+	height uint64
+	seenAt time.Time
+}
+
+func newDAHeadTracker(blockTime time.Duration) *daHeadTracker {
+	return &daHeadTracker{blockTime: blockTime}
+}
+
+// observe records that DA height exists.
+func (t *daHeadTracker) observe(height uint64) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if height > t.height || t.seenAt.IsZero() {
+		t.height = height
+		t.seenAt = time.Now()
+	}
+}
+
+// observeFromFuture records that DA height is not produced yet, so the DA head is right below it.
+func (t *daHeadTracker) observeFromFuture(height uint64) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if height > 0 {
+		t.height = height - 1
+		t.seenAt = time.Now()
+	}
+}
+
+// estimate returns the estimated current DA head, assuming a DA block is produced every block time since the head was
+// observed. It returns false if DA head was never observed.
+func (t *daHeadTracker) estimate() (uint64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.seenAt.IsZero() {
+		return 0, false
+	}
+	if t.blockTime <= 0 {
+		return t.height, true
+	}
+	return t.height + uint64(time.Since(t.seenAt)/t.blockTime), true //nolint:gosec
+}
+
+// limitWindow caps the number of DA heights retrieved concurrently, starting at daHeight, so that only heights up to
+// the estimated DA head are requested. At least one height is always requested.
+func (t *daHeadTracker) limitWindow(daHeight, window uint64) uint64 {
+	head, ok := t.estimate()
+	if !ok {
+		return window
+	}
+	if head < daHeight {
+		return 1
+	}
+	return max(1, min(window, head-daHeight+1))
+}
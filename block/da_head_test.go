@@ -0,0 +1,35 @@
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDAHeadTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	var unknown *daHeadTracker
+	assert.EqualValues(16, unknown.limitWindow(5, 16))
+	assert.EqualValues(16, newDAHeadTracker(time.Hour).limitWindow(5, 16))
+
+	tracker := newDAHeadTracker(time.Hour)
+	tracker.observe(10)
+	tracker.observe(8)
+	assert.EqualValues(6, tracker.limitWindow(5, 16))
+	assert.EqualValues(2, tracker.limitWindow(5, 2))
+	assert.EqualValues(1, tracker.limitWindow(10, 16))
+	assert.EqualValues(1, tracker.limitWindow(11, 16))
+
+	tracker.observeFromFuture(8)
+	assert.EqualValues(3, tracker.limitWindow(5, 16))
+
+	// DA head moves forward with time
+	tracker = newDAHeadTracker(time.Millisecond)
+	tracker.observe(10)
+	time.Sleep(20 * time.Millisecond)
+	head, ok := tracker.estimate()
+	assert.True(ok)
+	assert.Greater(head, uint64(10))
+}
@@ -0,0 +1,165 @@
+package block
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// directBlockQueueLength is the number of blocks buffered for each direct peer.
+// If the queue is full, blocks are dropped and peer has to rely on gossip.
+const directBlockQueueLength = 100
+
+// DirectBlockProtocolID returns libp2p protocol ID used to push blocks directly from aggregator to full nodes.
+func DirectBlockProtocolID(chainID string) protocol.ID {
+	return protocol.ID("/rollkit/" + chainID + "/direct-blocks/1.0.0")
+}
+
+type directBlock struct {
+	header *types.SignedHeader
+	data   *types.Data
+}
+
+// DirectBlockPusher pushes produced blocks (header and data pairs) directly to trusted full nodes, bypassing
+// multi-hop gossip. Blocks are still gossiped, so peers that can't be reached directly fall back to gossip.
+type DirectBlockPusher struct {
+	host       host.Host
+	protocolID protocol.ID
+	queues     map[peer.ID]chan directBlock
+
+	wg     sync.WaitGroup
+	logger log.Logger
+}
+
+// NewDirectBlockPusher creates new DirectBlockPusher for given peers.
+func NewDirectBlockPusher(h host.Host, chainID string, peers []peer.AddrInfo, logger log.Logger) *DirectBlockPusher {
+	p := &DirectBlockPusher{
+		host:       h,
+		protocolID: DirectBlockProtocolID(chainID),
+		queues:     make(map[peer.ID]chan directBlock, len(peers)),
+		logger:     logger,
+	}
+	for _, info := range peers {
+		h.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+		p.queues[info.ID] = make(chan directBlock, directBlockQueueLength)
+	}
+	return p
+}
+
+// Start starts pushing blocks to peers, until ctx is canceled.
+func (p *DirectBlockPusher) Start(ctx context.Context) {
+	for id, queue := range p.queues {
+		p.wg.Add(1)
+		go p.pushLoop(ctx, id, queue)
+	}
+}
+
+// Wait blocks until all push loops are finished.
+func (p *DirectBlockPusher) Wait() {
+	p.wg.Wait()
+}
+
+// Push enqueues block for sending to all peers. It never blocks.
+func (p *DirectBlockPusher) Push(header *types.SignedHeader, data *types.Data) {
+	for id, queue := range p.queues {
+		select {
+		case queue <- directBlock{header: header, data: data}:
+		default:
+			p.logger.Debug("direct block queue full, relying on gossip", "peer", id, "height", header.Height())
+		}
+	}
+}
+
+func (p *DirectBlockPusher) pushLoop(ctx context.Context, id peer.ID, queue <-chan directBlock) {
+	defer p.wg.Done()
+	var (
+		stream network.Stream
+		writer *bufio.Writer
+	)
+	defer func() {
+		if stream != nil {
+			_ = stream.Close()
+		}
+	}()
+	for {
+		var block directBlock
+		select {
+		case <-ctx.Done():
+			return
+		case block = <-queue:
+		}
+
+		if stream == nil {
+			var err error
+			stream, err = p.host.NewStream(ctx, id, p.protocolID)
+			if err != nil {
+				p.logger.Debug("failed to open direct block stream", "peer", id, "error", err)
+				continue
+			}
+			writer = bufio.NewWriter(stream)
+		}
+		err := writeBlock(writer, block.header, block.data)
+		if err == nil {
+			err = writer.Flush()
+		}
+		if err != nil {
+			p.logger.Debug("failed to push block", "peer", id, "height", block.header.Height(), "error", err)
+			_ = stream.Reset()
+			stream = nil
+		}
+	}
+}
+
+// DirectBlockStreamHandler returns handler of streams with blocks pushed by the aggregator, which passes received
+// blocks to the sync loop.
+//
+// Streams are accepted only from trusted senders (the aggregator), and only blocks signed by the expected sequencer,
+// with data matching the header, are accepted.
+func (m *Manager) DirectBlockStreamHandler(senders []peer.ID) network.StreamHandler {
+	trusted := make(map[peer.ID]struct{}, len(senders))
+	for _, id := range senders {
+		trusted[id] = struct{}{}
+	}
+	return func(stream network.Stream) {
+		if _, ok := trusted[stream.Conn().RemotePeer()]; !ok {
+			m.logger.Debug("rejecting direct block stream from untrusted peer", "peer", stream.Conn().RemotePeer())
+			_ = stream.Reset()
+			return
+		}
+		m.handleDirectBlockStream(stream)
+	}
+}
+
+func (m *Manager) handleDirectBlockStream(stream network.Stream) {
+	defer stream.Close() //nolint:errcheck
+	from := stream.Conn().RemotePeer()
+	reader := bufio.NewReader(stream)
+	for {
+		header, data, err := unmarshalBlock(reader, m.blockPartLimit())
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				m.logger.Debug("failed to read directly pushed block", "peer", from, "error", err)
+				_ = stream.Reset()
+			}
+			return
+		}
+		if err := m.validateReceivedBlock(header, data); err != nil {
+			m.logger.Debug("rejecting directly pushed block", "peer", from, "height", header.Height(), "error", err)
+			_ = stream.Reset()
+			return
+		}
+		m.logger.Debug("block received directly", "peer", from, "height", header.Height())
+		m.sendReceivedBlock(header, data)
+	}
+}
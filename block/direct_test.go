@@ -0,0 +1,97 @@
+package block
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestDirectBlockPush(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chainID := "TestDirectBlockPush"
+	genesis, privKey := types.GetGenesisWithPrivkey(types.DefaultSigningKeyType, chainID)
+	m := getManager(t, nil)
+	m.genesis = genesis
+	m.headerInCh = make(chan NewHeaderEvent, 1)
+	m.dataInCh = make(chan NewDataEvent, 1)
+
+	mn, err := mocknet.FullMeshLinked(2)
+	require.NoError(err)
+	aggregator, fullNode := mn.Hosts()[0], mn.Hosts()[1]
+	fullNode.SetStreamHandler(DirectBlockProtocolID(chainID), m.DirectBlockStreamHandler([]peer.ID{aggregator.ID()}))
+
+	pusher := NewDirectBlockPusher(aggregator, chainID, []peer.AddrInfo{{ID: fullNode.ID(), Addrs: fullNode.Addrs()}}, m.logger)
+	pusher.Start(ctx)
+
+	header, data, _ := types.GenerateRandomBlockCustom(&types.BlockConfig{Height: 1, NTxs: 2, PrivKey: privKey}, chainID)
+	pusher.Push(header, data)
+
+	select {
+	case evt := <-m.headerInCh:
+		assert.Equal(header.Hash(), evt.Header.Hash())
+	case <-time.After(5 * time.Second):
+		t.Fatal("header not received")
+	}
+	select {
+	case evt := <-m.dataInCh:
+		assert.Equal(data.Hash(), evt.Data.Hash())
+	case <-time.After(5 * time.Second):
+		t.Fatal("data not received")
+	}
+
+	// block from unexpected proposer is rejected
+	header, data = types.GetRandomBlock(2, 2, chainID)
+	pusher.Push(header, data)
+	select {
+	case <-m.headerInCh:
+		t.Fatal("block from unexpected proposer accepted")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	cancel()
+	pusher.Wait()
+}
+
+func TestDirectBlockPushUntrustedPeer(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chainID := "TestDirectBlockPushUntrustedPeer"
+	genesis, privKey := types.GetGenesisWithPrivkey(types.DefaultSigningKeyType, chainID)
+	m := getManager(t, nil)
+	m.genesis = genesis
+	m.headerInCh = make(chan NewHeaderEvent, 1)
+	m.dataInCh = make(chan NewDataEvent, 1)
+
+	mn, err := mocknet.FullMeshLinked(3)
+	require.NoError(err)
+	aggregator, fullNode, untrusted := mn.Hosts()[0], mn.Hosts()[1], mn.Hosts()[2]
+	fullNode.SetStreamHandler(DirectBlockProtocolID(chainID), m.DirectBlockStreamHandler([]peer.ID{aggregator.ID()}))
+
+	pusher := NewDirectBlockPusher(untrusted, chainID, []peer.AddrInfo{{ID: fullNode.ID(), Addrs: fullNode.Addrs()}}, m.logger)
+	pusher.Start(ctx)
+
+	// even correctly signed block is rejected if it's not pushed by trusted peer
+	header, data, _ := types.GenerateRandomBlockCustom(&types.BlockConfig{Height: 1, NTxs: 2, PrivKey: privKey}, chainID)
+	pusher.Push(header, data)
+	select {
+	case <-m.headerInCh:
+		t.Fatal("block from untrusted peer accepted")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	cancel()
+	pusher.Wait()
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -58,9 +59,11 @@ const defaultMempoolTTL = 25
 // see: https://gist.github.com/tuxcanfly/80892dde9cdbe89bfb57a6cb3c27bae2
 const blockProtocolOverhead = 1 << 16
 
-// maxSubmitAttempts defines how many times Rollkit will re-try to publish block to DA layer.
-// This is temporary solution. It will be removed in future versions.
-const maxSubmitAttempts = 30
+// defaultSubmitMaxAttempts is used only if DASubmitMaxAttempts is not configured for manager
+const defaultSubmitMaxAttempts = 30
+
+// defaultDAPrefetchWindow is used only if DAPrefetchWindow is not configured for manager
+const defaultDAPrefetchWindow = 16
 
 // Applies to most channels, 100 is a large enough buffer to avoid blocking
 const channelLength = 100
@@ -68,12 +71,16 @@ const channelLength = 100
 // Applies to the headerInCh, 10000 is a large enough number for headers per DA block.
 const headerInChLength = 10000
 
-// initialBackoff defines initial value for block submission backoff
-var initialBackoff = 100 * time.Millisecond
+// defaultInitialBackoff is used only if DAInitialBackoff is not configured for manager
+const defaultInitialBackoff = 100 * time.Millisecond
 
 // DAIncludedHeightKey is the key used for persisting the da included height in store.
 const DAIncludedHeightKey = "da included height"
 
+// LastSubmittedDataHeightKey is the key used for persisting the height of the last block with data posted to the DA
+// data namespace in store.
+const LastSubmittedDataHeightKey = "last submitted data height"
+
 // dataHashForEmptyTxs to be used while only syncing headers from DA and no p2p to get the Data for no txs scenarios, the syncing can proceed without getting stuck forever.
 var dataHashForEmptyTxs = []byte{110, 52, 11, 156, 255, 179, 122, 152, 156, 165, 68, 230, 187, 120, 10, 44, 120, 144, 29, 63, 179, 55, 56, 118, 133, 17, 163, 6, 23, 175, 160, 29}
 
@@ -119,6 +126,15 @@ type Manager struct {
 	HeaderCh chan *types.SignedHeader
 	DataCh   chan *types.Data
 
+	// directPusher pushes produced blocks directly to trusted full nodes (optional)
+	directPusher *DirectBlockPusher
+	// blockGossiper publishes produced blocks on the combined block topic (optional)
+	blockGossiper BlockGossiper
+	// dataStoreWriter writes data of synced blocks to the data store (optional)
+	dataStoreWriter DataStoreWriter
+	// maxBlobSize is the DA max blob size, limiting size of blocks received from peers
+	maxBlobSize uint64
+
 	headerInCh  chan NewHeaderEvent
 	headerStore *goheaderstore.Store[*types.SignedHeader]
 
@@ -143,6 +159,14 @@ type Manager struct {
 	buildingBlock bool
 
 	pendingHeaders *PendingHeaders
+	// lastSubmission is the latest attempt to submit headers to DA
+	lastSubmission atomic.Pointer[DASubmission]
+
+	// lastSubmittedDataHeight is the height of the last block with data posted to the DA data namespace.
+	lastSubmittedDataHeight atomic.Uint64
+
+	// daHead tracks the latest produced DA height, limiting DA heights retrieved concurrently
+	daHead *daHeadTracker
 
 	// for reporting metrics
 	metrics *Metrics
@@ -233,6 +257,31 @@ func NewManager(
 		conf.DAMempoolTTL = defaultMempoolTTL
 	}
 
+	if conf.DASubmitMaxAttempts == 0 {
+		logger.Info("Using default DA submit max attempts", "DASubmitMaxAttempts", defaultSubmitMaxAttempts)
+		conf.DASubmitMaxAttempts = defaultSubmitMaxAttempts
+	}
+
+	if conf.DAInitialBackoff == 0 {
+		logger.Info("Using default DA initial backoff", "DAInitialBackoff", defaultInitialBackoff)
+		conf.DAInitialBackoff = defaultInitialBackoff
+	}
+
+	if conf.DAMaxBackoff == 0 {
+		logger.Info("Using DA block time as DA max backoff", "DAMaxBackoff", conf.DABlockTime)
+		conf.DAMaxBackoff = conf.DABlockTime
+	}
+
+	if conf.DARetrieveMaxBackoff == 0 {
+		logger.Info("Using DA block time as DA retrieve max backoff", "DARetrieveMaxBackoff", conf.DABlockTime)
+		conf.DARetrieveMaxBackoff = conf.DABlockTime
+	}
+
+	if conf.DAPrefetchWindow == 0 {
+		logger.Info("Using default DA prefetch window", "DAPrefetchWindow", defaultDAPrefetchWindow)
+		conf.DAPrefetchWindow = defaultDAPrefetchWindow
+	}
+
 	proposerAddress := s.Validators.Proposer.Address.Bytes()
 
 	maxBlobSize, err := dalc.DA.MaxBlobSize(context.Background())
@@ -296,6 +345,8 @@ func NewManager(
 		isProposer:     isProposer,
 		seqClient:      seqClient,
 		bq:             NewBatchQueue(),
+		daHead:         newDAHeadTracker(conf.DABlockTime),
+		maxBlobSize:    maxBlobSize + blockProtocolOverhead,
 	}
 	agg.init(context.Background())
 	return agg, nil
@@ -306,6 +357,10 @@ func (m *Manager) init(ctx context.Context) {
 	if height, err := m.store.GetMetadata(ctx, DAIncludedHeightKey); err == nil && len(height) == 8 {
 		m.daIncludedHeight.Store(binary.BigEndian.Uint64(height))
 	}
+	// initialize height of the last block with data posted to DA, so that data is not posted again after restart
+	if height, err := m.store.GetMetadata(ctx, LastSubmittedDataHeightKey); err == nil && len(height) == 8 {
+		m.lastSubmittedDataHeight.Store(binary.BigEndian.Uint64(height))
+	}
 }
 
 func (m *Manager) setDAIncludedHeight(ctx context.Context, newHeight uint64) error {
@@ -329,6 +384,28 @@ func (m *Manager) GetDAIncludedHeight() uint64 {
 	return m.daIncludedHeight.Load()
 }
 
+// saveDAInclusionProof persists DA inclusion proof of i-th block submitted to DA, together with DA endpoints that
+// included it.
+func (m *Manager) saveDAInclusionProof(ctx context.Context, height uint64, res da.ResultSubmit, i int) error {
+	if i >= len(res.IDs) {
+		return nil
+	}
+	proof := &types.DAInclusionProof{
+		Height:     height,
+		DAHeight:   binary.LittleEndian.Uint64(res.IDs[i]),
+		Namespace:  m.dalc.HeaderNamespace,
+		ID:         res.IDs[i],
+		Inclusions: res.Inclusions,
+	}
+	if res.Commitments != nil {
+		proof.Commitment = res.Commitments[i]
+	}
+	if res.Proofs != nil {
+		proof.Proof = res.Proofs[i]
+	}
+	return m.store.SaveDAInclusionProof(ctx, height, proof)
+}
+
 // SetDALC is used to set DataAvailabilityLayerClient used by Manager.
 func (m *Manager) SetDALC(dalc *da.DAClient) {
 	m.dalc = dalc
@@ -360,6 +437,12 @@ func (m *Manager) GetStoreHeight() uint64 {
 	return m.store.Height()
 }
 
+// SetDirectBlockPusher sets pusher used to send produced blocks directly to trusted full nodes.
+// It must be called before block production is started.
+func (m *Manager) SetDirectBlockPusher(p *DirectBlockPusher) {
+	m.directPusher = p
+}
+
 // GetHeaderInCh returns the manager's blockInCh
 func (m *Manager) GetHeaderInCh() chan NewHeaderEvent {
 	return m.headerInCh
@@ -661,7 +744,10 @@ func (m *Manager) SyncLoop(ctx context.Context, cancel context.CancelFunc) {
 				m.logger.Debug("data already seen", "height", dataHeight, "data hash", dataHash)
 				continue
 			}
-			m.dataCache.setData(dataHeight, data)
+			if !m.cacheData(dataHeight, data) {
+				m.logger.Debug("skipping data not matching the header", "height", dataHeight, "data hash", dataHash)
+				continue
+			}
 
 			m.sendNonBlockingSignalToDataStoreCh()
 
@@ -677,6 +763,22 @@ func (m *Manager) SyncLoop(ctx context.Context, cancel context.CancelFunc) {
 	}
 }
 
+// cacheData caches block data of given height, unless the header of that height is already cached and data doesn't
+// match it, or cached data already matches it. Data is not signed, so data received before the header is replaced by
+// later data, but never displaces data matching a verified header.
+func (m *Manager) cacheData(height uint64, data *types.Data) bool {
+	if header := m.headerCache.getHeader(height); header != nil {
+		if types.Validate(header, data) != nil {
+			return false
+		}
+		if cached := m.dataCache.getData(height); cached != nil && types.Validate(header, cached) == nil {
+			return false
+		}
+	}
+	m.dataCache.setData(height, data)
+	return true
+}
+
 func (m *Manager) sendNonBlockingSignalToHeaderStoreCh() {
 	select {
 	case m.headerStoreCh <- struct{}{}:
@@ -753,6 +855,7 @@ func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
 
 		// Height gets updated
 		m.store.SetHeight(ctx, hHeight)
+		m.writeSyncedData(ctx, d)
 
 		if daHeight > newState.DAHeight {
 			newState.DAHeight = daHeight
@@ -882,6 +985,11 @@ func (m *Manager) RetrieveLoop(ctx context.Context) {
 	// This enables syncing faster than the DA block time.
 	headerFoundCh := make(chan struct{}, 1)
 	defer close(headerFoundCh)
+	// window is the number of DA heights retrieved concurrently. It grows while all heights
+	// are retrieved successfully (i.e. node is catching up) and drops back to 1 on failure or
+	// after reaching the DA head. It's also capped at the estimated DA head, to avoid hammering
+	// DA with requests for heights that are not produced yet.
+	window := uint64(1)
 	for {
 		select {
 		case <-ctx.Done():
@@ -890,9 +998,16 @@ func (m *Manager) RetrieveLoop(ctx context.Context) {
 		case <-headerFoundCh:
 		}
 		daHeight := atomic.LoadUint64(&m.daHeight)
-		err := m.processNextDAHeader(ctx)
+		err := m.processNextDAHeaders(ctx, window)
+		if errors.Is(err, da.ErrHeightFromFuture) {
+			// caught up with DA, wait for the next DA block
+			m.logger.Debug("reached DA head", "daHeight", atomic.LoadUint64(&m.daHeight))
+			window = 1
+			continue
+		}
 		if err != nil && ctx.Err() == nil {
-			m.logger.Error("failed to retrieve block from DALC", "daHeight", daHeight, "errors", err.Error())
+			m.logger.Error("failed to retrieve block from DALC", "daHeight", daHeight, "window", window, "errors", err.Error())
+			window = 1
 			continue
 		}
 		// Signal the blockFoundCh to try and retrieve the next block
@@ -900,79 +1015,174 @@ func (m *Manager) RetrieveLoop(ctx context.Context) {
 		case headerFoundCh <- struct{}{}:
 		default:
 		}
-		atomic.AddUint64(&m.daHeight, 1)
+		window = min(window*2, m.conf.DAPrefetchWindow)
 	}
 }
 
-func (m *Manager) processNextDAHeader(ctx context.Context) error {
+// daHeadersResult is the outcome of retrieving headers (and block data, if posted to separate namespace) from a
+// single DA height.
+type daHeadersResult struct {
+	resp     da.ResultRetrieveHeaders
+	dataResp da.ResultRetrieveData
+	err      error
+}
+
+// processNextDAHeaders retrieves window consecutive DA heights, starting at m.daHeight, concurrently and
+// processes them in order. The window is capped at the estimated DA head. m.daHeight is advanced after each
+// processed height; retrieval stops at the first height that could not be retrieved, or that is not produced yet
+// (reported with da.ErrHeightFromFuture).
+func (m *Manager) processNextDAHeaders(ctx context.Context, window uint64) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	// TODO(tzdybal): extract configuration option
-	maxRetries := 10
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	daHeight := atomic.LoadUint64(&m.daHeight)
+	window = m.daHead.limitWindow(daHeight, window)
+	m.logger.Debug("trying to retrieve blocks from DA", "daHeight", daHeight, "window", window)
+	results := make([]chan daHeadersResult, window)
+	for i := range results {
+		// buffered, so that workers never block after processing was aborted
+		results[i] = make(chan daHeadersResult, 1)
+		go func(daHeight uint64, resultCh chan<- daHeadersResult) {
+			var res daHeadersResult
+			res.resp, res.err = fetchWithRetry(fetchCtx, m, daHeight, m.fetchHeaders)
+			if res.err == nil && m.dalc.SeparateDataNamespace() {
+				res.dataResp, res.err = fetchWithRetry(fetchCtx, m, daHeight, m.fetchData)
+			}
+			resultCh <- res
+		}(daHeight+uint64(i), results[i])
+	}
 
-	var err error
-	m.logger.Debug("trying to retrieve block from DA", "daHeight", daHeight)
-	for r := 0; r < maxRetries; r++ {
+	for i, resultCh := range results {
+		var res daHeadersResult
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		default:
+		case res = <-resultCh:
 		}
-		headerResp, fetchErr := m.fetchHeaders(ctx, daHeight)
-		if fetchErr == nil {
-			if headerResp.Code == da.StatusNotFound {
-				m.logger.Debug("no header found", "daHeight", daHeight, "reason", headerResp.Message)
-				return nil
-			}
-			m.logger.Debug("retrieved potential headers", "n", len(headerResp.Headers), "daHeight", daHeight)
-			for _, header := range headerResp.Headers {
-				// early validation to reject junk headers
-				if !m.isUsingExpectedCentralizedSequencer(header) {
-					m.logger.Debug("skipping header from unexpected sequencer",
-						"headerHeight", header.Height(),
-						"headerHash", header.Hash().String())
-					continue
-				}
-				blockHash := header.Hash().String()
-				m.headerCache.setDAIncluded(blockHash)
-				err = m.setDAIncludedHeight(ctx, header.Height())
-				if err != nil {
-					return err
-				}
-				m.logger.Info("block marked as DA included", "blockHeight", header.Height(), "blockHash", blockHash)
-				if !m.headerCache.isSeen(blockHash) {
-					// Check for shut down event prior to logging
-					// and sending block to blockInCh. The reason
-					// for checking for the shutdown event
-					// separately is due to the inconsistent nature
-					// of the select statement when multiple cases
-					// are satisfied.
-					select {
-					case <-ctx.Done():
-						return pkgErrors.WithMessage(ctx.Err(), "unable to send block to blockInCh, context done")
-					default:
-					}
-					m.headerInCh <- NewHeaderEvent{header, daHeight}
-				}
+		if errors.Is(res.err, da.ErrHeightFromFuture) {
+			m.daHead.observeFromFuture(daHeight + uint64(i))
+			return res.err
+		}
+		if res.err != nil {
+			if ctx.Err() == nil {
+				m.metrics.DARetrieveRetriesExhausted.Add(1)
 			}
-			return nil
+			return res.err
+		}
+		if err := m.processDAHeaders(ctx, daHeight+uint64(i), res.resp); err != nil {
+			return err
 		}
+		if err := m.processDAData(ctx, daHeight+uint64(i), res.dataResp); err != nil {
+			return err
+		}
+		m.daHead.observe(daHeight + uint64(i))
+		atomic.AddUint64(&m.daHeight, 1)
+	}
+	return nil
+}
 
+// fetchWithRetry retrieves blobs from given DA height using fetch, retrying up to DARetrieveMaxRetries times with
+// exponential backoff (capped at DARetrieveMaxBackoff) on failure. Heights that are not produced yet are not retried.
+func fetchWithRetry[T any](ctx context.Context, m *Manager, daHeight uint64, fetch func(context.Context, uint64) (T, error)) (T, error) {
+	var (
+		zero    T
+		errs    error
+		backoff time.Duration
+	)
+	resp, err := fetch(ctx, daHeight)
+	for r := uint64(0); r < m.conf.DARetrieveMaxRetries && err != nil && !errors.Is(err, da.ErrHeightFromFuture); r++ {
 		// Track the error
-		err = errors.Join(err, fetchErr)
+		errs = errors.Join(errs, err)
 		// Delay before retrying
+		backoff = m.exponentialBackoff(backoff, m.conf.DARetrieveMaxBackoff)
 		select {
 		case <-ctx.Done():
+			return zero, errors.Join(errs, ctx.Err())
+		case <-time.After(m.withJitter(backoff)):
+		}
+		resp, err = fetch(ctx, daHeight)
+	}
+	switch {
+	case err == nil:
+		return resp, nil
+	case errors.Is(err, da.ErrHeightFromFuture):
+		return zero, err
+	}
+	return zero, errors.Join(errs, err)
+}
+
+// processDAHeaders marks headers retrieved from given DA height as DA included and passes unseen ones to headerInCh.
+func (m *Manager) processDAHeaders(ctx context.Context, daHeight uint64, headerResp da.ResultRetrieveHeaders) error {
+	if headerResp.Code == da.StatusNotFound {
+		m.logger.Debug("no header found", "daHeight", daHeight, "reason", headerResp.Message)
+		return nil
+	}
+	m.logger.Debug("retrieved potential headers", "n", len(headerResp.Headers), "daHeight", daHeight)
+	for _, header := range headerResp.Headers {
+		// early validation to reject junk headers
+		if !m.isUsingExpectedCentralizedSequencer(header) {
+			m.logger.Debug("skipping header from unexpected sequencer",
+				"headerHeight", header.Height(),
+				"headerHash", header.Hash().String())
+			continue
+		}
+		blockHash := header.Hash().String()
+		m.headerCache.setDAIncluded(blockHash)
+		err := m.setDAIncludedHeight(ctx, header.Height())
+		if err != nil {
 			return err
-		case <-time.After(100 * time.Millisecond):
+		}
+		m.logger.Info("block marked as DA included", "blockHeight", header.Height(), "blockHash", blockHash)
+		if !m.headerCache.isSeen(blockHash) {
+			// Check for shut down event prior to logging
+			// and sending block to blockInCh. The reason
+			// for checking for the shutdown event
+			// separately is due to the inconsistent nature
+			// of the select statement when multiple cases
+			// are satisfied.
+			select {
+			case <-ctx.Done():
+				return pkgErrors.WithMessage(ctx.Err(), "unable to send block to blockInCh, context done")
+			default:
+			}
+			m.headerInCh <- NewHeaderEvent{header, daHeight}
 		}
 	}
-	return err
+	return nil
+}
+
+// processDAData passes block data retrieved from given DA height to dataInCh. Data blobs are not signed, so data of
+// other chains and data not matching an already known header are skipped; remaining data is validated against the
+// header by the sync loop, and doesn't replace cached data matching it (see cacheData).
+func (m *Manager) processDAData(ctx context.Context, daHeight uint64, dataResp da.ResultRetrieveData) error {
+	if dataResp.Code == da.StatusNotFound {
+		m.logger.Debug("no block data found", "daHeight", daHeight, "reason", dataResp.Message)
+		return nil
+	}
+	for _, data := range dataResp.Data {
+		if data.Metadata == nil || data.ChainID() != m.genesis.ChainID {
+			continue
+		}
+		if header := m.headerCache.getHeader(data.Height()); header != nil && types.Validate(header, data) != nil {
+			m.logger.Debug("skipping block data not matching the header", "height", data.Height(), "daHeight", daHeight)
+			continue
+		}
+		if data.Height() <= m.store.Height() || m.dataCache.isSeen(data.Hash().String()) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return pkgErrors.WithMessage(ctx.Err(), "unable to send block data to dataInCh, context done")
+		case m.dataInCh <- NewDataEvent{data, daHeight}:
+		}
+	}
+	return nil
 }
 
 func (m *Manager) isUsingExpectedCentralizedSequencer(header *types.SignedHeader) bool {
@@ -982,12 +1192,27 @@ func (m *Manager) isUsingExpectedCentralizedSequencer(header *types.SignedHeader
 func (m *Manager) fetchHeaders(ctx context.Context, daHeight uint64) (da.ResultRetrieveHeaders, error) {
 	var err error
 	headerRes := m.dalc.RetrieveHeaders(ctx, daHeight)
-	if headerRes.Code == da.StatusError {
+	switch headerRes.Code {
+	case da.StatusHeightFromFuture:
+		err = fmt.Errorf("%w: %d", da.ErrHeightFromFuture, daHeight)
+	case da.StatusError:
 		err = fmt.Errorf("failed to retrieve block: %s", headerRes.Message)
 	}
 	return headerRes, err
 }
 
+func (m *Manager) fetchData(ctx context.Context, daHeight uint64) (da.ResultRetrieveData, error) {
+	var err error
+	dataRes := m.dalc.RetrieveData(ctx, daHeight)
+	switch dataRes.Code {
+	case da.StatusHeightFromFuture:
+		err = fmt.Errorf("%w: %d", da.ErrHeightFromFuture, daHeight)
+	case da.StatusError:
+		err = fmt.Errorf("failed to retrieve block data: %s", dataRes.Message)
+	}
+	return dataRes, err
+}
+
 func (m *Manager) getSignature(header types.Header) (*types.Signature, error) {
 	// note: for compatibility with tendermint light client
 	consensusVote := header.MakeCometBFTVote()
@@ -1195,6 +1420,14 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	default:
 	}
 
+	// Push block directly to trusted full nodes, before it's gossiped
+	if m.directPusher != nil {
+		m.directPusher.Push(header, data)
+	}
+	if m.blockGossiper != nil {
+		m.gossipBlock(ctx, header, data)
+	}
+
 	// Publish header to channel so that header exchange service can broadcast
 	m.HeaderCh <- header
 
@@ -1311,38 +1544,49 @@ func (m *Manager) submitHeadersToDA(ctx context.Context) error {
 		m.logger.Error("error while fetching blocks pending DA", "err", err)
 	}
 	numSubmittedHeaders := 0
-	attempt := 0
+	attempt := uint64(0)
 	maxBlobSize, err := m.dalc.DA.MaxBlobSize(ctx)
 	if err != nil {
 		return err
 	}
+	if m.dalc.SeparateDataNamespace() {
+		if err := m.submitDataToDA(ctx, headersToSubmit, maxBlobSize); err != nil {
+			return err
+		}
+	}
 	initialMaxBlobSize := maxBlobSize
 	initialGasPrice := m.dalc.GasPrice
 	gasPrice := m.dalc.GasPrice
 
 daSubmitRetryLoop:
-	for !submittedAllHeaders && attempt < maxSubmitAttempts {
+	for !submittedAllHeaders && attempt < m.conf.DASubmitMaxAttempts {
 		select {
 		case <-ctx.Done():
 			break daSubmitRetryLoop
-		case <-time.After(backoff):
+		case <-time.After(m.withJitter(backoff)):
 		}
 
 		res := m.dalc.SubmitHeaders(ctx, headersToSubmit, maxBlobSize, gasPrice)
+		m.recordSubmission(res, gasPrice, attempt+1)
 		switch res.Code {
 		case da.StatusSuccess:
 			m.logger.Info("successfully submitted Rollkit headers to DA layer", "gasPrice", gasPrice, "daHeight", res.DAHeight, "headerCount", res.SubmittedCount)
+			m.daHead.observe(res.DAHeight)
 			if res.SubmittedCount == uint64(len(headersToSubmit)) {
 				submittedAllHeaders = true
 			}
 			submittedBlocks, notSubmittedBlocks := headersToSubmit[:res.SubmittedCount], headersToSubmit[res.SubmittedCount:]
 			numSubmittedHeaders += len(submittedBlocks)
-			for _, block := range submittedBlocks {
+			for i, block := range submittedBlocks {
 				m.headerCache.setDAIncluded(block.Hash().String())
 				err = m.setDAIncludedHeight(ctx, block.Height())
 				if err != nil {
 					return err
 				}
+				err = m.saveDAInclusionProof(ctx, block.Height(), res, i)
+				if err != nil {
+					return err
+				}
 			}
 			lastSubmittedHeight := uint64(0)
 			if l := len(submittedBlocks); l > 0 {
@@ -1374,13 +1618,16 @@ daSubmitRetryLoop:
 			fallthrough
 		default:
 			m.logger.Error("DA layer submission failed", "error", res.Message, "attempt", attempt)
-			backoff = m.exponentialBackoff(backoff)
+			backoff = m.exponentialBackoff(backoff, m.conf.DAMaxBackoff)
 		}
 
 		attempt += 1
 	}
 
 	if !submittedAllHeaders {
+		if ctx.Err() == nil {
+			m.metrics.DASubmitRetriesExhausted.Add(1)
+		}
 		return fmt.Errorf(
 			"failed to submit all blocks to DA layer, submitted %d blocks (%d left) after %d attempts",
 			numSubmittedHeaders,
@@ -1391,17 +1638,100 @@ daSubmitRetryLoop:
 	return nil
 }
 
-func (m *Manager) exponentialBackoff(backoff time.Duration) time.Duration {
+// submitDataToDA posts data of blocks with given headers to the DA data namespace. It's called before headers are
+// submitted, so that nodes following DA find data of every DA included block. Data of blocks without transactions
+// is not posted, as it's recreated from the header.
+func (m *Manager) submitDataToDA(ctx context.Context, headers []*types.SignedHeader, maxBlobSize uint64) error {
+	var dataToSubmit []*types.Data
+	for _, header := range headers {
+		if header.Height() <= m.lastSubmittedDataHeight.Load() || bytes.Equal(header.DataHash, dataHashForEmptyTxs) {
+			continue
+		}
+		_, data, err := m.store.GetBlockData(ctx, header.Height())
+		if err != nil {
+			return fmt.Errorf("failed to load block data: %w", err)
+		}
+		dataToSubmit = append(dataToSubmit, data)
+	}
+
+	var backoff time.Duration
+	gasPrice := m.dalc.GasPrice
+	for attempt := uint64(0); len(dataToSubmit) > 0 && attempt < m.conf.DASubmitMaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.withJitter(backoff)):
+		}
+
+		res := m.dalc.SubmitData(ctx, dataToSubmit, maxBlobSize, gasPrice)
+		switch res.Code {
+		case da.StatusSuccess:
+			m.logger.Info("successfully submitted block data to DA layer", "gasPrice", gasPrice, "daHeight", res.DAHeight, "dataCount", res.SubmittedCount)
+			m.setLastSubmittedDataHeight(ctx, dataToSubmit[res.SubmittedCount-1].Height())
+			dataToSubmit = dataToSubmit[res.SubmittedCount:]
+			backoff = 0
+		case da.StatusNotIncludedInBlock, da.StatusAlreadyInMempool:
+			m.logger.Error("DA layer data submission failed", "error", res.Message, "attempt", attempt)
+			backoff = m.conf.DABlockTime * time.Duration(m.conf.DAMempoolTTL) //nolint:gosec
+			if m.dalc.GasMultiplier > 0 && gasPrice != -1 {
+				gasPrice = gasPrice * m.dalc.GasMultiplier
+			}
+		case da.StatusTooBig:
+			maxBlobSize = maxBlobSize / 4
+			fallthrough
+		default:
+			m.logger.Error("DA layer data submission failed", "error", res.Message, "attempt", attempt)
+			backoff = m.exponentialBackoff(backoff, m.conf.DAMaxBackoff)
+		}
+	}
+
+	if len(dataToSubmit) > 0 {
+		if ctx.Err() == nil {
+			m.metrics.DASubmitRetriesExhausted.Add(1)
+		}
+		return fmt.Errorf("failed to submit block data to DA layer, %d blocks left", len(dataToSubmit))
+	}
+	if l := len(headers); l > 0 {
+		m.setLastSubmittedDataHeight(ctx, headers[l-1].Height())
+	}
+	return nil
+}
+
+// setLastSubmittedDataHeight advances the height of the last block with data posted to DA, and persists it.
+func (m *Manager) setLastSubmittedDataHeight(ctx context.Context, height uint64) {
+	if height <= m.lastSubmittedDataHeight.Load() {
+		return
+	}
+	m.lastSubmittedDataHeight.Store(height)
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, height)
+	if err := m.store.SetMetadata(ctx, LastSubmittedDataHeightKey, heightBytes); err != nil {
+		// data of blocks submitted since the last persisted height is submitted again after restart
+		m.logger.Error("failed to store height of latest block data submitted to DA", "err", err)
+	}
+}
+
+// exponentialBackoff doubles the delay, starting at DAInitialBackoff, up to maxBackoff.
+func (m *Manager) exponentialBackoff(backoff, maxBackoff time.Duration) time.Duration {
 	backoff *= 2
 	if backoff == 0 {
-		backoff = initialBackoff
+		backoff = m.conf.DAInitialBackoff
 	}
-	if backoff > m.conf.DABlockTime {
-		backoff = m.conf.DABlockTime
+	if backoff > maxBackoff {
+		backoff = maxBackoff
 	}
 	return backoff
 }
 
+// withJitter randomly shifts delay by up to DABackoffJitter fraction of its value, in both directions.
+func (m *Manager) withJitter(delay time.Duration) time.Duration {
+	if m.conf.DABackoffJitter <= 0 || delay <= 0 {
+		return delay
+	}
+	jitter := float64(delay) * m.conf.DABackoffJitter
+	return delay + time.Duration(jitter*(2*rand.Float64()-1)) //nolint:gosec
+}
+
 func (m *Manager) getLastStateValidators() *cmtypes.ValidatorSet {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
@@ -66,6 +66,16 @@ func getManager(t *testing.T, backend goDA.DA) *Manager {
 		dalc:        da.NewDAClient(backend, -1, -1, nil, nil, logger),
 		headerCache: NewHeaderCache(),
 		logger:      logger,
+		conf: config.BlockManagerConfig{
+			DASubmitMaxAttempts:  defaultSubmitMaxAttempts,
+			DARetrieveMaxRetries: config.DefaultNodeConfig.DARetrieveMaxRetries,
+			DAInitialBackoff:     defaultInitialBackoff,
+			DAMaxBackoff:         defaultInitialBackoff,
+			DARetrieveMaxBackoff: defaultInitialBackoff,
+			DAPrefetchWindow:     defaultDAPrefetchWindow,
+		},
+		metrics: NopMetrics(),
+		daHead:  newDAHeadTracker(time.Second),
 	}
 }
 
@@ -312,12 +322,28 @@ func TestSubmitBlocksToMockDA(t *testing.T) {
 			mockDA.
 				On("Submit", mock.Anything, blobs, tc.expectedGasPrices[2], []byte(nil)).
 				Return([][]byte{bytes.Repeat([]byte{0x00}, 8)}, nil)
+			mockDA.On("Commit", mock.Anything, blobs, []byte(nil)).Return([][]byte{{0x01}}, nil)
+			mockDA.On("GetProofs", mock.Anything, mock.Anything, []byte(nil)).Return([][]byte{{0x02}}, nil)
 
 			m.pendingHeaders, err = NewPendingHeaders(m.store, m.logger)
 			require.NoError(t, err)
 			err = m.submitHeadersToDA(ctx)
 			require.NoError(t, err)
 			mockDA.AssertExpectations(t)
+
+			proof, err := m.store.GetDAInclusionProof(ctx, 1)
+			require.NoError(t, err)
+			assert.EqualValues(t, []byte{0x01}, proof.Commitment)
+			assert.EqualValues(t, []byte{0x02}, proof.Proof)
+
+			status := m.DAStatus()
+			assert.Equal(t, uint64(1), status.DAIncludedHeight)
+			assert.Zero(t, status.PendingHeaders)
+			require.NotNil(t, status.LastSubmission)
+			assert.Equal(t, "success", status.LastSubmission.Status)
+			assert.Equal(t, uint64(1), status.LastSubmission.SubmittedCount)
+			assert.Equal(t, tc.expectedGasPrices[2], status.LastSubmission.GasPrice)
+			assert.Equal(t, uint64(3), status.LastSubmission.Attempt)
 		})
 	}
 }
@@ -511,6 +537,8 @@ func Test_submitBlocksToDA_BlockMarshalErrorCase2(t *testing.T) {
 	store.On("SetMetadata", ctx, DAIncludedHeightKey, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}).Return(nil)
 	store.On("SetMetadata", ctx, DAIncludedHeightKey, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02}).Return(nil)
 	store.On("SetMetadata", ctx, LastSubmittedHeightKey, []byte(strconv.FormatUint(2, 10))).Return(nil)
+	store.On("SaveDAInclusionProof", ctx, uint64(1), mock.Anything).Return(nil)
+	store.On("SaveDAInclusionProof", ctx, uint64(2), mock.Anything).Return(nil)
 	store.On("GetMetadata", ctx, LastSubmittedHeightKey).Return(nil, ds.ErrNotFound)
 	store.On("GetBlockData", ctx, uint64(1)).Return(header1, data1, nil)
 	store.On("GetBlockData", ctx, uint64(2)).Return(header2, data2, nil)
@@ -621,6 +649,197 @@ func Test_isProposer(t *testing.T) {
 	}
 }
 
+func TestExponentialBackoffWithJitter(t *testing.T) {
+	assert := assert.New(t)
+	m := &Manager{conf: config.BlockManagerConfig{
+		DAInitialBackoff: 100 * time.Millisecond,
+		DAMaxBackoff:     time.Second,
+		DABackoffJitter:  0.5,
+	}}
+
+	backoff := m.exponentialBackoff(0, m.conf.DAMaxBackoff)
+	assert.Equal(100*time.Millisecond, backoff)
+	backoff = m.exponentialBackoff(backoff, m.conf.DAMaxBackoff)
+	assert.Equal(200*time.Millisecond, backoff)
+	for i := 0; i < 5; i++ {
+		backoff = m.exponentialBackoff(backoff, m.conf.DAMaxBackoff)
+	}
+	assert.Equal(time.Second, backoff)
+	// retrieval backoff is fixed if its maximum equals initial backoff
+	assert.Equal(100*time.Millisecond, m.exponentialBackoff(100*time.Millisecond, 100*time.Millisecond))
+
+	for i := 0; i < 100; i++ {
+		delay := m.withJitter(backoff)
+		assert.GreaterOrEqual(delay, 500*time.Millisecond)
+		assert.LessOrEqual(delay, 1500*time.Millisecond)
+	}
+	assert.Zero(m.withJitter(0))
+}
+
+func TestProcessNextDAHeaderRetriesExhausted(t *testing.T) {
+	mockDA := &goDAMock.MockDA{}
+	mockDA.On("GetIDs", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("DA unavailable"))
+	m := getManager(t, mockDA)
+	m.conf.DARetrieveMaxRetries = 3
+	m.conf.DAInitialBackoff = time.Millisecond
+	m.conf.DARetrieveMaxBackoff = time.Millisecond
+
+	err := m.processNextDAHeaders(context.Background(), 1)
+	require.ErrorContains(t, err, "DA unavailable")
+	// first attempt and 3 retries
+	mockDA.AssertNumberOfCalls(t, "GetIDs", 4)
+
+	// retries can be disabled
+	m.conf.DARetrieveMaxRetries = 0
+	err = m.processNextDAHeaders(context.Background(), 1)
+	require.ErrorContains(t, err, "DA unavailable")
+	mockDA.AssertNumberOfCalls(t, "GetIDs", 5)
+}
+
+func TestProcessNextDAHeadersWindow(t *testing.T) {
+	mockDA := &goDAMock.MockDA{}
+	// first height is the slowest one, but it still has to be processed first
+	mockDA.On("GetIDs", mock.Anything, uint64(1), mock.Anything).Return(&goDA.GetIDsResult{}, nil).After(50 * time.Millisecond)
+	mockDA.On("GetIDs", mock.Anything, uint64(2), mock.Anything).Return(&goDA.GetIDsResult{}, nil)
+	mockDA.On("GetIDs", mock.Anything, uint64(3), mock.Anything).Return(nil, errors.New("DA unavailable"))
+	mockDA.On("GetIDs", mock.Anything, uint64(4), mock.Anything).Return(&goDA.GetIDsResult{}, nil)
+	m := getManager(t, mockDA)
+	m.conf.DARetrieveMaxRetries = 1
+	m.daHeight = 1
+
+	err := m.processNextDAHeaders(context.Background(), 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, m.daHeight)
+
+	// processing stops at the first height that can't be retrieved
+	err = m.processNextDAHeaders(context.Background(), 2)
+	require.ErrorContains(t, err, "DA unavailable")
+	require.EqualValues(t, 3, m.daHeight)
+}
+
+func TestProcessNextDAHeadersFromFuture(t *testing.T) {
+	mockDA := &goDAMock.MockDA{}
+	mockDA.On("GetIDs", mock.Anything, uint64(1), mock.Anything).Return(&goDA.GetIDsResult{}, nil)
+	mockDA.On("GetIDs", mock.Anything, uint64(2), mock.Anything).Return(nil, fmt.Errorf("%w: header", da.ErrHeightFromFuture))
+	m := getManager(t, mockDA)
+	m.daHeight = 1
+
+	require.NoError(t, m.processNextDAHeaders(context.Background(), 1))
+	require.EqualValues(t, 2, m.daHeight)
+
+	// height that is not produced yet stops processing without retries
+	err := m.processNextDAHeaders(context.Background(), 1)
+	require.ErrorIs(t, err, da.ErrHeightFromFuture)
+	require.EqualValues(t, 2, m.daHeight)
+	mockDA.AssertNumberOfCalls(t, "GetIDs", 2)
+
+	// window is capped at DA head
+	head, ok := m.daHead.estimate()
+	require.True(t, ok)
+	assert.EqualValues(t, 1, head)
+	assert.EqualValues(t, 1, m.daHead.limitWindow(2, 16))
+}
+
+func TestSubmitDataToSeparateNamespace(t *testing.T) {
+	ctx := context.Background()
+	mockDA := &goDAMock.MockDA{}
+	m := getManager(t, mockDA)
+	m.dalc.HeaderNamespace = []byte("header")
+	m.dalc.DataNamespace = []byte("data")
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	m.store = store.New(kvStore)
+
+	header1, data1 := types.GetRandomBlock(1, 5, "TestSubmitDataToSeparateNamespace")
+	header2, data2 := types.GetRandomBlock(2, 0, "TestSubmitDataToSeparateNamespace")
+	header2.DataHash = dataHashForEmptyTxs
+	for _, block := range []struct {
+		header *types.SignedHeader
+		data   *types.Data
+	}{{header1, data1}, {header2, data2}} {
+		require.NoError(t, m.store.SaveBlockData(ctx, block.header, block.data, &types.Signature{}))
+	}
+	m.store.SetHeight(ctx, 2)
+	m.pendingHeaders, err = NewPendingHeaders(m.store, m.logger)
+	require.NoError(t, err)
+
+	// only data of block with transactions is posted
+	dataBlob, err := data1.MarshalBinary()
+	require.NoError(t, err)
+	mockDA.On("MaxBlobSize", mock.Anything).Return(uint64(12345), nil)
+	mockDA.On("Submit", mock.Anything, [][]byte{dataBlob}, float64(-1), []byte("data")).Return([][]byte{bytes.Repeat([]byte{0x00}, 8)}, nil).Once()
+	mockDA.On("Submit", mock.Anything, mock.Anything, float64(-1), []byte("header")).Return([][]byte{bytes.Repeat([]byte{0x01}, 8), bytes.Repeat([]byte{0x01}, 8)}, nil).Once()
+	mockDA.On("Commit", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not supported"))
+	mockDA.On("GetProofs", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not supported"))
+
+	require.NoError(t, m.submitHeadersToDA(ctx))
+	mockDA.AssertExpectations(t)
+	assert.EqualValues(t, 2, m.lastSubmittedDataHeight.Load())
+	assert.EqualValues(t, 2, m.GetDAIncludedHeight())
+
+	// height of the last submitted data is restored after restart
+	m.lastSubmittedDataHeight.Store(0)
+	m.init(ctx)
+	assert.EqualValues(t, 2, m.lastSubmittedDataHeight.Load())
+}
+
+func TestProcessNextDAHeadersData(t *testing.T) {
+	chainID := "TestProcessNextDAHeadersData"
+	mockDA := &goDAMock.MockDA{}
+	m := getManager(t, mockDA)
+	m.dalc.HeaderNamespace = []byte("header")
+	m.dalc.DataNamespace = []byte("data")
+	m.genesis = &cmtypes.GenesisDoc{ChainID: chainID}
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	m.store = store.New(kvStore)
+	m.dataCache = NewDataCache()
+	m.dataInCh = make(chan NewDataEvent, 2)
+	m.daHeight = 1
+
+	_, data := types.GetRandomBlock(1, 2, chainID)
+	_, otherChainData := types.GetRandomBlock(1, 2, "other")
+	var blobs [][]byte
+	for _, d := range []*types.Data{data, otherChainData} {
+		blob, err := d.MarshalBinary()
+		require.NoError(t, err)
+		blobs = append(blobs, blob)
+	}
+	ids := []goDA.ID{{1}, {2}}
+	mockDA.On("GetIDs", mock.Anything, uint64(1), []byte("header")).Return(&goDA.GetIDsResult{}, nil)
+	mockDA.On("GetIDs", mock.Anything, uint64(1), []byte("data")).Return(&goDA.GetIDsResult{IDs: ids}, nil)
+	mockDA.On("Get", mock.Anything, ids, []byte("data")).Return(blobs, nil)
+
+	require.NoError(t, m.processNextDAHeaders(context.Background(), 1))
+	require.Len(t, m.dataInCh, 1)
+	evt := <-m.dataInCh
+	assert.Equal(t, data.Hash(), evt.Data.Hash())
+	assert.EqualValues(t, 1, evt.DAHeight)
+}
+
+func TestCacheData(t *testing.T) {
+	m := getManager(t, &goDAMock.MockDA{})
+	m.dataCache = NewDataCache()
+	header, data := types.GetRandomBlock(1, 2, "TestCacheData")
+	_, other := types.GetRandomBlock(1, 2, "TestCacheData")
+
+	// without the header, later data replaces earlier one
+	assert.True(t, m.cacheData(1, other))
+	assert.True(t, m.cacheData(1, data))
+	assert.Equal(t, data, m.dataCache.getData(1))
+
+	// data matching the header is not replaced
+	m.headerCache.setHeader(1, header)
+	assert.False(t, m.cacheData(1, other))
+	assert.False(t, m.cacheData(1, data))
+	assert.Equal(t, data, m.dataCache.getData(1))
+
+	// data not matching the header is replaced only by matching data
+	m.dataCache.setData(1, other)
+	assert.True(t, m.cacheData(1, data))
+	assert.Equal(t, data, m.dataCache.getData(1))
+}
+
 func Test_publishBlock_ManagerNotProposer(t *testing.T) {
 	require := require.New(t)
 	m := getManager(t, &goDAMock.MockDA{})
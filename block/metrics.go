@@ -26,6 +26,10 @@ type Metrics struct {
 	TotalTxs metrics.Gauge
 	// The latest block height.
 	CommittedHeight metrics.Gauge `metrics_name:"latest_block_height"`
+	// Number of times all attempts to submit headers to DA failed.
+	DASubmitRetriesExhausted metrics.Counter
+	// Number of times all attempts to retrieve a DA height failed.
+	DARetrieveRetriesExhausted metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -67,6 +71,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "latest_block_height",
 			Help:      "The latest block height.",
 		}, labels).With(labelsAndValues...),
+		DASubmitRetriesExhausted: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_submit_retries_exhausted",
+			Help:      "Number of times all attempts to submit headers to DA failed.",
+		}, labels).With(labelsAndValues...),
+		DARetrieveRetriesExhausted: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_retrieve_retries_exhausted",
+			Help:      "Number of times all attempts to retrieve a DA height failed.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -78,5 +94,8 @@ func NopMetrics() *Metrics {
 		BlockSizeBytes:  discard.NewGauge(),
 		TotalTxs:        discard.NewGauge(),
 		CommittedHeight: discard.NewGauge(),
+
+		DASubmitRetriesExhausted:   discard.NewCounter(),
+		DARetrieveRetriesExhausted: discard.NewCounter(),
 	}
 }
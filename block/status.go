@@ -0,0 +1,82 @@
+package block
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rollkit/rollkit/da"
+)
+
+// DASubmission describes an attempt to submit block headers to DA layer.
+type DASubmission struct {
+	// Time is when the DA layer responded.
+	Time time.Time `json:"time"`
+	// Status is the DA layer status code, e.g. "success" or "already_in_mempool".
+	Status string `json:"status"`
+	// Message contains error details, if submission failed.
+	Message string `json:"message,omitempty"`
+	// DAHeight is the DA height including submitted headers.
+	DAHeight uint64 `json:"da_height"`
+	// SubmittedCount is the number of submitted headers.
+	SubmittedCount uint64 `json:"submitted_count"`
+	// GasPrice is the gas price used for submission.
+	GasPrice float64 `json:"gas_price"`
+	// Attempt is the number of the attempt to submit pending headers, starting from 1.
+	Attempt uint64 `json:"attempt"`
+}
+
+// DAStatus describes progress of publishing blocks to DA layer and retrieving blocks from it.
+type DAStatus struct {
+	// DAIncludedHeight is the height up to which all blocks are included in DA layer.
+	DAIncludedHeight uint64 `json:"da_included_height"`
+	// PendingHeaders is the number of block headers waiting for submission to DA layer.
+	PendingHeaders uint64 `json:"pending_headers"`
+	// SyncDAHeight is the DA height being retrieved by the node.
+	SyncDAHeight uint64 `json:"sync_da_height"`
+	// LastSubmission is the latest attempt to submit headers. It's nil until node submits headers to DA layer.
+	LastSubmission *DASubmission `json:"last_submission,omitempty"`
+}
+
+// SequencerStatus describes block production of the node.
+type SequencerStatus struct {
+	// Aggregator is set if node runs in aggregator mode. It's not known by the Manager.
+	Aggregator bool `json:"aggregator"`
+	// LazyAggregator is set if blocks are produced only when there are transactions.
+	LazyAggregator bool `json:"lazy_aggregator"`
+	// Proposer is set if node's signing key is the proposer key of the chain.
+	Proposer bool `json:"proposer"`
+	// BatchQueueLength is the number of transaction batches received from sequencer, that are not in blocks yet.
+	BatchQueueLength uint64 `json:"batch_queue_length"`
+}
+
+// DAStatus returns current status of DA submission and retrieval.
+func (m *Manager) DAStatus() DAStatus {
+	return DAStatus{
+		DAIncludedHeight: m.GetDAIncludedHeight(),
+		PendingHeaders:   m.pendingHeaders.numPendingHeaders(),
+		SyncDAHeight:     atomic.LoadUint64(&m.daHeight),
+		LastSubmission:   m.lastSubmission.Load(),
+	}
+}
+
+// SequencerStatus returns current status of block production.
+func (m *Manager) SequencerStatus() SequencerStatus {
+	return SequencerStatus{
+		LazyAggregator:   m.conf.LazyAggregator,
+		Proposer:         m.isProposer,
+		BatchQueueLength: uint64(m.bq.Len()), //nolint:gosec
+	}
+}
+
+// recordSubmission saves the result of submission attempt, reported by DAStatus.
+func (m *Manager) recordSubmission(res da.ResultSubmit, gasPrice float64, attempt uint64) {
+	m.lastSubmission.Store(&DASubmission{
+		Time:           time.Now(),
+		Status:         res.Code.String(),
+		Message:        res.Message,
+		DAHeight:       res.DAHeight,
+		SubmittedCount: res.SubmittedCount,
+		GasPrice:       gasPrice,
+		Attempt:        attempt,
+	})
+}
@@ -90,7 +90,7 @@ func newSyncService[H header.Header[H]](store ds.TxnDatastore, syncType syncType
 		return nil, fmt.Errorf("failed to initialize the %s store: %w", syncType, err)
 	}
 
-	return &SyncService[H]{
+	syncService := &SyncService[H]{
 		conf:         conf,
 		genesis:      genesis,
 		p2p:          p2p,
@@ -98,7 +98,9 @@ func newSyncService[H header.Header[H]](store ds.TxnDatastore, syncType syncType
 		syncType:     syncType,
 		logger:       logger,
 		syncerStatus: new(SyncerStatus),
-	}, nil
+	}
+	p2p.AddSyncTopic(goheaderp2p.PubsubTopicID(syncService.getChainID()))
+	return syncService, nil
 }
 
 // Store returns the store of the SyncService
@@ -121,19 +123,19 @@ func (syncService *SyncService[H]) initStoreAndStartSyncer(ctx context.Context,
 
 // WriteToStoreAndBroadcast initializes store if needed and broadcasts  provided header or block.
 // Note: Only returns an error in case store can't be initialized. Logs error if there's one while broadcasting.
+//
+// The genesis header or block is written to the store by WriteToStore, which initializes the store and starts the
+// syncer. Later ones are written to the store by the syncer, once they pass local validation of the broadcast; they
+// can't be appended before broadcasting, as validation rejects known headers, and rejected messages are not
+// published to peers.
 func (syncService *SyncService[H]) WriteToStoreAndBroadcast(ctx context.Context, headerOrData H) error {
 	if syncService.genesis.InitialHeight < 0 {
 		return fmt.Errorf("invalid initial height; cannot be negative")
 	}
 	isGenesis := headerOrData.Height() == uint64(syncService.genesis.InitialHeight)
-	// For genesis header/block initialize the store and start the syncer
 	if isGenesis {
-		if err := syncService.store.Init(ctx, headerOrData); err != nil {
-			return fmt.Errorf("failed to initialize the store")
-		}
-
-		if err := syncService.StartSyncer(ctx); err != nil {
-			return fmt.Errorf("failed to start syncer after initializing the store")
+		if err := syncService.WriteToStore(ctx, headerOrData); err != nil {
+			return err
 		}
 	}
 
@@ -150,6 +152,26 @@ func (syncService *SyncService[H]) WriteToStoreAndBroadcast(ctx context.Context,
 	return nil
 }
 
+// WriteToStore initializes store if needed and appends provided header or block to the store, without broadcasting it.
+func (syncService *SyncService[H]) WriteToStore(ctx context.Context, headerOrData H) error {
+	if syncService.genesis.InitialHeight < 0 {
+		return fmt.Errorf("invalid initial height; cannot be negative")
+	}
+	if headerOrData.Height() == uint64(syncService.genesis.InitialHeight) {
+		if err := syncService.store.Init(ctx, headerOrData); err != nil {
+			return fmt.Errorf("failed to initialize the store")
+		}
+		if err := syncService.StartSyncer(ctx); err != nil {
+			return fmt.Errorf("failed to start syncer after initializing the store")
+		}
+		return nil
+	}
+	if err := syncService.store.Append(ctx, headerOrData); err != nil {
+		return fmt.Errorf("failed to append to the store: %w", err)
+	}
+	return nil
+}
+
 func (syncService *SyncService[H]) isInitialized() bool {
 	return syncService.store.Height() > 0
 }
@@ -199,7 +221,7 @@ func (syncService *SyncService[H]) setupP2P(ctx context.Context) ([]peer.ID, err
 	}
 	networkID := syncService.getNetworkID(network)
 
-	if syncService.p2pServer, err = newP2PServer(syncService.p2p.Host(), syncService.store, networkID); err != nil {
+	if syncService.p2pServer, err = newP2PServer(syncService.p2p.ExchangeServerHost(), syncService.store, networkID); err != nil {
 		return nil, fmt.Errorf("error while creating p2p server: %w", err)
 	}
 	if err := syncService.p2pServer.Start(ctx); err != nil {
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/p2p"
+)
+
+const (
+	// defaultNodeRPCAddress is the default address of node RPC used by client commands.
+	defaultNodeRPCAddress = "tcp://127.0.0.1:26657"
+
+	// p2pDiagTimeout is the time limit for querying node for diagnostics.
+	p2pDiagTimeout = 10 * time.Second
+)
+
+// NewP2PCmd creates a new cobra command group for inspecting P2P networking of a running node.
+func NewP2PCmd() *cobra.Command {
+	p2pCmd := &cobra.Command{
+		Use:     "p2p",
+		Short:   "P2P networking operations",
+		Long:    `This command group is used to inspect P2P networking of a running node, using its RPC.`,
+		Example: `  rollkit p2p diag --node tcp://127.0.0.1:26657`,
+	}
+
+	p2pCmd.AddCommand(newP2PDiagCmd())
+
+	return p2pCmd
+}
+
+func newP2PDiagCmd() *cobra.Command {
+	var (
+		node    string
+		asJSON  bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "diag",
+		Short: "Show P2P network health diagnostics of a running node",
+		Long: `Show DHT routing table size, connected peers and GossipSub mesh peers of every topic,
+peers that recently sent invalid messages and NAT reachability status of a running node.
+The node must have unsafe RPC methods enabled (--rpc.unsafe).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			diag, err := queryP2PDiagnostics(ctx, node)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(diag)
+			}
+			return printP2PDiagnostics(cmd.OutOrStdout(), diag)
+		},
+	}
+	cmd.Flags().StringVar(&node, "node", defaultNodeRPCAddress, "RPC address of the node")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print diagnostics as JSON")
+	cmd.Flags().DurationVar(&timeout, "timeout", p2pDiagTimeout, "time limit for querying the node")
+	return cmd
+}
+
+func queryP2PDiagnostics(ctx context.Context, node string) (*p2p.Diagnostics, error) {
+	client, err := rpcclient.New(node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node address: %w", err)
+	}
+	var diag p2p.Diagnostics
+	if _, err := client.Call(ctx, "p2p_diag", map[string]interface{}{}, &diag); err != nil {
+		return nil, fmt.Errorf("failed to query node: %w", err)
+	}
+	return &diag, nil
+}
+
+func printP2PDiagnostics(out io.Writer, diag *p2p.Diagnostics) error {
+	w := tabwriter.NewWriter(out, 2, 0, 2, ' ', 0)
+	var errs []error
+	printf := func(format string, a ...interface{}) {
+		_, err := fmt.Fprintf(w, format, a...)
+		errs = append(errs, err)
+	}
+
+	printf("Node ID:\t%s\n", diag.ID)
+	for i, addr := range diag.ListenAddrs {
+		if i == 0 {
+			printf("Listen addresses:\t%s\n", addr)
+		} else {
+			printf("\t%s\n", addr)
+		}
+	}
+	printf("NAT reachability:\t%s\n", diag.Reachability)
+	printf("DHT routing table size:\t%d\n", diag.DHTRoutingTableSize)
+	printf("Connected peers:\t%d\n", diag.ConnectedPeers)
+
+	printf("\nTOPIC\tPEERS\tMESH\n")
+	for _, topic := range diag.Topics {
+		printf("%s\t%d\t%d\n", topic.Topic, len(topic.Peers), len(topic.Mesh))
+	}
+	for _, topic := range diag.Topics {
+		if len(topic.Mesh) == 0 {
+			continue
+		}
+		printf("\nMesh of %s:\n", topic.Topic)
+		for _, p := range topic.Mesh {
+			printf("  %s\n", p)
+		}
+	}
+
+	if len(diag.ValidationFailures) == 0 {
+		printf("\nNo recent validation failures.\n")
+	} else {
+		printf("\nPEER\tINVALID MESSAGES\tSINCE\n")
+		for _, f := range diag.ValidationFailures {
+			printf("%s\t%d\t%s\n", f.Peer, f.Count, f.Since.Format(time.RFC3339))
+		}
+	}
+	return errors.Join(append(errs, w.Flush())...)
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cmjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/p2p"
+)
+
+func TestP2PDiag(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ids := make([]peer.ID, 2)
+	for i := range ids {
+		_, pubKey, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		ids[i], err = peer.IDFromPublicKey(pubKey)
+		require.NoError(err)
+	}
+	expected := p2p.Diagnostics{
+		ID:                  ids[0],
+		ListenAddrs:         []string{"/ip4/127.0.0.1/tcp/7676"},
+		Reachability:        "Public",
+		DHTRoutingTableSize: 3,
+		ConnectedPeers:      1,
+		Topics: []p2p.TopicDiagnostics{
+			{Topic: "chain-tx", Peers: []peer.ID{ids[1]}, Mesh: []peer.ID{ids[1]}},
+		},
+		ValidationFailures: []p2p.ValidationFailures{
+			{Peer: ids[1], Count: 2, Since: time.Now().UTC().Truncate(time.Second)},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal("p2p_diag", req.Method)
+		result, err := cmjson.Marshal(expected)
+		require.NoError(err)
+		require.NoError(json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  json.RawMessage(result),
+		}))
+	}))
+	defer srv.Close()
+
+	cmd := NewP2PCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"diag", "--node", srv.URL, "--json"})
+	require.NoError(cmd.Execute())
+	var actual p2p.Diagnostics
+	require.NoError(json.Unmarshal(out.Bytes(), &actual))
+	assert.Equal(expected.ID, actual.ID)
+	assert.Equal(expected.Topics, actual.Topics)
+	assert.Equal(expected.ValidationFailures[0].Peer, actual.ValidationFailures[0].Peer)
+	assert.True(expected.ValidationFailures[0].Since.Equal(actual.ValidationFailures[0].Since))
+
+	out.Reset()
+	cmd = NewP2PCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"diag", "--node", srv.URL})
+	require.NoError(cmd.Execute())
+	assert.Contains(out.String(), "NAT reachability:        Public")
+	assert.Contains(out.String(), "Mesh of chain-tx:\n  "+ids[1].String())
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	rollconf "github.com/rollkit/rollkit/config"
+	rollnode "github.com/rollkit/rollkit/node"
+)
+
+// NewReindexCmd returns the command that rebuilds transaction and block indexes from stored blocks.
+func NewReindexCmd() *cobra.Command {
+	var from, to uint64
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild transaction and block indexes from stored blocks",
+		Long: `Rebuild transaction and block indexes of blocks in the given height range, using blocks and
+block results persisted in the node's store. Blocks are not re-executed. The node must be stopped.`,
+		Example: `  rollkit reindex --from 100 --to 200`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parseConfig(cmd); err != nil {
+				return err
+			}
+			rollconf.GetNodeConfig(&nodeConfig, config)
+			return rollnode.Reindex(cmd.Context(), nodeConfig, from, to, logger.With("module", "reindex"))
+		},
+	}
+
+	cmd.Flags().Uint64Var(&from, "from", 1, "first height to reindex")
+	cmd.Flags().Uint64Var(&to, "to", 0, "last height to reindex (0 means the latest height)")
+	addNodeFlags(cmd)
+
+	return cmd
+}
@@ -180,7 +180,7 @@ func NewRunNodeCmd() *cobra.Command {
 			}
 
 			// Launch the RPC server
-			server := rollrpc.NewServer(rollnode, config.RPC, logger)
+			server := rollrpc.NewServer(rollnode, config.RPC, nodeConfig.RPC, logger)
 			err = server.Start()
 			if err != nil {
 				return fmt.Errorf("failed to launch RPC server: %w", err)
@@ -22,6 +22,8 @@ func main() {
 		cmd.VersionCmd,
 		cmd.NewTomlCmd(),
 		cmd.RebuildCmd,
+		cmd.NewP2PCmd(),
+		cmd.NewReindexCmd(),
 	)
 
 	// In case there is a rollkit.toml file in the current dir or somewhere up the
@@ -50,6 +50,14 @@ func TestViperAndCobra(t *testing.T) {
 	assert.NoError(cmd.Flags().Set(FlagDAAddress, `{"json":true}`))
 	assert.NoError(cmd.Flags().Set(FlagBlockTime, "1234s"))
 	assert.NoError(cmd.Flags().Set(FlagDANamespace, "0102030405060708"))
+	assert.NoError(cmd.Flags().Set(FlagP2PAllowedPeers, "/ip4/127.0.0.1/tcp/7676/p2p/12D3KooWM1NFkZozoatQi3JvFE57eBaX56mNgBA68Lk5MTPxBE4U"))
+	assert.NoError(cmd.Flags().Set(FlagP2PAllowlistOnly, "true"))
+	assert.NoError(cmd.Flags().Set(FlagTxIndexerExclude, "message,transfer.sender"))
+	assert.NoError(cmd.Flags().Set(FlagBlockIndexerMode, IndexerModeNull))
+	assert.NoError(cmd.Flags().Set(FlagRPCAuthToken, "secret"))
+	assert.NoError(cmd.Flags().Set(FlagRPCPublicMethods, "health,status"))
+	assert.NoError(cmd.Flags().Set(FlagRPCRequestRate, "2.5"))
+	assert.NoError(cmd.Flags().Set(FlagRPCGRPCListenAddress, "tcp://127.0.0.1:9090"))
 
 	nc := DefaultNodeConfig
 
@@ -58,4 +66,14 @@ func TestViperAndCobra(t *testing.T) {
 	assert.Equal(true, nc.Aggregator)
 	assert.Equal(`{"json":true}`, nc.DAAddress)
 	assert.Equal(1234*time.Second, nc.BlockTime)
+	assert.Equal("/ip4/127.0.0.1/tcp/7676/p2p/12D3KooWM1NFkZozoatQi3JvFE57eBaX56mNgBA68Lk5MTPxBE4U", nc.P2P.AllowedPeers)
+	assert.True(nc.P2P.AllowlistOnly)
+	assert.Equal(IndexerModeKV, nc.Indexer.Tx.Mode)
+	assert.Equal("message,transfer.sender", nc.Indexer.Tx.Exclude)
+	assert.Equal(IndexerModeNull, nc.Indexer.Block.Mode)
+	assert.Equal("secret", nc.RPC.AuthToken)
+	assert.Equal("health,status", nc.RPC.PublicMethods)
+	assert.Empty(nc.RPC.AuthenticatedMethods)
+	assert.Equal(2.5, nc.RPC.RequestRate)
+	assert.Equal("tcp://127.0.0.1:9090", nc.RPC.GRPCListenAddress)
 }
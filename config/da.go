@@ -0,0 +1,79 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var (
+	errNamespaceLength    = errors.New("all DA namespaces must have the same length")
+	errNamespaceCollision = errors.New("DA namespaces for headers, data and forced inclusion transactions must be distinct")
+)
+
+// DANamespaces holds decoded DA namespaces for all kinds of blobs.
+type DANamespaces struct {
+	Header          []byte
+	Data            []byte
+	ForcedInclusion []byte
+}
+
+// GetDANamespaces decodes configured DA namespaces.
+//
+// DANamespace is used for every kind of blob without dedicated namespace configured.
+func (nc NodeConfig) GetDANamespaces() (DANamespaces, error) {
+	var (
+		namespaces DANamespaces
+		err        error
+	)
+	if namespaces.Header, err = decodeNamespace(FlagDAHeaderNamespace, nc.DAHeaderNamespace, nc.DANamespace); err != nil {
+		return namespaces, err
+	}
+	if namespaces.Data, err = decodeNamespace(FlagDADataNamespace, nc.DADataNamespace, nc.DANamespace); err != nil {
+		return namespaces, err
+	}
+	if namespaces.ForcedInclusion, err = decodeNamespace(FlagDAForcedInclusionNamespace, nc.DAForcedInclusionNamespace, nc.DANamespace); err != nil {
+		return namespaces, err
+	}
+	return namespaces, nil
+}
+
+// validateDANamespaces checks that configured DA namespaces are valid hex strings of the same length, that header
+// and data namespaces are distinct if any of them is configured explicitly, and that explicitly configured forced
+// inclusion namespace differs from both of them.
+func (nc NodeConfig) validateDANamespaces() error {
+	namespaces, err := nc.GetDANamespaces()
+	if err != nil {
+		return err
+	}
+	length := 0
+	for _, ns := range [][]byte{namespaces.Header, namespaces.Data, namespaces.ForcedInclusion} {
+		if len(ns) == 0 {
+			continue
+		}
+		if length != 0 && len(ns) != length {
+			return errNamespaceLength
+		}
+		length = len(ns)
+	}
+	if (nc.DAHeaderNamespace != "" || nc.DADataNamespace != "") && bytes.Equal(namespaces.Header, namespaces.Data) {
+		return errNamespaceCollision
+	}
+	if nc.DAForcedInclusionNamespace != "" &&
+		(bytes.Equal(namespaces.ForcedInclusion, namespaces.Header) || bytes.Equal(namespaces.ForcedInclusion, namespaces.Data)) {
+		return errNamespaceCollision
+	}
+	return nil
+}
+
+func decodeNamespace(flag, namespace, fallback string) ([]byte, error) {
+	if namespace == "" {
+		namespace = fallback
+	}
+	decoded, err := hex.DecodeString(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", flag, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDANamespaces(t *testing.T) {
+	t.Parallel()
+
+	nc := NodeConfig{DANamespace: "0102", DADataNamespace: "0304"}
+	namespaces, err := nc.GetDANamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, namespaces.Header)
+	assert.Equal(t, []byte{3, 4}, namespaces.Data)
+	assert.Equal(t, []byte{1, 2}, namespaces.ForcedInclusion)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	initialBackoffOnly := DefaultNodeConfig
+	initialBackoffOnly.DAInitialBackoff = time.Second
+
+	cases := []struct {
+		name        string
+		conf        NodeConfig
+		expectedErr string
+	}{
+		{"default", NodeConfig{DANamespace: "0102"}, ""},
+		{"empty", NodeConfig{}, ""},
+		{"dedicated", NodeConfig{DAHeaderNamespace: "0102", DADataNamespace: "0304", DAForcedInclusionNamespace: "0506"}, ""},
+		{"forced inclusion only", NodeConfig{DANamespace: "0102", DAForcedInclusionNamespace: "0506"}, ""},
+		{"data only", NodeConfig{DANamespace: "0102", DADataNamespace: "0304"}, ""},
+		{"invalid hex", NodeConfig{DADataNamespace: "zz"}, "error decoding " + FlagDADataNamespace},
+		{"length mismatch", NodeConfig{DANamespace: "0102", DADataNamespace: "030405"}, errNamespaceLength.Error()},
+		{"forced inclusion invalid hex", NodeConfig{DAForcedInclusionNamespace: "zz"}, "error decoding " + FlagDAForcedInclusionNamespace},
+		{"forced inclusion length mismatch", NodeConfig{DANamespace: "0102", DAForcedInclusionNamespace: "030405"}, errNamespaceLength.Error()},
+		{"forced inclusion collision with header", NodeConfig{DANamespace: "0102", DAForcedInclusionNamespace: "0102"}, errNamespaceCollision.Error()},
+		{"forced inclusion collision with data", NodeConfig{DAHeaderNamespace: "0102", DADataNamespace: "0304", DAForcedInclusionNamespace: "0304"}, errNamespaceCollision.Error()},
+		{"collision", NodeConfig{DAHeaderNamespace: "0102", DADataNamespace: "0102"}, errNamespaceCollision.Error()},
+		{"collision with default", NodeConfig{DANamespace: "0102", DADataNamespace: "0102"}, errNamespaceCollision.Error()},
+		{"header collision with default", NodeConfig{DANamespace: "0102", DAHeaderNamespace: "0102"}, errNamespaceCollision.Error()},
+		{"negative gas multiplier", NodeConfig{DAGasMultiplier: -1}, "gas multiplier"},
+		{"jitter out of range", NodeConfig{BlockManagerConfig: BlockManagerConfig{DABackoffJitter: 1.5}}, "jitter"},
+		{"max backoff too low", NodeConfig{BlockManagerConfig: BlockManagerConfig{DAInitialBackoff: time.Second, DAMaxBackoff: time.Millisecond}}, "max backoff"},
+		{"initial backoff only", initialBackoffOnly, ""},
+		{"send rate too low", NodeConfig{P2P: P2PConfig{MaxSendRate: MinSendRate - 1}}, "max send rate"},
+		{"send rate per peer too low", NodeConfig{P2P: P2PConfig{MaxSendRatePerPeer: 1}}, "max send rate per peer"},
+		{"minimum send rates", NodeConfig{P2P: P2PConfig{MaxSendRate: MinSendRate, MaxSendRatePerPeer: MinSendRate}}, ""},
+		{"retrieve max backoff too low", NodeConfig{BlockManagerConfig: BlockManagerConfig{DAInitialBackoff: time.Second, DARetrieveMaxBackoff: time.Millisecond}}, "retrieve max backoff"},
+		{"invalid indexer mode", NodeConfig{Indexer: IndexerConfig{Tx: EventIndexConfig{Mode: "sql"}}}, "tx indexer: invalid mode"},
+		{"psql", NodeConfig{Indexer: IndexerConfig{Tx: EventIndexConfig{Mode: IndexerModePsql}, Block: EventIndexConfig{Mode: IndexerModePsql}, PsqlConn: "postgresql://localhost/db"}}, ""},
+		{"psql tx only", NodeConfig{Indexer: IndexerConfig{Tx: EventIndexConfig{Mode: IndexerModePsql}, PsqlConn: "postgresql://localhost/db"}}, "requires psql block indexer"},
+		{"negative RPC request rate", NodeConfig{RPC: RPCConfig{RequestRate: -1}}, "request rate"},
+		{"RPC public methods", NodeConfig{RPC: RPCConfig{AuthToken: "secret", PublicMethods: "health"}}, ""},
+		{"RPC public methods without auth", NodeConfig{RPC: RPCConfig{PublicMethods: "health"}}, "require RPC auth"},
+		{"psql without connection", NodeConfig{Indexer: IndexerConfig{Block: EventIndexConfig{Mode: IndexerModePsql}}}, "connection string"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.conf.Validate()
+			if c.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, c.expectedErr)
+		})
+	}
+}
@@ -25,18 +25,32 @@ var DefaultNodeConfig = NodeConfig{
 	P2P: P2PConfig{
 		ListenAddress: DefaultListenAddress,
 		Seeds:         "",
+		BanThreshold:  10,
+		BanDuration:   1 * time.Hour,
+	},
+	Indexer: IndexerConfig{
+		Tx:    EventIndexConfig{Mode: IndexerModeKV},
+		Block: EventIndexConfig{Mode: IndexerModeKV},
 	},
 	Aggregator: false,
 	BlockManagerConfig: BlockManagerConfig{
-		BlockTime:      1 * time.Second,
-		DABlockTime:    15 * time.Second,
-		LazyAggregator: false,
-		LazyBlockTime:  60 * time.Second,
+		BlockTime:            1 * time.Second,
+		DABlockTime:          15 * time.Second,
+		LazyAggregator:       false,
+		LazyBlockTime:        60 * time.Second,
+		DASubmitMaxAttempts:  30,
+		DARetrieveMaxRetries: 10,
+		DAInitialBackoff:     100 * time.Millisecond,
+		DABackoffJitter:      0.1,
+		DAPrefetchWindow:     16,
 	},
-	DAAddress:       DefaultDAAddress,
-	DAGasPrice:      -1,
-	DAGasMultiplier: 0,
-	Light:           false,
+	DAAddress:             DefaultDAAddress,
+	DAGasPrice:            -1,
+	DAGasMultiplier:       0,
+	DASubmitTimeout:       60 * time.Second,
+	DARetrieveTimeout:     60 * time.Second,
+	MempoolBroadcastPeers: 0,
+	Light:                 false,
 	HeaderConfig: HeaderConfig{
 		TrustedHash: "",
 	},
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// IndexerModeKV indexes events in the node's key-value store.
+	IndexerModeKV = "kv"
+	// IndexerModePsql indexes events in PostgreSQL database.
+	IndexerModePsql = "psql"
+	// IndexerModeNull disables indexing.
+	IndexerModeNull = "null"
+)
+
+// IndexerConfig stores configuration of transaction and block indexing.
+type IndexerConfig struct {
+	Tx    EventIndexConfig // Indexing of transaction events
+	Block EventIndexConfig // Indexing of block (FinalizeBlock) events
+
+	PsqlConn string // PostgreSQL connection string used in "psql" mode
+}
+
+// EventIndexConfig selects events indexed by an indexer. Only attributes flagged for indexing by the application
+// are indexed. Filters contain event types (e.g. "transfer") or event attribute keys (e.g. "transfer.recipient").
+type EventIndexConfig struct {
+	Mode    string // Indexer mode: "kv" (default), "psql" or "null" (indexing disabled)
+	Include string // Comma separated list of event types and attribute keys to index; empty means all
+	Exclude string // Comma separated list of event types and attribute keys never indexed
+}
+
+// Validate checks indexer configuration for invalid values.
+func (c IndexerConfig) Validate() error {
+	if err := c.Tx.validate(); err != nil {
+		return fmt.Errorf("tx indexer: %w", err)
+	}
+	if err := c.Block.validate(); err != nil {
+		return fmt.Errorf("block indexer: %w", err)
+	}
+	if c.Tx.Mode == IndexerModePsql && c.Block.Mode != IndexerModePsql {
+		return errors.New("psql tx indexer requires psql block indexer")
+	}
+	if (c.Tx.Mode == IndexerModePsql || c.Block.Mode == IndexerModePsql) && c.PsqlConn == "" {
+		return errors.New("psql indexer requires PostgreSQL connection string")
+	}
+	return nil
+}
+
+func (c EventIndexConfig) validate() error {
+	switch c.Mode {
+	case "", IndexerModeKV, IndexerModePsql, IndexerModeNull:
+		return nil
+	}
+	return fmt.Errorf("invalid mode %q, must be %q, %q or %q", c.Mode, IndexerModeKV, IndexerModePsql, IndexerModeNull)
+}
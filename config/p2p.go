@@ -1,9 +1,51 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
+// MinSendRate is the lowest non-zero rate (bytes/s) accepted for MaxSendRate and MaxSendRatePerPeer. Data is
+// throttled in chunks of 16 KiB, and a chunk that would wait for limiters longer than 10 seconds fails, so with lower
+// rates every write would fail.
+const MinSendRate = 2 << 10
+
 // P2PConfig stores configuration related to peer-to-peer networking.
 type P2PConfig struct {
-	ListenAddress string // Address to listen for incoming connections
-	Seeds         string // Comma separated list of seed nodes to connect to
-	BlockedPeers  string // Comma separated list of nodes to ignore
-	AllowedPeers  string // Comma separated list of nodes to whitelist
+	ListenAddress string        // Address to listen for incoming connections
+	Seeds         string        // Comma separated list of seed nodes to connect to
+	BlockedPeers  string        // Comma separated list of nodes to ignore
+	AllowedPeers  string        // Comma separated list of nodes to whitelist
+	BanThreshold  uint64        // Number of invalid gossip messages after which peer is banned
+	BanDuration   time.Duration // Time for which misbehaving peers are banned
+
+	// NAT traversal and relaying
+	EnableNAT          bool   // Enable UPnP/NAT-PMP port mapping and serve AutoNAT reachability checks to other peers
+	EnableHolePunching bool   // Enable direct connection upgrade through relay (hole punching)
+	StaticRelays       string // Comma separated list of circuit relay v2 nodes used to accept inbound connections when behind NAT
+	EnableRelayService bool   // Act as circuit relay v2 for other peers; node should be publicly reachable
+
+	// Private networks
+	PrivateNetworkKeyFile string // Path to pre-shared key (swarm.key) file; only nodes with the same key can connect
+	AllowlistOnly         bool   // Accept and dial only peers listed in AllowedPeers
+
+	DirectPeers string // Comma separated list of trusted peers: full nodes the aggregator pushes blocks to, or aggregator allowed to push blocks to full node
+
+	CombinedBlockGossip bool // Gossip header and data of every block as a single message on a combined block topic
+
+	// Bandwidth and rate limiting of header and data exchange servers; 0 means unlimited
+	MaxSendRate         uint64  // Maximum rate of data (bytes/s) sent to all peers by exchange servers
+	MaxSendRatePerPeer  uint64  // Maximum rate of data (bytes/s) sent to a single peer by exchange servers
+	ExchangeRequestRate float64 // Maximum rate of requests (requests/s) accepted from a single peer, separately for headers and data
+}
+
+// Validate checks that P2P configuration is valid.
+func (c P2PConfig) Validate() error {
+	if c.MaxSendRate != 0 && c.MaxSendRate < MinSendRate {
+		return fmt.Errorf("P2P max send rate must be 0 (unlimited) or at least %d bytes/s", MinSendRate)
+	}
+	if c.MaxSendRatePerPeer != 0 && c.MaxSendRatePerPeer < MinSendRate {
+		return fmt.Errorf("P2P max send rate per peer must be 0 (unlimited) or at least %d bytes/s", MinSendRate)
+	}
+	return nil
 }
@@ -1,5 +1,7 @@
 package config
 
+import "errors"
+
 // RPCConfig holds RPC configuration params.
 type RPCConfig struct {
 	ListenAddress string
@@ -35,4 +37,45 @@ type RPCConfig struct {
 	// NOTE: both tls-cert-file and tls-key-file must be present for Tendermint to create HTTPS server.
 	// Otherwise, HTTP server is run.
 	TLSKeyFile string `mapstructure:"tls-key-file"`
+
+	// Unsafe enables administrative RPC methods, e.g. runtime peer management.
+	Unsafe bool
+
+	// GRPCListenAddress is the listen address of the gRPC query service of the node, e.g. "tcp://127.0.0.1:9090".
+	// Empty means the service is disabled. It's distinct from CometBFT's gRPC listen address, which configures
+	// the BroadcastAPI.
+	GRPCListenAddress string
+
+	// Authentication of callers with "Authorization: Bearer <token>" header. Callers are authenticated by the static
+	// AuthToken, or by a JWT signed with HS256 using the secret read from JWTSecretFile, expiring within an hour. If
+	// neither is configured, all callers are anonymous and may call all methods.
+	AuthToken     string
+	JWTSecretFile string
+
+	// Method access control, enforced only if authentication is configured; comma separated lists of method names.
+	PublicMethods        string // Methods anonymous callers may call; empty means none
+	AuthenticatedMethods string // Methods authenticated callers may call; empty means all
+
+	// RequestRate is the maximum rate of requests (requests/s) accepted from a single IP address. 0 means unlimited.
+	RequestRate float64
+
+	// MaxResponseBytes is the maximum size of a single call result, or of all responses to a batch request.
+	// 0 means unlimited.
+	MaxResponseBytes uint64
+}
+
+// AuthEnabled reports whether authentication of RPC callers is configured.
+func (c RPCConfig) AuthEnabled() bool {
+	return c.AuthToken != "" || c.JWTSecretFile != ""
+}
+
+// Validate checks RPCConfig for invalid values.
+func (c RPCConfig) Validate() error {
+	if c.RequestRate < 0 {
+		return errors.New("RPC request rate must be greater than or equal to zero")
+	}
+	if !c.AuthEnabled() && (c.PublicMethods != "" || c.AuthenticatedMethods != "") {
+		return errors.New("RPC method access lists require RPC auth token or JWT secret file")
+	}
+	return nil
 }
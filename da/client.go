@@ -0,0 +1,93 @@
+package da
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	goDA "github.com/rollkit/go-da"
+	proxyda "github.com/rollkit/go-da/proxy"
+	proxyjsonrpc "github.com/rollkit/go-da/proxy/jsonrpc"
+)
+
+// HTTPStatusError is returned by JSON-RPC clients created by NewClient when the endpoint, or a proxy in front of
+// it, responds with HTTP 5xx status instead of a JSON-RPC response.
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status: %s", e.Status)
+}
+
+// NewClient returns a client of the DA layer at given URI, like go-da proxy.NewClient. Supported schemes are grpc,
+// http and https. JSON-RPC clients report HTTP 5xx responses as *HTTPStatusError, and errors of requests for heights
+// that are not produced yet match ErrHeightFromFuture.
+func NewClient(uri, token string) (goDA.DA, error) {
+	addr, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if addr.Scheme != "http" && addr.Scheme != "https" {
+		client, err := proxyda.NewClient(uri, token)
+		if err != nil {
+			return nil, err
+		}
+		return TypedErrors(client), nil
+	}
+	var api proxyjsonrpc.API
+	header := http.Header{"Authorization": []string{fmt.Sprintf("Bearer %s", token)}}
+	httpClient := &http.Client{Transport: statusTransport{http.DefaultTransport}}
+	_, err = jsonrpc.NewMergeClient(context.Background(), uri, "da", []interface{}{&api.Internal}, header,
+		jsonrpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return TypedErrors(&api), nil
+}
+
+// TypedErrors returns DA client, which maps errors returned by d to errors of this package, so that they can be
+// matched with errors.Is. DA clients returned by NewClient already map errors; it's useful for DA implementations
+// used directly, e.g. in tests.
+func TypedErrors(d goDA.DA) goDA.DA {
+	return clientErrors{d}
+}
+
+// clientErrors maps errors of DA layer calls to errors of this package. go-da servers report these errors only by
+// their messages, so they are matched once here instead of by every caller.
+type clientErrors struct {
+	goDA.DA
+}
+
+// GetIDs returns IDs of blobs at given height.
+func (c clientErrors) GetIDs(ctx context.Context, height uint64, namespace goDA.Namespace) (*goDA.GetIDsResult, error) {
+	res, err := c.DA.GetIDs(ctx, height, namespace)
+	if err != nil && strings.Contains(err.Error(), ErrHeightFromFuture.Error()) {
+		return nil, fmt.Errorf("%w: %w", ErrHeightFromFuture, err)
+	}
+	return res, err
+}
+
+// statusTransport turns HTTP 5xx responses, which are not JSON-RPC responses, into *HTTPStatusError.
+type statusTransport struct {
+	http.RoundTripper
+}
+
+func (t statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusInternalServerError {
+		return resp, err
+	}
+	// JSON-RPC servers report errors of calls with status 500 and JSON body.
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode == http.StatusInternalServerError && mediaType == "application/json" {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+}
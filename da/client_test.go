@@ -0,0 +1,59 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientHTTPStatus(t *testing.T) {
+	cases := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		endpoint    bool
+	}{
+		{"bad gateway", http.StatusBadGateway, "text/html", "<html>bad gateway</html>", true},
+		{"proxy error", http.StatusInternalServerError, "text/plain", "internal error", true},
+		{"JSON-RPC error", http.StatusInternalServerError, "application/json", `{"jsonrpc":"2.0","id":1,"error":{"code":1,"message":"tx too large"}}`, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", c.contentType)
+				w.WriteHeader(c.status)
+				_, _ = w.Write([]byte(c.body))
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(srv.URL, "token")
+			require.NoError(t, err)
+			_, err = client.MaxBlobSize(context.Background())
+			require.Error(t, err)
+			assert.Equal(t, c.endpoint, isEndpointError(err), err.Error())
+			var statusErr *HTTPStatusError
+			assert.Equal(t, c.endpoint, errors.As(err, &statusErr))
+		})
+	}
+}
+
+func TestNewClientHeightFromFuture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":1,"message":"given height is from the future"}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, "token")
+	require.NoError(t, err)
+	_, err = client.GetIDs(context.Background(), 10, nil)
+	assert.ErrorIs(t, err, ErrHeightFromFuture)
+	assert.False(t, isEndpointError(err))
+}
@@ -1,6 +1,7 @@
 package da
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -48,6 +49,9 @@ var (
 
 	// ErrContextDeadline is the error message returned by the DA when context deadline exceeds
 	ErrContextDeadline = errors.New("context deadline")
+
+	// ErrHeightFromFuture is returned by the DA when requested height is not produced yet (see NewClient)
+	ErrHeightFromFuture = errors.New("given height is from the future")
 )
 
 // StatusCode is a type for DA layer return status.
@@ -66,8 +70,33 @@ const (
 	StatusTooBig
 	StatusContextDeadline
 	StatusError
+	StatusHeightFromFuture
 )
 
+// String returns the name of the status code.
+func (c StatusCode) String() string {
+	switch c {
+	case StatusSuccess:
+		return "success"
+	case StatusNotFound:
+		return "not_found"
+	case StatusNotIncludedInBlock:
+		return "not_included_in_block"
+	case StatusAlreadyInMempool:
+		return "already_in_mempool"
+	case StatusTooBig:
+		return "too_big"
+	case StatusContextDeadline:
+		return "context_deadline"
+	case StatusError:
+		return "error"
+	case StatusHeightFromFuture:
+		return "height_from_future"
+	default:
+		return "unknown"
+	}
+}
+
 // BaseResult contains basic information returned by DA layer.
 type BaseResult struct {
 	// Code is to determine if the action succeeded.
@@ -83,6 +112,14 @@ type BaseResult struct {
 // ResultSubmit contains information returned from DA layer after block headers/data submission.
 type ResultSubmit struct {
 	BaseResult
+	// Inclusions lists DA endpoints that included submitted blobs, if known.
+	Inclusions []Inclusion
+	// IDs identify submitted blobs in DA layer, one per submitted block.
+	IDs []goDA.ID
+	// Commitments are DA commitments to submitted blobs. Nil if DA failed to provide them.
+	Commitments []goDA.Commitment
+	// Proofs are DA inclusion proofs of submitted blobs. Nil if DA failed to provide them.
+	Proofs []goDA.Proof
 	// Not sure if this needs to be bubbled up to other
 	// parts of Rollkit.
 	// Hash hash.Hash
@@ -96,43 +133,83 @@ type ResultRetrieveHeaders struct {
 	Headers []*types.SignedHeader
 }
 
+// ResultRetrieveData contains batch of block data returned from DA layer client.
+type ResultRetrieveData struct {
+	BaseResult
+	// Data is the block data retrieved from Data Availability Layer.
+	// If Code is not equal to StatusSuccess, it has to be nil.
+	Data []*types.Data
+}
+
 // DAClient is a new DA implementation.
 type DAClient struct {
-	DA              goDA.DA
-	GasPrice        float64
-	GasMultiplier   float64
-	Namespace       goDA.Namespace
-	SubmitOptions   []byte
-	SubmitTimeout   time.Duration
-	RetrieveTimeout time.Duration
-	Logger          log.Logger
+	DA            goDA.DA
+	GasPrice      float64
+	GasMultiplier float64
+	// HeaderNamespace is used for block headers.
+	HeaderNamespace goDA.Namespace
+	// DataNamespace is used for block data.
+	DataNamespace goDA.Namespace
+	// ForcedInclusionNamespace is reserved for transactions that must be included by the sequencer. Nothing is
+	// submitted to or retrieved from it yet.
+	ForcedInclusionNamespace goDA.Namespace
+	SubmitOptions            []byte
+	SubmitTimeout            time.Duration
+	RetrieveTimeout          time.Duration
+	Logger                   log.Logger
 }
 
 // NewDAClient returns a new DA client.
+//
+// Given namespace is used for all kinds of blobs, until overridden.
 func NewDAClient(da goDA.DA, gasPrice, gasMultiplier float64, ns goDA.Namespace, options []byte, logger log.Logger) *DAClient {
 	return &DAClient{
-		DA:              da,
-		GasPrice:        gasPrice,
-		GasMultiplier:   gasMultiplier,
-		Namespace:       ns,
-		SubmitOptions:   options,
-		SubmitTimeout:   defaultSubmitTimeout,
-		RetrieveTimeout: defaultRetrieveTimeout,
-		Logger:          logger,
+		DA:                       da,
+		GasPrice:                 gasPrice,
+		GasMultiplier:            gasMultiplier,
+		HeaderNamespace:          ns,
+		DataNamespace:            ns,
+		ForcedInclusionNamespace: ns,
+		SubmitOptions:            options,
+		SubmitTimeout:            defaultSubmitTimeout,
+		RetrieveTimeout:          defaultRetrieveTimeout,
+		Logger:                   logger,
 	}
 }
 
+// SeparateDataNamespace reports whether block data has its own namespace. Otherwise data is not posted to DA, as
+// data blobs would be mixed with headers.
+func (dac *DAClient) SeparateDataNamespace() bool {
+	return !bytes.Equal(dac.HeaderNamespace, dac.DataNamespace)
+}
+
 // SubmitHeaders submits block headers to DA.
 func (dac *DAClient) SubmitHeaders(ctx context.Context, headers []*types.SignedHeader, maxBlobSize uint64, gasPrice float64) ResultSubmit {
+	blobs, message := dac.marshalBlobs(len(headers), func(i int) ([]byte, error) {
+		return headers[i].MarshalBinary()
+	}, maxBlobSize)
+	return dac.submitBlobs(ctx, "headers", blobs, message, gasPrice, dac.HeaderNamespace, true)
+}
+
+// SubmitData submits block data to DA.
+func (dac *DAClient) SubmitData(ctx context.Context, data []*types.Data, maxBlobSize uint64, gasPrice float64) ResultSubmit {
+	blobs, message := dac.marshalBlobs(len(data), func(i int) ([]byte, error) {
+		return data[i].MarshalBinary()
+	}, maxBlobSize)
+	return dac.submitBlobs(ctx, "data", blobs, message, gasPrice, dac.DataNamespace, false)
+}
+
+// marshalBlobs serializes items until maxBlobSize is reached.
+func (dac *DAClient) marshalBlobs(n int, marshal func(i int) ([]byte, error), maxBlobSize uint64) ([][]byte, string) {
 	var (
 		blobs    [][]byte
 		blobSize uint64
 		message  string
 	)
-	for i := range headers {
-		blob, err := headers[i].MarshalBinary()
+	for i := 0; i < n; i++ {
+		blob, err := marshal(i)
 		if err != nil {
-			message = fmt.Sprint("failed to serialize header", err)
+			message = fmt.Sprint("failed to serialize blob", err)
 			dac.Logger.Info(message)
 			break
 		}
@@ -144,18 +221,24 @@ func (dac *DAClient) SubmitHeaders(ctx context.Context, headers []*types.SignedH
 		blobSize += uint64(len(blob))
 		blobs = append(blobs, blob)
 	}
+	return blobs, message
+}
+
+// submitBlobs submits blobs to given namespace. If withProofs is set, commitments and inclusion proofs of submitted
+// blobs are fetched.
+func (dac *DAClient) submitBlobs(ctx context.Context, kind string, blobs [][]byte, message string, gasPrice float64, namespace goDA.Namespace, withProofs bool) ResultSubmit {
 	if len(blobs) == 0 {
 		return ResultSubmit{
 			BaseResult: BaseResult{
 				Code:    StatusError,
-				Message: "failed to submit headers: no blobs generated " + message,
+				Message: "failed to submit " + kind + ": no blobs generated " + message,
 			},
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, dac.SubmitTimeout)
+	submitCtx, cancel := context.WithTimeout(ctx, dac.SubmitTimeout)
 	defer cancel()
-	ids, err := dac.submit(ctx, blobs, gasPrice, dac.Namespace)
+	ids, inclusions, source, err := dac.submit(submitCtx, blobs, gasPrice, namespace)
 	if err != nil {
 		status := StatusError
 		switch {
@@ -174,76 +257,77 @@ func (dac *DAClient) SubmitHeaders(ctx context.Context, headers []*types.SignedH
 		return ResultSubmit{
 			BaseResult: BaseResult{
 				Code:    status,
-				Message: "failed to submit headers: " + err.Error(),
+				Message: "failed to submit " + kind + ": " + err.Error(),
 			},
 		}
 	}
 
-	if len(ids) == 0 {
+	if len(ids) == 0 || len(ids) > len(blobs) {
 		return ResultSubmit{
 			BaseResult: BaseResult{
 				Code:    StatusError,
-				Message: "failed to submit headers: unexpected len(ids): 0",
+				Message: fmt.Sprintf("failed to submit %s: unexpected len(ids): %d, submitted %d blobs", kind, len(ids), len(blobs)),
 			},
 		}
 	}
 
+	var (
+		commitments []goDA.Commitment
+		proofs      []goDA.Proof
+	)
+	if withProofs {
+		commitments, proofs = dac.inclusionProofs(ctx, source, blobs[:len(ids)], ids, namespace)
+	}
 	return ResultSubmit{
 		BaseResult: BaseResult{
 			Code:           StatusSuccess,
 			DAHeight:       binary.LittleEndian.Uint64(ids[0]),
 			SubmittedCount: uint64(len(ids)),
 		},
+		Inclusions:  inclusions,
+		IDs:         ids,
+		Commitments: commitments,
+		Proofs:      proofs,
 	}
 }
 
-// RetrieveHeaders retrieves block headers from DA.
-func (dac *DAClient) RetrieveHeaders(ctx context.Context, dataLayerHeight uint64) ResultRetrieveHeaders {
-	result, err := dac.DA.GetIDs(ctx, dataLayerHeight, dac.Namespace)
-	if err != nil {
-		return ResultRetrieveHeaders{
-			BaseResult: BaseResult{
-				Code:     StatusError,
-				Message:  fmt.Sprintf("failed to get IDs: %s", err.Error()),
-				DAHeight: dataLayerHeight,
-			},
-		}
+// inclusionProofs fetches commitments and inclusion proofs of submitted blobs from the DA endpoint that returned
+// their IDs. It has its own timeout (RetrieveTimeout), so that it doesn't shorten the submission timeout.
+//
+// Blobs are already included in DA, so failures are only logged and nil is returned instead.
+func (dac *DAClient) inclusionProofs(ctx context.Context, source goDA.DA, blobs []goDA.Blob, ids []goDA.ID, namespace goDA.Namespace) ([]goDA.Commitment, []goDA.Proof) {
+	ctx, cancel := context.WithTimeout(ctx, dac.RetrieveTimeout)
+	defer cancel()
+	commitments, err := source.Commit(ctx, blobs, namespace)
+	if err != nil || len(commitments) != len(ids) {
+		dac.Logger.Error("failed to get commitments of submitted blobs", "error", err, "commitments", len(commitments), "ids", len(ids))
+		commitments = nil
 	}
-
-	// If no blocks are found, return a non-blocking error.
-	if len(result.IDs) == 0 {
-		return ResultRetrieveHeaders{
-			BaseResult: BaseResult{
-				Code:     StatusNotFound,
-				Message:  ErrBlobNotFound.Error(),
-				DAHeight: dataLayerHeight,
-			},
-		}
+	proofs, err := source.GetProofs(ctx, ids, namespace)
+	if err != nil || len(proofs) != len(ids) {
+		dac.Logger.Error("failed to get inclusion proofs of submitted blobs", "error", err, "proofs", len(proofs), "ids", len(ids))
+		proofs = nil
 	}
+	return commitments, proofs
+}
 
-	ctx, cancel := context.WithTimeout(ctx, dac.RetrieveTimeout)
-	defer cancel()
-	blobs, err := dac.DA.Get(ctx, result.IDs, dac.Namespace)
-	if err != nil {
-		return ResultRetrieveHeaders{
-			BaseResult: BaseResult{
-				Code:     StatusError,
-				Message:  fmt.Sprintf("failed to get blobs: %s", err.Error()),
-				DAHeight: dataLayerHeight,
-			},
-		}
+// RetrieveHeaders retrieves block headers from DA.
+func (dac *DAClient) RetrieveHeaders(ctx context.Context, dataLayerHeight uint64) ResultRetrieveHeaders {
+	blobs, res := dac.retrieveBlobs(ctx, dataLayerHeight, dac.HeaderNamespace)
+	if res.Code != StatusSuccess {
+		return ResultRetrieveHeaders{BaseResult: res}
 	}
 
 	headers := make([]*types.SignedHeader, len(blobs))
 	for i, blob := range blobs {
 		var header pb.SignedHeader
-		err = proto.Unmarshal(blob, &header)
+		err := proto.Unmarshal(blob, &header)
 		if err != nil {
 			dac.Logger.Error("failed to unmarshal block", "daHeight", dataLayerHeight, "position", i, "error", err)
 			continue
 		}
 		headers[i] = new(types.SignedHeader)
-		err := headers[i].FromProto(&header)
+		err = headers[i].FromProto(&header)
 		if err != nil {
 			return ResultRetrieveHeaders{
 				BaseResult: BaseResult{
@@ -255,17 +339,92 @@ func (dac *DAClient) RetrieveHeaders(ctx context.Context, dataLayerHeight uint64
 	}
 
 	return ResultRetrieveHeaders{
-		BaseResult: BaseResult{
-			Code:     StatusSuccess,
+		BaseResult: res,
+		Headers:    headers,
+	}
+}
+
+// RetrieveData retrieves block data from DA.
+func (dac *DAClient) RetrieveData(ctx context.Context, dataLayerHeight uint64) ResultRetrieveData {
+	blobs, res := dac.retrieveBlobs(ctx, dataLayerHeight, dac.DataNamespace)
+	if res.Code != StatusSuccess {
+		return ResultRetrieveData{BaseResult: res}
+	}
+
+	data := make([]*types.Data, 0, len(blobs))
+	for i, blob := range blobs {
+		d := new(types.Data)
+		if err := d.UnmarshalBinary(blob); err != nil {
+			dac.Logger.Error("failed to unmarshal data", "daHeight", dataLayerHeight, "position", i, "error", err)
+			continue
+		}
+		data = append(data, d)
+	}
+
+	return ResultRetrieveData{
+		BaseResult: res,
+		Data:       data,
+	}
+}
+
+// retrieveBlobs returns all blobs in given namespace at given DA height.
+func (dac *DAClient) retrieveBlobs(ctx context.Context, dataLayerHeight uint64, namespace goDA.Namespace) ([][]byte, BaseResult) {
+	result, err := dac.DA.GetIDs(ctx, dataLayerHeight, namespace)
+	if errors.Is(err, ErrHeightFromFuture) {
+		return nil, BaseResult{
+			Code:     StatusHeightFromFuture,
+			Message:  err.Error(),
 			DAHeight: dataLayerHeight,
-		},
-		Headers: headers,
+		}
+	}
+	if err != nil {
+		return nil, BaseResult{
+			Code:     StatusError,
+			Message:  fmt.Sprintf("failed to get IDs: %s", err.Error()),
+			DAHeight: dataLayerHeight,
+		}
+	}
+
+	// If no blocks are found, return a non-blocking error.
+	if len(result.IDs) == 0 {
+		return nil, BaseResult{
+			Code:     StatusNotFound,
+			Message:  ErrBlobNotFound.Error(),
+			DAHeight: dataLayerHeight,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dac.RetrieveTimeout)
+	defer cancel()
+	blobs, err := dac.DA.Get(ctx, result.IDs, namespace)
+	if err != nil {
+		return nil, BaseResult{
+			Code:     StatusError,
+			Message:  fmt.Sprintf("failed to get blobs: %s", err.Error()),
+			DAHeight: dataLayerHeight,
+		}
+	}
+
+	return blobs, BaseResult{
+		Code:     StatusSuccess,
+		DAHeight: dataLayerHeight,
 	}
 }
 
-func (dac *DAClient) submit(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace) ([]goDA.ID, error) {
+// submit submits blobs, returning their IDs, DA endpoints that included them (if known) and the DA that returned
+// the IDs.
+func (dac *DAClient) submit(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace) ([]goDA.ID, []Inclusion, goDA.DA, error) {
+	if multi, ok := dac.DA.(*MultiDA); ok {
+		return multi.submit(ctx, blobs, gasPrice, namespace, dac.SubmitOptions)
+	}
+	var (
+		ids []goDA.ID
+		err error
+	)
 	if len(dac.SubmitOptions) == 0 {
-		return dac.DA.Submit(ctx, blobs, gasPrice, namespace)
+		ids, err = dac.DA.Submit(ctx, blobs, gasPrice, namespace)
+	} else {
+		ids, err = dac.DA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, dac.SubmitOptions)
 	}
-	return dac.DA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, dac.SubmitOptions)
+	return ids, nil, dac.DA, err
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"math/rand"
-	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -13,13 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/rollkit/go-da"
 	damock "github.com/rollkit/go-da/mocks"
-	proxygrpc "github.com/rollkit/go-da/proxy/grpc"
-	proxyjsonrpc "github.com/rollkit/go-da/proxy/jsonrpc"
 	goDATest "github.com/rollkit/go-da/test"
 	testServer "github.com/rollkit/rollkit/test/server"
 	"github.com/rollkit/rollkit/types"
@@ -113,13 +108,23 @@ func TestMockDAErrors(t *testing.T) {
 			Return([]da.ID{}, errors.New("tx too large"))
 		doTestTxTooLargeError(t, dalc, headers)
 	})
+	t.Run("too_many_ids", func(t *testing.T) {
+		mockDA := &damock.MockDA{}
+		dalc := NewDAClient(mockDA, -1, -1, nil, nil, log.TestingLogger())
+		header, _ := types.GetRandomBlock(1, 0, chainID)
+		mockDA.On("MaxBlobSize", mock.Anything).Return(uint64(1<<20), nil)
+		mockDA.
+			On("Submit", mock.Anything, mock.Anything, float64(-1), []byte(nil)).
+			Return([]da.ID{{1}, {2}}, nil)
+		res := dalc.SubmitHeaders(context.Background(), []*types.SignedHeader{header}, 1<<20, -1)
+		assert.Equal(t, StatusError, res.Code)
+		assert.Contains(t, res.Message, "unexpected len(ids)")
+	})
 }
 
 func TestSubmitRetrieve(t *testing.T) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	dummyClient := NewDAClient(goDATest.NewDummyDA(), -1, -1, nil, nil, log.TestingLogger())
-	jsonrpcClient, err := startMockDAClientJSONRPC(ctx)
+	dummyClient := NewDAClient(TypedErrors(goDATest.NewDummyDA()), -1, -1, nil, nil, log.TestingLogger())
+	jsonrpcClient, err := startMockDAClientJSONRPC()
 	require.NoError(t, err)
 	grpcClient := startMockDAClientGRPC()
 	require.NoError(t, err)
@@ -149,20 +154,19 @@ func TestSubmitRetrieve(t *testing.T) {
 }
 
 func startMockDAClientGRPC() *DAClient {
-	client := proxygrpc.NewClient()
-	addr, _ := url.Parse(MockDAAddress)
-	if err := client.Start(addr.Host, grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+	client, err := NewClient(MockDAAddress, "")
+	if err != nil {
 		panic(err)
 	}
 	return NewDAClient(client, -1, -1, nil, nil, log.TestingLogger())
 }
 
-func startMockDAClientJSONRPC(ctx context.Context) (*DAClient, error) {
-	client, err := proxyjsonrpc.NewClient(ctx, MockDAAddressHTTP, "")
+func startMockDAClientJSONRPC() (*DAClient, error) {
+	client, err := NewClient(MockDAAddressHTTP, "")
 	if err != nil {
 		return nil, err
 	}
-	return NewDAClient(&client.DA, -1, -1, nil, nil, log.TestingLogger()), nil
+	return NewDAClient(client, -1, -1, nil, nil, log.TestingLogger()), nil
 }
 
 func doTestSubmitTimeout(t *testing.T, dalc *DAClient, headers []*types.SignedHeader) {
@@ -207,6 +211,12 @@ func doTestSubmitRetrieve(t *testing.T, dalc *DAClient) {
 		for len(headers) > 0 {
 			resp := dalc.SubmitHeaders(ctx, headers, maxBlobSize, -1)
 			assert.Equal(StatusSuccess, resp.Code, resp.Message)
+			require.Len(resp.Commitments, len(resp.IDs))
+			valid, err := dalc.DA.Validate(ctx, resp.IDs, resp.Proofs, dalc.HeaderNamespace)
+			require.NoError(err)
+			for _, v := range valid {
+				assert.True(v)
+			}
 
 			for _, block := range headers[:resp.SubmittedCount] {
 				headerToDAHeight[block] = resp.DAHeight
@@ -354,14 +364,13 @@ func doTestRetrieveNoBlocksFound(t *testing.T, dalc *DAClient) {
 	// when namespaces are implemented, this should be uncommented
 	// assert.Equal(StatusNotFound, result.Code)
 	// assert.Contains(result.Message, ErrBlobNotFound.Error())
-	assert.Equal(StatusError, result.Code)
+	// dummy DA is not at height 123 yet
+	assert.Equal(StatusHeightFromFuture, result.Code)
 }
 
 func TestSubmitWithOptions(t *testing.T) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
 	dummyClient := NewDAClient(goDATest.NewDummyDA(), -1, -1, nil, []byte("option=value"), log.TestingLogger())
-	jsonrpcClient, err := startMockDAClientJSONRPC(ctx)
+	jsonrpcClient, err := startMockDAClientJSONRPC()
 	require.NoError(t, err)
 	grpcClient := startMockDAClientGRPC()
 	require.NoError(t, err)
@@ -384,3 +393,26 @@ func TestSubmitWithOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestSubmitRetrieveData(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dalc := NewDAClient(goDATest.NewDummyDA(), -1, -1, []byte("header"), nil, log.TestingLogger())
+	dalc.DataNamespace = []byte("data")
+
+	maxBlobSize, err := dalc.DA.MaxBlobSize(ctx)
+	require.NoError(t, err)
+
+	_, data1 := types.GetRandomBlock(1, 2, "TestSubmitRetrieveData")
+	_, data2 := types.GetRandomBlock(2, 3, "TestSubmitRetrieveData")
+	resp := dalc.SubmitData(ctx, []*types.Data{data1, data2}, maxBlobSize, -1)
+	require.Equal(t, StatusSuccess, resp.Code, resp.Message)
+	assert.EqualValues(t, 2, resp.SubmittedCount)
+
+	ret := dalc.RetrieveData(ctx, resp.DAHeight)
+	require.Equal(t, StatusSuccess, ret.Code, ret.Message)
+	require.Len(t, ret.Data, 2)
+	assert.Equal(t, data1.Hash(), ret.Data[0].Hash())
+	assert.Equal(t, data2.Hash(), ret.Data[1].Hash())
+}
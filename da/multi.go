@@ -0,0 +1,392 @@
+package da
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	goDA "github.com/rollkit/go-da"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	// defaultEndpointCooldown is the time an endpoint is deprioritized after a failed call
+	defaultEndpointCooldown = 30 * time.Second
+
+	// defaultHealthCheckInterval is the interval between health probes of DA endpoints
+	defaultHealthCheckInterval = 15 * time.Second
+
+	// defaultHealthCheckTimeout is the time limit of a health probe of a single DA endpoint
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+// ErrNoEndpoints is returned when MultiDA is created without any endpoint.
+var ErrNoEndpoints = errors.New("no DA endpoints configured")
+
+// Endpoint is a single DA backend used by MultiDA.
+type Endpoint struct {
+	// Name identifies the endpoint in logs and inclusion records, usually its address.
+	Name string
+	DA   goDA.DA
+}
+
+// Inclusion records that blobs were included by a DA endpoint at given DA height.
+type Inclusion = types.DAInclusion
+
+// EndpointStatus describes health of a single DA endpoint.
+type EndpointStatus struct {
+	Name      string
+	Healthy   bool
+	Failures  uint64
+	LastError string
+}
+
+type endpointState struct {
+	Endpoint
+	healthy  bool
+	failures uint64
+	lastErr  error
+	retryAt  time.Time
+}
+
+var _ goDA.DA = &MultiDA{}
+
+// MultiDA is a goDA.DA implementation backed by an ordered list of endpoints.
+//
+// Calls are routed to endpoints in priority order, skipping endpoints that recently
+// failed. When a call fails, the endpoint is marked unhealthy and the call is retried
+// on the next endpoint. Unhealthy endpoints are tried as a last resort, and are restored
+// after a successful call or health probe.
+//
+// In fan-out mode, blobs are submitted to every healthy endpoint concurrently and each
+// successful inclusion is reported.
+type MultiDA struct {
+	endpoints []*endpointState
+	mtx       sync.Mutex
+
+	fanOut             bool
+	cooldown           time.Duration
+	healthCheckTimeout time.Duration
+	logger             log.Logger
+}
+
+// NewMultiDA returns a new MultiDA for given endpoints, ordered by priority.
+func NewMultiDA(endpoints []Endpoint, fanOut bool, logger log.Logger) (*MultiDA, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	states := make([]*endpointState, len(endpoints))
+	for i, e := range endpoints {
+		states[i] = &endpointState{Endpoint: e, healthy: true}
+	}
+	return &MultiDA{
+		endpoints:          states,
+		fanOut:             fanOut,
+		cooldown:           defaultEndpointCooldown,
+		healthCheckTimeout: defaultHealthCheckTimeout,
+		logger:             logger,
+	}, nil
+}
+
+// FanOut returns true if blobs are submitted to all healthy endpoints.
+func (m *MultiDA) FanOut() bool {
+	return m.fanOut
+}
+
+// Status returns health information about all endpoints, in priority order.
+func (m *MultiDA) Status() []EndpointStatus {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	statuses := make([]EndpointStatus, len(m.endpoints))
+	for i, e := range m.endpoints {
+		statuses[i] = EndpointStatus{
+			Name:     e.Name,
+			Healthy:  e.healthy,
+			Failures: e.failures,
+		}
+		if e.lastErr != nil {
+			statuses[i].LastError = e.lastErr.Error()
+		}
+	}
+	return statuses
+}
+
+// HealthCheckLoop periodically probes all endpoints until context is done.
+func (m *MultiDA) HealthCheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(defaultHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		m.CheckHealth(ctx)
+	}
+}
+
+// CheckHealth probes every endpoint and updates its health status. Endpoints not responding within the health check
+// timeout are marked unhealthy, so that a hanging endpoint doesn't block probing the others.
+func (m *MultiDA) CheckHealth(ctx context.Context) {
+	for _, e := range m.endpoints {
+		probeCtx, cancel := context.WithTimeout(ctx, m.healthCheckTimeout)
+		_, err := e.DA.MaxBlobSize(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			m.markFailure(e, err)
+			continue
+		}
+		m.markSuccess(e)
+	}
+}
+
+// MaxBlobSize returns the max blob size of the first responding endpoint.
+func (m *MultiDA) MaxBlobSize(ctx context.Context) (uint64, error) {
+	return failover(ctx, m, func(e *endpointState) (uint64, error) {
+		return e.DA.MaxBlobSize(ctx)
+	})
+}
+
+// Get returns Blob for each given ID from the first responding endpoint.
+func (m *MultiDA) Get(ctx context.Context, ids []goDA.ID, namespace goDA.Namespace) ([]goDA.Blob, error) {
+	return failover(ctx, m, func(e *endpointState) ([]goDA.Blob, error) {
+		return e.DA.Get(ctx, ids, namespace)
+	})
+}
+
+// GetIDs returns IDs of all Blobs located at given height from the first responding endpoint.
+func (m *MultiDA) GetIDs(ctx context.Context, height uint64, namespace goDA.Namespace) (*goDA.GetIDsResult, error) {
+	return failover(ctx, m, func(e *endpointState) (*goDA.GetIDsResult, error) {
+		return e.DA.GetIDs(ctx, height, namespace)
+	})
+}
+
+// GetProofs returns inclusion Proofs for given IDs from the first responding endpoint.
+func (m *MultiDA) GetProofs(ctx context.Context, ids []goDA.ID, namespace goDA.Namespace) ([]goDA.Proof, error) {
+	return failover(ctx, m, func(e *endpointState) ([]goDA.Proof, error) {
+		return e.DA.GetProofs(ctx, ids, namespace)
+	})
+}
+
+// Commit creates a Commitment for each given Blob using the first responding endpoint.
+func (m *MultiDA) Commit(ctx context.Context, blobs []goDA.Blob, namespace goDA.Namespace) ([]goDA.Commitment, error) {
+	return failover(ctx, m, func(e *endpointState) ([]goDA.Commitment, error) {
+		return e.DA.Commit(ctx, blobs, namespace)
+	})
+}
+
+// Validate validates Commitments against the corresponding Proofs using the first responding endpoint.
+func (m *MultiDA) Validate(ctx context.Context, ids []goDA.ID, proofs []goDA.Proof, namespace goDA.Namespace) ([]bool, error) {
+	return failover(ctx, m, func(e *endpointState) ([]bool, error) {
+		return e.DA.Validate(ctx, ids, proofs, namespace)
+	})
+}
+
+// Submit submits the Blobs to Data Availability layer.
+func (m *MultiDA) Submit(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace) ([]goDA.ID, error) {
+	ids, _, err := m.SubmitWithInclusions(ctx, blobs, gasPrice, namespace, nil)
+	return ids, err
+}
+
+// SubmitWithOptions submits the Blobs to Data Availability layer.
+func (m *MultiDA) SubmitWithOptions(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace, options []byte) ([]goDA.ID, error) {
+	ids, _, err := m.SubmitWithInclusions(ctx, blobs, gasPrice, namespace, options)
+	return ids, err
+}
+
+// SubmitWithInclusions submits the Blobs and reports which endpoints included them.
+//
+// Returned IDs come from the highest priority endpoint that accepted the blobs.
+func (m *MultiDA) SubmitWithInclusions(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace, options []byte) ([]goDA.ID, []Inclusion, error) {
+	ids, inclusions, _, err := m.submit(ctx, blobs, gasPrice, namespace, options)
+	return ids, inclusions, err
+}
+
+// submit submits the Blobs like SubmitWithInclusions, and also returns the endpoint that returned the IDs, so that
+// proofs of the blobs can be requested from it.
+func (m *MultiDA) submit(ctx context.Context, blobs []goDA.Blob, gasPrice float64, namespace goDA.Namespace, options []byte) ([]goDA.ID, []Inclusion, goDA.DA, error) {
+	submit := func(e *endpointState) ([]goDA.ID, error) {
+		if len(options) == 0 {
+			return e.DA.Submit(ctx, blobs, gasPrice, namespace)
+		}
+		return e.DA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	}
+
+	if !m.fanOut {
+		var used *endpointState
+		ids, err := failover(ctx, m, func(e *endpointState) ([]goDA.ID, error) {
+			used = e
+			return submit(e)
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return ids, inclusionsOf(ids, used.Name), used.DA, nil
+	}
+
+	targets := m.healthyEndpoints()
+	if len(targets) == 0 {
+		targets = m.endpoints
+	}
+	results := make([][]goDA.ID, len(targets))
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, e := range targets {
+		wg.Add(1)
+		go func(i int, e *endpointState) {
+			defer wg.Done()
+			results[i], errs[i] = submit(e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	var (
+		ids        []goDA.ID
+		inclusions []Inclusion
+		source     goDA.DA
+		err        error
+	)
+	for i, e := range targets {
+		if errs[i] != nil {
+			if ctx.Err() == nil && isEndpointError(errs[i]) {
+				m.markFailure(e, errs[i])
+			}
+			err = errors.Join(err, fmt.Errorf("%s: %w", e.Name, errs[i]))
+			continue
+		}
+		m.markSuccess(e)
+		if ids == nil {
+			ids = results[i]
+			source = e.DA
+		}
+		inclusions = append(inclusions, inclusionsOf(results[i], e.Name)...)
+	}
+	if ids == nil {
+		return nil, nil, nil, err
+	}
+	if err != nil {
+		m.logger.Error("DA fan-out submission partially failed", "error", err)
+	}
+	return ids, inclusions, source, nil
+}
+
+// failover calls fn on endpoints in priority order until one of them succeeds.
+func failover[T any](ctx context.Context, m *MultiDA, fn func(*endpointState) (T, error)) (T, error) {
+	var (
+		zero T
+		err  error
+	)
+	for _, e := range m.candidates() {
+		res, callErr := fn(e)
+		if callErr == nil {
+			m.markSuccess(e)
+			return res, nil
+		}
+		err = errors.Join(err, fmt.Errorf("%s: %w", e.Name, callErr))
+		// errors caused by the request itself would be returned by every endpoint
+		if ctx.Err() != nil || !isEndpointError(callErr) {
+			return zero, err
+		}
+		m.markFailure(e, callErr)
+		m.logger.Info("DA endpoint failed, trying next one", "endpoint", e.Name, "error", callErr)
+	}
+	return zero, err
+}
+
+// candidates returns healthy endpoints first, followed by unhealthy ones, both in priority order.
+func (m *MultiDA) candidates() []*endpointState {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	now := time.Now()
+	healthy := make([]*endpointState, 0, len(m.endpoints))
+	var unhealthy []*endpointState
+	for _, e := range m.endpoints {
+		if e.healthy || now.After(e.retryAt) {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (m *MultiDA) healthyEndpoints() []*endpointState {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	now := time.Now()
+	var healthy []*endpointState
+	for _, e := range m.endpoints {
+		if e.healthy || now.After(e.retryAt) {
+			healthy = append(healthy, e)
+		}
+	}
+	return healthy
+}
+
+func (m *MultiDA) markSuccess(e *endpointState) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if !e.healthy {
+		m.logger.Info("DA endpoint is healthy again", "endpoint", e.Name)
+	}
+	e.healthy = true
+	e.lastErr = nil
+}
+
+func (m *MultiDA) markFailure(e *endpointState, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if e.healthy {
+		m.logger.Error("DA endpoint marked unhealthy", "endpoint", e.Name, "error", err)
+	}
+	e.healthy = false
+	e.failures++
+	e.lastErr = err
+	e.retryAt = time.Now().Add(m.cooldown)
+}
+
+// isEndpointError reports whether err was caused by the endpoint being unreachable or unresponsive, rather than
+// by the request. Only transport errors are detected (network errors, closed connections, timeouts, gRPC
+// unavailability and HTTP 5xx responses reported by clients created by NewClient, e.g. from a load balancer without
+// healthy backends), so errors returned by the DA layer itself, e.g. for too large blobs, don't trigger failover.
+func isEndpointError(err error) bool {
+	var (
+		netErr    net.Error
+		statusErr *HTTPStatusError
+	)
+	if errors.As(err, &statusErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// gRPC clients report HTTP 502, 503 and 504 responses as unavailability.
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func inclusionsOf(ids []goDA.ID, endpoint string) []Inclusion {
+	if len(ids) == 0 || len(ids[0]) < 8 {
+		return nil
+	}
+	return []Inclusion{{Endpoint: endpoint, DAHeight: binary.LittleEndian.Uint64(ids[0])}}
+}
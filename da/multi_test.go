@@ -0,0 +1,147 @@
+package da
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rollkit/go-da"
+	damock "github.com/rollkit/go-da/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+// errConnRefused is returned by DA clients when the endpoint is down.
+var errConnRefused = &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+func makeID(height uint64) da.ID {
+	id := make([]byte, 8)
+	binary.LittleEndian.PutUint64(id, height)
+	return id
+}
+
+func TestMultiDAFailover(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := &damock.MockDA{}, &damock.MockDA{}
+	primary.On("GetIDs", mock.Anything, uint64(1), mock.Anything).Return(nil, errConnRefused).Once()
+	secondary.On("GetIDs", mock.Anything, mock.Anything, mock.Anything).Return(&da.GetIDsResult{IDs: []da.ID{makeID(1)}}, nil)
+
+	multi, err := NewMultiDA([]Endpoint{{"primary", primary}, {"secondary", secondary}}, false, log.TestingLogger())
+	require.NoError(t, err)
+
+	res, err := multi.GetIDs(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Len(t, res.IDs, 1)
+
+	status := multi.Status()
+	assert.False(t, status[0].Healthy)
+	assert.EqualValues(t, 1, status[0].Failures)
+	assert.Contains(t, status[0].LastError, "connection refused")
+	assert.True(t, status[1].Healthy)
+
+	// unhealthy primary is skipped during cooldown
+	_, err = multi.GetIDs(ctx, 2, nil)
+	require.NoError(t, err)
+	primary.AssertNumberOfCalls(t, "GetIDs", 1)
+	secondary.AssertNumberOfCalls(t, "GetIDs", 2)
+
+	// successful health probe restores the endpoint
+	primary.On("MaxBlobSize", mock.Anything).Return(uint64(1234), nil)
+	secondary.On("MaxBlobSize", mock.Anything).Return(uint64(1234), nil)
+	multi.CheckHealth(ctx)
+	assert.True(t, multi.Status()[0].Healthy)
+}
+
+func TestMultiDACheckHealthTimeout(t *testing.T) {
+	hanging, healthy := &damock.MockDA{}, &damock.MockDA{}
+	hanging.On("MaxBlobSize", mock.Anything).Return(uint64(0), context.DeadlineExceeded).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	})
+	healthy.On("MaxBlobSize", mock.Anything).Return(uint64(1234), nil)
+
+	multi, err := NewMultiDA([]Endpoint{{"hanging", hanging}, {"healthy", healthy}}, false, log.TestingLogger())
+	require.NoError(t, err)
+	multi.healthCheckTimeout = 10 * time.Millisecond
+
+	// hanging endpoint doesn't block probing the next one
+	multi.CheckHealth(context.Background())
+	status := multi.Status()
+	assert.False(t, status[0].Healthy)
+	assert.True(t, status[1].Healthy)
+	healthy.AssertNumberOfCalls(t, "MaxBlobSize", 1)
+}
+
+func TestMultiDANoFailoverOnBlobError(t *testing.T) {
+	primary, secondary := &damock.MockDA{}, &damock.MockDA{}
+	primary.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("tx too large"))
+
+	multi, err := NewMultiDA([]Endpoint{{"primary", primary}, {"secondary", secondary}}, false, log.TestingLogger())
+	require.NoError(t, err)
+
+	_, err = multi.Submit(context.Background(), []da.Blob{{1}}, -1, nil)
+	assert.ErrorContains(t, err, "tx too large")
+	assert.True(t, multi.Status()[0].Healthy)
+	secondary.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMultiDAFanOut(t *testing.T) {
+	primary, secondary, broken := &damock.MockDA{}, &damock.MockDA{}, &damock.MockDA{}
+	primary.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]da.ID{makeID(10)}, nil)
+	secondary.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]da.ID{makeID(20)}, nil)
+	broken.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errConnRefused)
+	primary.On("Commit", mock.Anything, mock.Anything, mock.Anything).Return([]da.Commitment{{1}}, nil)
+	primary.On("GetProofs", mock.Anything, mock.Anything, mock.Anything).Return([]da.Proof{{2}}, nil)
+
+	multi, err := NewMultiDA([]Endpoint{{"broken", broken}, {"primary", primary}, {"secondary", secondary}}, true, log.TestingLogger())
+	require.NoError(t, err)
+
+	dalc := NewDAClient(multi, -1, -1, nil, nil, log.TestingLogger())
+	header, _ := types.GetRandomBlock(1, 0, "TestMultiDAFanOut")
+	res := dalc.SubmitHeaders(context.Background(), []*types.SignedHeader{header}, 1<<20, -1)
+	require.Equal(t, StatusSuccess, res.Code, res.Message)
+	assert.EqualValues(t, 10, res.DAHeight)
+	assert.Equal(t, []Inclusion{{Endpoint: "primary", DAHeight: 10}, {Endpoint: "secondary", DAHeight: 20}}, res.Inclusions)
+	assert.Equal(t, []da.Proof{{2}}, res.Proofs)
+	assert.False(t, multi.Status()[0].Healthy)
+}
+
+func TestMultiDANoEndpoints(t *testing.T) {
+	_, err := NewMultiDA(nil, false, log.TestingLogger())
+	assert.ErrorIs(t, err, ErrNoEndpoints)
+}
+
+func TestIsEndpointError(t *testing.T) {
+	cases := []struct {
+		err      error
+		endpoint bool
+	}{
+		{errConnRefused, true},
+		{fmt.Errorf("wrapped: %w", errConnRefused), true},
+		{io.ErrUnexpectedEOF, true},
+		{context.DeadlineExceeded, true},
+		{status.Error(codes.Unavailable, "connection closed"), true},
+		{&HTTPStatusError{StatusCode: 502, Status: "502 Bad Gateway"}, true},
+		{fmt.Errorf("wrapped: %w", &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}), true},
+		{errors.New("request failed, http status 404 Not Found"), false},
+		{status.Error(codes.Unknown, "blob not found"), false},
+		{status.Error(codes.InvalidArgument, "tx too large"), false},
+		{errors.New("tx too large"), false},
+		{fmt.Errorf("%w: %w", ErrTxAlreadyInMempool, errors.New("rpc error")), false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.endpoint, isEndpointError(c.err), c.err.Error())
+	}
+}
@@ -679,6 +679,10 @@ type mempoolTx struct {
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
+
+	// libp2p peers this tx was sent to by mempool reactor. It's dropped together with the tx.
+	// sentTo: peer.ID -> struct{}
+	sentTo sync.Map
 }
 
 // Height returns the height for this transaction
@@ -0,0 +1,304 @@
+package mempool
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/rollkit/rollkit/mempool/clist"
+)
+
+const (
+	// reactorRetryInterval is the delay before reopening broadcast stream after failure.
+	reactorRetryInterval = 1 * time.Second
+
+	// reactorMaxRetries is the number of attempts to open broadcast stream to a peer.
+	reactorMaxRetries = 3
+)
+
+// PeerIDs assigns mempool sender IDs to peers.
+type PeerIDs interface {
+	// GetForPeer returns sender ID of the peer, reserving a new one if required.
+	GetForPeer(peer.ID) uint16
+	// Reclaim returns sender ID of disconnected peer back to the pool.
+	Reclaim(peer.ID)
+}
+
+// TxReceiver handles transactions received from peers.
+type TxReceiver interface {
+	// ReceiveTx validates transaction received from the peer and adds it to the mempool.
+	ReceiveTx(tx types.Tx, from peer.ID)
+	// ReportInvalid reports peer that sent malformed or too large transaction.
+	ReportInvalid(from peer.ID)
+}
+
+// Reactor broadcasts transactions from the mempool to connected peers and passes transactions received from peers
+// to TxReceiver.
+//
+// Every transaction is sent to each peer at most once, also if the stream is reopened, and never to peers it was
+// received from. Transactions are broadcast to at most maxPeers peers. When a new peer connects, all transactions
+// still in the mempool are sent to it.
+type Reactor struct {
+	mempool    *CListMempool
+	host       host.Host
+	protocolID protocol.ID
+	ids        PeerIDs
+	receiver   TxReceiver
+	maxPeers   int
+
+	mtx   sync.Mutex
+	peers map[peer.ID]context.CancelFunc
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+	logger log.Logger
+}
+
+// NewReactor creates new mempool reactor. Transactions are exchanged using libp2p protocol specific to the chain.
+// If maxPeers is 0, transactions from mempool are not broadcast, but transactions from peers are still accepted.
+func NewReactor(mempool *CListMempool, chainID string, ids PeerIDs, receiver TxReceiver, maxPeers int, logger log.Logger) *Reactor {
+	return &Reactor{
+		mempool:    mempool,
+		protocolID: ProtocolID(chainID),
+		ids:        ids,
+		receiver:   receiver,
+		maxPeers:   maxPeers,
+		peers:      make(map[peer.ID]context.CancelFunc),
+		logger:     logger,
+	}
+}
+
+// ProtocolID returns libp2p protocol ID used by mempool reactor for given chain.
+func ProtocolID(chainID string) protocol.ID {
+	return protocol.ID("/rollkit/" + chainID + "/mempool/1.0.0")
+}
+
+// Start registers stream handler on the host and starts broadcasting transactions to connected peers.
+func (r *Reactor) Start(ctx context.Context, h host.Host) error {
+	r.host = h
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.host.SetStreamHandler(r.protocolID, r.handleStream)
+
+	sub, err := r.host.EventBus().Subscribe([]interface{}{
+		new(event.EvtPeerIdentificationCompleted),
+		new(event.EvtPeerProtocolsUpdated),
+		new(event.EvtPeerConnectednessChanged),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to peer events: %w", err)
+	}
+	r.wg.Add(1)
+	go r.peerEventsLoop(ctx, sub)
+
+	for _, p := range r.host.Network().Peers() {
+		r.tryAddPeer(ctx, p)
+	}
+	return nil
+}
+
+// Stop stops broadcasting transactions and removes stream handler.
+func (r *Reactor) Stop() {
+	if r.host == nil {
+		return
+	}
+	r.host.RemoveStreamHandler(r.protocolID)
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Reactor) peerEventsLoop(ctx context.Context, sub event.Subscription) {
+	defer r.wg.Done()
+	defer sub.Close() //nolint:errcheck
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-sub.Out():
+			if !ok {
+				return
+			}
+			switch evt := e.(type) {
+			case event.EvtPeerIdentificationCompleted:
+				r.tryAddPeer(ctx, evt.Peer)
+			case event.EvtPeerProtocolsUpdated:
+				r.tryAddPeer(ctx, evt.Peer)
+			case event.EvtPeerConnectednessChanged:
+				if evt.Connectedness == network.NotConnected {
+					r.removePeer(evt.Peer)
+					r.ids.Reclaim(evt.Peer)
+					r.fillPeers(ctx)
+				}
+			}
+		}
+	}
+}
+
+// tryAddPeer starts broadcasting transactions to the peer, if it supports mempool protocol and peer limit is not reached.
+func (r *Reactor) tryAddPeer(ctx context.Context, p peer.ID) {
+	if p == r.host.ID() || ctx.Err() != nil {
+		return
+	}
+	if protocols, err := r.host.Peerstore().SupportsProtocols(p, r.protocolID); err != nil || len(protocols) == 0 {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.peers[p]; ok || len(r.peers) >= r.maxPeers {
+		return
+	}
+	peerCtx, cancel := context.WithCancel(ctx)
+	r.peers[p] = cancel
+	r.wg.Add(1)
+	go r.broadcastTxRoutine(peerCtx, p)
+}
+
+func (r *Reactor) removePeer(p peer.ID) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if cancel, ok := r.peers[p]; ok {
+		cancel()
+		delete(r.peers, p)
+	}
+}
+
+// markSent records that transaction was sent to the peer. It returns false if it was already sent. The record is
+// kept with the transaction, so it's forgotten when transaction leaves the mempool.
+func markSent(memTx *mempoolTx, p peer.ID) bool {
+	_, sent := memTx.sentTo.LoadOrStore(p, struct{}{})
+	return !sent
+}
+
+// fillPeers tries to replace disconnected peers with other connected peers.
+func (r *Reactor) fillPeers(ctx context.Context) {
+	for _, p := range r.host.Network().Peers() {
+		r.tryAddPeer(ctx, p)
+	}
+}
+
+// broadcastTxRoutine walks through the mempool and sends transactions to the peer.
+func (r *Reactor) broadcastTxRoutine(ctx context.Context, p peer.ID) {
+	defer r.wg.Done()
+	defer r.removePeer(p)
+
+	stream, err := r.openStream(ctx, p)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Debug("failed to open mempool stream", "peer", p, "err", err)
+		}
+		return
+	}
+	defer stream.Close() //nolint:errcheck
+	writer := bufio.NewWriter(stream)
+
+	peerID := r.ids.GetForPeer(p)
+	var next *clist.CElement
+	for {
+		// start from the beginning of the mempool, or wait for a new transaction
+		if next == nil {
+			select {
+			case <-r.mempool.TxsWaitChan():
+				if next = r.mempool.TxsFront(); next == nil {
+					continue
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		memTx := next.Value.(*mempoolTx)
+		if _, ok := memTx.senders.Load(peerID); !ok && markSent(memTx, p) {
+			if err := writeTx(writer, memTx.tx); err != nil {
+				if ctx.Err() == nil {
+					r.logger.Debug("failed to send transaction", "peer", p, "err", err)
+				}
+				return
+			}
+		}
+
+		select {
+		case <-next.NextWaitChan():
+			next = next.Next()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Reactor) openStream(ctx context.Context, p peer.ID) (network.Stream, error) {
+	var err error
+	for i := 0; i < reactorMaxRetries; i++ {
+		var stream network.Stream
+		stream, err = r.host.NewStream(ctx, p, r.protocolID)
+		if err == nil {
+			return stream, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(reactorRetryInterval):
+		}
+	}
+	return nil, err
+}
+
+// handleStream reads transactions sent by the peer and passes them to the receiver.
+func (r *Reactor) handleStream(stream network.Stream) {
+	defer stream.Close() //nolint:errcheck
+	p := stream.Conn().RemotePeer()
+	reader := bufio.NewReader(stream)
+	for {
+		tx, err := readTx(reader, r.mempool.config.MaxTxBytes)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				r.logger.Debug("failed to receive transaction", "peer", p, "err", err)
+				if errors.As(err, new(ErrTxTooLarge)) {
+					r.receiver.ReportInvalid(p)
+				}
+				_ = stream.Reset()
+			}
+			return
+		}
+		r.receiver.ReceiveTx(tx, p)
+	}
+}
+
+// writeTx writes length-prefixed transaction and flushes the writer.
+func writeTx(w *bufio.Writer, tx types.Tx) error {
+	if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(tx)))); err != nil {
+		return err
+	}
+	if _, err := w.Write(tx); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// readTx reads length-prefixed transaction.
+func readTx(r *bufio.Reader, maxTxBytes int) (types.Tx, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(maxTxBytes) { //nolint:gosec
+		return nil, ErrTxTooLarge{Max: maxTxBytes, Actual: int(size)} //nolint:gosec
+	}
+	tx := make(types.Tx, size)
+	if _, err := io.ReadFull(r, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
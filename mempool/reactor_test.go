@@ -0,0 +1,214 @@
+package mempool
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPeerIDs struct {
+	mtx sync.Mutex
+	ids map[peer.ID]uint16
+}
+
+func (ids *testPeerIDs) GetForPeer(p peer.ID) uint16 {
+	ids.mtx.Lock()
+	defer ids.mtx.Unlock()
+	id, ok := ids.ids[p]
+	if !ok {
+		id = uint16(len(ids.ids) + 1) //nolint:gosec
+		ids.ids[p] = id
+	}
+	return id
+}
+
+func (ids *testPeerIDs) Reclaim(peer.ID) {}
+
+// testTxReceiver adds received transactions to the mempool and counts them.
+type testTxReceiver struct {
+	mempool *CListMempool
+	ids     PeerIDs
+
+	mtx      sync.Mutex
+	received int
+	invalid  []peer.ID
+}
+
+func (r *testTxReceiver) ReceiveTx(tx types.Tx, from peer.ID) {
+	r.mtx.Lock()
+	r.received++
+	r.mtx.Unlock()
+	_ = r.mempool.CheckTx(tx, nil, TxInfo{SenderID: r.ids.GetForPeer(from)})
+}
+
+func (r *testTxReceiver) ReportInvalid(from peer.ID) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.invalid = append(r.invalid, from)
+}
+
+func startTestReactors(ctx context.Context, t *testing.T, n int, maxPeers int) ([]*CListMempool, []*Reactor) {
+	t.Helper()
+	require := require.New(t)
+
+	mnet := mocknet.New()
+	mempools := make([]*CListMempool, n)
+	reactors := make([]*Reactor, n)
+	for i := 0; i < n; i++ {
+		h, err := mnet.GenPeer()
+		require.NoError(err)
+		mp, cleanup := newMempoolWithApp(proxy.NewLocalClientCreator(kvstore.NewInMemoryApplication()))
+		t.Cleanup(cleanup)
+		mempools[i] = mp
+		ids := &testPeerIDs{ids: make(map[peer.ID]uint16)}
+		receiver := &testTxReceiver{mempool: mp, ids: ids}
+		reactors[i] = NewReactor(mp, "TestChain", ids, receiver, maxPeers, log.TestingLogger())
+		require.NoError(reactors[i].Start(ctx, h))
+		t.Cleanup(reactors[i].Stop)
+	}
+	require.NoError(mnet.LinkAll())
+	return mempools, reactors
+}
+
+func waitForTxs(t *testing.T, mp *CListMempool, txs types.Txs) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, tx := range txs {
+			if _, ok := mp.txsMap.Load(tx.Key()); !ok {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestReactorBroadcastTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mempools, reactors := startTestReactors(ctx, t, 3, 10)
+
+	// transactions already in the mempool are sent to newly connected peers
+	txs := checkTxs(t, mempools[0], 10, UnknownPeerID)
+	_, err := reactors[0].host.Network().DialPeer(ctx, reactors[1].host.ID())
+	require.NoError(t, err)
+	waitForTxs(t, mempools[1], txs)
+
+	// new transactions are forwarded through peers
+	_, err = reactors[1].host.Network().DialPeer(ctx, reactors[2].host.ID())
+	require.NoError(t, err)
+	txs = append(txs, checkTxs(t, mempools[0], 10, UnknownPeerID)...)
+	waitForTxs(t, mempools[2], txs)
+	assert.Equal(t, 20, mempools[1].Size())
+
+	// transactions received from a peer are not sent back to it
+	senderID := reactors[1].ids.GetForPeer(reactors[0].host.ID())
+	for e := mempools[1].TxsFront(); e != nil; e = e.Next() {
+		_, ok := e.Value.(*mempoolTx).senders.Load(senderID)
+		assert.True(t, ok)
+	}
+}
+
+func TestReactorMaxPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mempools, reactors := startTestReactors(ctx, t, 3, 1)
+	for _, i := range []int{1, 2} {
+		_, err := reactors[0].host.Network().DialPeer(ctx, reactors[i].host.ID())
+		require.NoError(t, err)
+	}
+
+	txs := checkTxs(t, mempools[0], 5, UnknownPeerID)
+	require.Eventually(t, func() bool {
+		return mempools[1].Size()+mempools[2].Size() == len(txs)
+	}, 5*time.Second, 20*time.Millisecond)
+
+	reactors[0].mtx.Lock()
+	assert.Len(t, reactors[0].peers, 1)
+	reactors[0].mtx.Unlock()
+}
+
+func TestReactorReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mempools, reactors := startTestReactors(ctx, t, 2, 10)
+	receiver := reactors[1].receiver.(*testTxReceiver)
+	received := func() int {
+		receiver.mtx.Lock()
+		defer receiver.mtx.Unlock()
+		return receiver.received
+	}
+
+	txs := checkTxs(t, mempools[0], 5, UnknownPeerID)
+	_, err := reactors[0].host.Network().DialPeer(ctx, reactors[1].host.ID())
+	require.NoError(t, err)
+	waitForTxs(t, mempools[1], txs)
+
+	// transactions already sent are not sent again after reconnecting
+	require.NoError(t, reactors[0].host.Network().ClosePeer(reactors[1].host.ID()))
+	require.Eventually(t, func() bool {
+		reactors[0].mtx.Lock()
+		defer reactors[0].mtx.Unlock()
+		return len(reactors[0].peers) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+	_, err = reactors[0].host.Network().DialPeer(ctx, reactors[1].host.ID())
+	require.NoError(t, err)
+	txs = checkTxs(t, mempools[0], 1, UnknownPeerID)
+	waitForTxs(t, mempools[1], txs)
+	assert.Equal(t, 6, received())
+}
+
+func TestReactorTooLargeTx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mempools, reactors := startTestReactors(ctx, t, 2, 10)
+	_, err := reactors[0].host.Network().DialPeer(ctx, reactors[1].host.ID())
+	require.NoError(t, err)
+	stream, err := reactors[0].host.NewStream(ctx, reactors[1].host.ID(), reactors[1].protocolID)
+	require.NoError(t, err)
+	_, err = stream.Write(binary.AppendUvarint(nil, uint64(mempools[1].config.MaxTxBytes+1))) //nolint:gosec
+	require.NoError(t, err)
+
+	receiver := reactors[1].receiver.(*testTxReceiver)
+	require.Eventually(t, func() bool {
+		receiver.mtx.Lock()
+		defer receiver.mtx.Unlock()
+		return len(receiver.invalid) == 1 && receiver.invalid[0] == reactors[0].host.ID()
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestReactorReceiveOnly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mempools, reactors := startTestReactors(ctx, t, 2, 10)
+	// second node doesn't broadcast transactions, but still accepts them
+	reactors[1].mtx.Lock()
+	reactors[1].maxPeers = 0
+	reactors[1].mtx.Unlock()
+	_, err := reactors[0].host.Network().DialPeer(ctx, reactors[1].host.ID())
+	require.NoError(t, err)
+
+	txs := checkTxs(t, mempools[0], 5, UnknownPeerID)
+	waitForTxs(t, mempools[1], txs)
+	reactors[1].mtx.Lock()
+	assert.Empty(t, reactors[1].peers)
+	reactors[1].mtx.Unlock()
+
+	// transactions record peers they were sent to
+	assert.False(t, markSent(mempools[0].TxsFront().Value.(*mempoolTx), reactors[1].host.ID()))
+}
@@ -3,7 +3,6 @@ package node
 import (
 	"context"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,7 +10,9 @@ import (
 
 	ds "github.com/ipfs/go-datastore"
 	ktds "github.com/ipfs/go-datastore/keytransform"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
@@ -26,7 +27,7 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	cmtypes "github.com/cometbft/cometbft/types"
 
-	proxyda "github.com/rollkit/go-da/proxy"
+	goDA "github.com/rollkit/go-da"
 
 	seqGRPC "github.com/rollkit/go-sequencing/proxy/grpc"
 	"github.com/rollkit/rollkit/block"
@@ -36,9 +37,7 @@ import (
 	"github.com/rollkit/rollkit/p2p"
 	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/state/indexer"
-	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
 	"github.com/rollkit/rollkit/state/txindex"
-	"github.com/rollkit/rollkit/state/txindex/kv"
 	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/types"
 )
@@ -68,23 +67,24 @@ type FullNode struct {
 
 	nodeConfig config.NodeConfig
 
-	proxyApp     proxy.AppConns
-	eventBus     *cmtypes.EventBus
-	dalc         *da.DAClient
-	p2pClient    *p2p.Client
-	hSyncService *block.HeaderSyncService
-	dSyncService *block.DataSyncService
-	// TODO(tzdybal): consider extracting "mempool reactor"
-	Mempool      mempool.Mempool
-	mempoolIDs   *mempoolIDs
-	Store        store.Store
-	blockManager *block.Manager
-	client       rpcclient.Client
+	proxyApp       proxy.AppConns
+	eventBus       *cmtypes.EventBus
+	dalc           *da.DAClient
+	p2pClient      *p2p.Client
+	hSyncService   *block.HeaderSyncService
+	dSyncService   *block.DataSyncService
+	Mempool        mempool.Mempool
+	mempoolIDs     *mempoolIDs
+	mempoolReactor *mempool.Reactor
+	Store          store.Store
+	blockManager   *block.Manager
+	client         rpcclient.Client
 
 	// Preserves cometBFT compatibility
 	TxIndexer      txindex.TxIndexer
 	BlockIndexer   indexer.BlockIndexer
 	IndexerService *txindex.IndexerService
+	closeIndexers  func() error
 	prometheusSrv  *http.Server
 
 	// keep context here only because of API compatibility
@@ -117,7 +117,7 @@ func newFullNode(
 		}
 	}()
 
-	seqMetrics, p2pMetrics, memplMetrics, smMetrics, abciMetrics := metricsProvider(genesis.ChainID)
+	seqMetrics, p2pMetrics, memplMetrics, smMetrics, abciMetrics, indexerMetrics := metricsProvider(genesis.ChainID)
 
 	proxyApp, err := initProxyApp(clientCreator, logger, abciMetrics)
 	if err != nil {
@@ -167,7 +167,7 @@ func newFullNode(
 	}
 
 	indexerKV := newPrefixKV(baseKV, indexerPrefix)
-	indexerService, txIndexer, blockIndexer, err := createAndStartIndexerService(ctx, nodeConfig, indexerKV, eventBus, logger)
+	indexerService, txIndexer, blockIndexer, closeIndexers, err := createAndStartIndexerService(ctx, nodeConfig, indexerKV, store, genesis, eventBus, indexerMetrics, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +188,7 @@ func newFullNode(
 		TxIndexer:      txIndexer,
 		IndexerService: indexerService,
 		BlockIndexer:   blockIndexer,
+		closeIndexers:  closeIndexers,
 		hSyncService:   headerSyncService,
 		dSyncService:   dataSyncService,
 		ctx:            ctx,
@@ -196,7 +197,22 @@ func newFullNode(
 	}
 
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
-	node.p2pClient.SetTxValidator(node.newTxValidator(p2pMetrics))
+	txValidator := node.newTxValidator(p2pMetrics)
+	node.p2pClient.SetTxValidator(txValidator)
+	// reactor accepts transactions pushed by peers on every node, even if it doesn't broadcast them itself
+	node.mempoolReactor = initMempoolReactor(mempool, genesis.ChainID, node.mempoolIDs, txValidator, p2pClient, nodeConfig, logger)
+	if nodeConfig.P2P.CombinedBlockGossip {
+		self, err := peer.IDFromPrivateKey(p2pKey)
+		if err != nil {
+			return nil, err
+		}
+		node.p2pClient.SetBlockValidator(node.blockManager.BlockGossipValidator(self))
+		if nodeConfig.Aggregator {
+			node.blockManager.SetBlockGossiper(node.p2pClient)
+		} else {
+			node.blockManager.SetDataStoreWriter(node.dSyncService)
+		}
+	}
 	node.client = NewFullClient(node)
 
 	return node, nil
@@ -230,27 +246,52 @@ func initBaseKV(nodeConfig config.NodeConfig, logger log.Logger) (ds.TxnDatastor
 }
 
 func initDALC(nodeConfig config.NodeConfig, logger log.Logger) (*da.DAClient, error) {
-	namespace := make([]byte, len(nodeConfig.DANamespace)/2)
-	_, err := hex.Decode(namespace, []byte(nodeConfig.DANamespace))
-	if err != nil {
-		return nil, fmt.Errorf("error decoding namespace: %w", err)
+	if err := nodeConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid node configuration: %w", err)
 	}
-
-	if nodeConfig.DAGasMultiplier < 0 {
-		return nil, fmt.Errorf("gas multiplier must be greater than or equal to zero")
+	namespaces, err := nodeConfig.GetDANamespaces()
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := proxyda.NewClient(nodeConfig.DAAddress, nodeConfig.DAAuthToken)
+	client, err := initDA(nodeConfig, logger)
 	if err != nil {
-		return nil, fmt.Errorf("error while establishing connection to DA layer: %w", err)
+		return nil, err
 	}
 
 	var submitOpts []byte
 	if nodeConfig.DASubmitOptions != "" {
 		submitOpts = []byte(nodeConfig.DASubmitOptions)
 	}
-	return da.NewDAClient(client, nodeConfig.DAGasPrice, nodeConfig.DAGasMultiplier,
-		namespace, submitOpts, logger.With("module", "da_client")), nil
+	dalc := da.NewDAClient(client, nodeConfig.DAGasPrice, nodeConfig.DAGasMultiplier,
+		namespaces.Header, submitOpts, logger.With("module", "da_client"))
+	dalc.DataNamespace = namespaces.Data
+	dalc.ForcedInclusionNamespace = namespaces.ForcedInclusion
+	if nodeConfig.DASubmitTimeout != 0 {
+		dalc.SubmitTimeout = nodeConfig.DASubmitTimeout
+	}
+	if nodeConfig.DARetrieveTimeout != 0 {
+		dalc.RetrieveTimeout = nodeConfig.DARetrieveTimeout
+	}
+	return dalc, nil
+}
+
+// initDA connects to configured DA addresses. If fallback addresses are configured,
+// all connections are wrapped into a single da.MultiDA.
+func initDA(nodeConfig config.NodeConfig, logger log.Logger) (goDA.DA, error) {
+	addresses := append([]string{nodeConfig.DAAddress}, nodeConfig.DAFallbackAddresses...)
+	endpoints := make([]da.Endpoint, 0, len(addresses))
+	for _, addr := range addresses {
+		client, err := da.NewClient(addr, nodeConfig.DAAuthToken)
+		if err != nil {
+			return nil, fmt.Errorf("error while establishing connection to DA layer at %s: %w", addr, err)
+		}
+		endpoints = append(endpoints, da.Endpoint{Name: addr, DA: client})
+	}
+	if len(endpoints) == 1 && !nodeConfig.DAFanOut {
+		return endpoints[0].DA, nil
+	}
+	return da.NewMultiDA(endpoints, nodeConfig.DAFanOut, logger.With("module", "da_multi"))
 }
 
 func initMempool(proxyApp proxy.AppConns, memplMetrics *mempool.Metrics) *mempool.CListMempool {
@@ -259,6 +300,15 @@ func initMempool(proxyApp proxy.AppConns, memplMetrics *mempool.Metrics) *mempoo
 	return mempool
 }
 
+func initMempoolReactor(m *mempool.CListMempool, chainID string, ids *mempoolIDs, txValidator p2p.GossipValidator, p2pClient *p2p.Client, nodeConfig config.NodeConfig, logger log.Logger) *mempool.Reactor {
+	receiver := &mempoolTxReceiver{
+		validator: txValidator,
+		p2p:       p2pClient,
+		protocol:  string(mempool.ProtocolID(chainID)),
+	}
+	return mempool.NewReactor(m, chainID, ids, receiver, int(nodeConfig.MempoolBroadcastPeers), logger.With("module", "mempool")) //nolint:gosec
+}
+
 func initMempoolReaper(m mempool.Mempool, rollupID []byte, seqClient *seqGRPC.Client, logger log.Logger) *mempool.CListMempoolReaper {
 	return mempool.NewCListMempoolReaper(m, rollupID, seqClient, logger)
 }
@@ -332,11 +382,17 @@ func (n *FullNode) headerPublishLoop(ctx context.Context) {
 	}
 }
 
+// dataPublishLoop broadcasts produced block data. If combined block gossip is enabled, data is gossiped with the
+// header on the combined block topic, so it's only written to the store, to be served to peers on request.
 func (n *FullNode) dataPublishLoop(ctx context.Context) {
+	publish := n.dSyncService.WriteToStoreAndBroadcast
+	if n.nodeConfig.P2P.CombinedBlockGossip {
+		publish = n.dSyncService.WriteToStore
+	}
 	for {
 		select {
 		case data := <-n.blockManager.DataCh:
-			err := n.dSyncService.WriteToStoreAndBroadcast(ctx, data)
+			err := publish(ctx, data)
 			if err != nil {
 				// failed to init or start blockstore
 				n.Logger.Error(err.Error())
@@ -392,6 +448,12 @@ func (n *FullNode) OnStart() error {
 		return fmt.Errorf("error while starting P2P client: %w", err)
 	}
 
+	if n.mempoolReactor != nil {
+		if err = n.mempoolReactor.Start(n.ctx, n.p2pClient.Host()); err != nil {
+			return fmt.Errorf("error while starting mempool reactor: %w", err)
+		}
+	}
+
 	if err = n.hSyncService.Start(n.ctx); err != nil {
 		return fmt.Errorf("error while starting header sync service: %w", err)
 	}
@@ -400,6 +462,10 @@ func (n *FullNode) OnStart() error {
 		return fmt.Errorf("error while starting data sync service: %w", err)
 	}
 
+	if multiDA, ok := n.dalc.DA.(*da.MultiDA); ok {
+		n.threadManager.Go(func() { multiDA.HealthCheckLoop(n.ctx) })
+	}
+
 	if err := n.seqClient.Start(
 		n.nodeConfig.SequencerAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -409,6 +475,13 @@ func (n *FullNode) OnStart() error {
 
 	if n.nodeConfig.Aggregator {
 		n.Logger.Info("working in aggregator mode", "block time", n.nodeConfig.BlockTime)
+		if directPeers := n.p2pClient.DirectPeers(); len(directPeers) > 0 {
+			n.Logger.Info("pushing blocks directly to trusted full nodes", "peers", len(directPeers))
+			pusher := block.NewDirectBlockPusher(n.p2pClient.Host(), n.genesis.ChainID, directPeers, n.Logger.With("module", "direct-blocks"))
+			n.blockManager.SetDirectBlockPusher(pusher)
+			pusher.Start(n.ctx)
+			n.threadManager.Go(pusher.Wait)
+		}
 		// reaper is started only in aggregator mode
 		if err = n.mempoolReaper.StartReaper(n.ctx); err != nil {
 			return fmt.Errorf("error while starting mempool reaper: %w", err)
@@ -420,6 +493,14 @@ func (n *FullNode) OnStart() error {
 		n.threadManager.Go(func() { n.dataPublishLoop(n.ctx) })
 		return nil
 	}
+	if directPeers := n.p2pClient.DirectPeers(); len(directPeers) > 0 {
+		senders := make([]peer.ID, len(directPeers))
+		for i, p := range directPeers {
+			senders[i] = p.ID
+		}
+		n.Logger.Info("accepting blocks pushed directly by trusted peers", "peers", len(senders))
+		n.p2pClient.Host().SetStreamHandler(block.DirectBlockProtocolID(n.genesis.ChainID), n.blockManager.DirectBlockStreamHandler(senders))
+	}
 	n.threadManager.Go(func() { n.blockManager.RetrieveLoop(n.ctx) })
 	n.threadManager.Go(func() { n.blockManager.HeaderStoreRetrieveLoop(n.ctx) })
 	n.threadManager.Go(func() { n.blockManager.DataStoreRetrieveLoop(n.ctx) })
@@ -449,12 +530,16 @@ func (n *FullNode) GetGenesisChunks() ([]string, error) {
 func (n *FullNode) OnStop() {
 	n.Logger.Info("halting full node...")
 	n.Logger.Info("shutting down full node sub services...")
+	if n.mempoolReactor != nil {
+		n.mempoolReactor.Stop()
+	}
 	err := errors.Join(
 		n.p2pClient.Close(),
 		n.hSyncService.Stop(n.ctx),
 		n.dSyncService.Stop(n.ctx),
 		n.seqClient.Stop(),
 		n.IndexerService.Stop(),
+		n.closeIndexers(),
 	)
 	if n.prometheusSrv != nil {
 		err = errors.Join(err, n.prometheusSrv.Shutdown(n.ctx))
@@ -494,9 +579,14 @@ func (n *FullNode) AppClient() proxy.AppConns {
 }
 
 // newTxValidator creates a pubsub validator that uses the node's mempool to check the
-// transaction. If the transaction is valid, then it is added to the mempool
+// transaction. If the transaction is valid, then it is added to the mempool.
+//
+// Too large transactions and transactions failing the pre-check are invalid on every node, so they are rejected
+// and count towards banning the sender. Transactions failing CheckTx are ignored, as CheckTx result depends on
+// the state of the application (e.g. nonce or balance), and peers relay transactions in good faith. Transactions
+// that couldn't be checked because of errors of the node are ignored as well.
 func (n *FullNode) newTxValidator(metrics *p2p.Metrics) p2p.GossipValidator {
-	return func(m *p2p.GossipMessage) bool {
+	return func(m *p2p.GossipMessage) pubsub.ValidationResult {
 		n.Logger.Debug("transaction received", "bytes", len(m.Data))
 		msgBytes := m.Data
 		labels := []string{
@@ -518,18 +608,22 @@ func (n *FullNode) newTxValidator(metrics *p2p.Metrics) p2p.GossipValidator {
 		})
 		switch {
 		case errors.Is(err, mempool.ErrTxInCache):
-			return true
-		case errors.Is(err, mempool.ErrMempoolIsFull{}):
-			return true
-		case errors.Is(err, mempool.ErrTxTooLarge{}):
-			return false
-		case errors.Is(err, mempool.ErrPreCheck{}):
-			return false
-		default:
+			return pubsub.ValidationAccept
+		case errors.As(err, new(mempool.ErrMempoolIsFull)):
+			return pubsub.ValidationAccept
+		case errors.As(err, new(mempool.ErrTxTooLarge)):
+			return pubsub.ValidationReject
+		case mempool.IsPreCheckError(err):
+			return pubsub.ValidationReject
+		case err != nil:
+			return pubsub.ValidationIgnore
 		}
 		checkTxResp := <-checkTxResCh
 
-		return checkTxResp.Code == abci.CodeTypeOK
+		if checkTxResp.Code != abci.CodeTypeOK {
+			return pubsub.ValidationIgnore
+		}
+		return pubsub.ValidationAccept
 	}
 }
 
@@ -541,23 +635,42 @@ func createAndStartIndexerService(
 	ctx context.Context,
 	conf config.NodeConfig,
 	kvStore ds.TxnDatastore,
+	blocks store.Store,
+	genesis *cmtypes.GenesisDoc,
 	eventBus *cmtypes.EventBus,
+	metrics *txindex.Metrics,
 	logger log.Logger,
-) (*txindex.IndexerService, txindex.TxIndexer, indexer.BlockIndexer, error) {
-	var (
-		txIndexer    txindex.TxIndexer
-		blockIndexer indexer.BlockIndexer
-	)
+) (*txindex.IndexerService, txindex.TxIndexer, indexer.BlockIndexer, func() error, error) {
+	logger = logger.With("module", "txindex")
+	txIndexer, blockIndexer, closeIndexers, err := initIndexers(ctx, conf.Indexer, genesis.ChainID, kvStore)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
-	txIndexer = kv.NewTxIndex(ctx, kvStore)
-	blockIndexer = blockidxkv.New(ctx, newPrefixKV(kvStore, "block_events"))
+	// blocks missing in indexes are reindexed by the indexer service in the background, while new blocks are
+	// indexed; failure to find them is not fatal, as missing blocks can be reindexed later (see Reindex)
+	statusKV := newPrefixKV(kvStore, indexerStatusPrefix)
+	indexedHeight, latestHeight, legacyKV, heightErr := indexedHeights(ctx, blocks, txIndexer, blockIndexer, statusKV, uint64(genesis.InitialHeight), logger) //nolint:gosec
+	if heightErr != nil {
+		logger.Error("failed to find blocks missing in indexes", "err", heightErr)
+	}
 
 	indexerService := txindex.NewIndexerService(ctx, txIndexer, blockIndexer, eventBus, false)
-	indexerService.SetLogger(logger.With("module", "txindex"))
+	indexerService.SetLogger(logger)
+	indexerService.SetMetrics(metrics)
+	indexerService.TrackIndexedHeight(statusKV, indexedHeight, latestHeight)
+	if heightErr != nil {
+		indexerService.ReportError(fmt.Errorf("failed to find blocks missing in indexes: %w", heightErr))
+	} else {
+		indexerService.ReindexMissingBlocks(blocks)
+		if legacyKV {
+			indexerService.UpgradeIndexFormat()
+		}
+	}
 
 	if err := indexerService.Start(); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, errors.Join(err, closeIndexers())
 	}
 
-	return indexerService, txIndexer, blockIndexer, nil
+	return indexerService, txIndexer, blockIndexer, closeIndexers, nil
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"time"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -18,9 +17,14 @@ import (
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cometbft/cometbft/version"
+	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/rollkit/rollkit/block"
 	rconfig "github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/types"
 	abciconv "github.com/rollkit/rollkit/types/abci"
 )
@@ -36,6 +40,9 @@ const (
 var (
 	// ErrConsensusStateNotAvailable is returned because Rollkit doesn't use Tendermint consensus.
 	ErrConsensusStateNotAvailable = errors.New("consensus state not available in Rollkit")
+
+	// ErrUnsafeRPCDisabled is returned by administrative methods if unsafe RPC is not enabled.
+	ErrUnsafeRPCDisabled = errors.New("unsafe RPC methods are disabled (use --rpc.unsafe to enable them)")
 )
 
 var _ rpcclient.Client = &FullClient{}
@@ -140,7 +147,7 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 	}
 
 	// broadcast tx
-	err = c.node.p2pClient.GossipTx(ctx, tx)
+	err = c.gossipTx(ctx, tx)
 	if err != nil {
 		return nil, fmt.Errorf("tx added to local mempool but failure to broadcast: %w", err)
 	}
@@ -189,7 +196,7 @@ func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (*ctyp
 		return nil, err
 	}
 	// gossipTx optimistically
-	err = c.node.p2pClient.GossipTx(ctx, tx)
+	err = c.gossipTx(ctx, tx)
 	if err != nil {
 		return nil, fmt.Errorf("tx added to local mempool but failed to gossip: %w", err)
 	}
@@ -217,7 +224,7 @@ func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (*ctype
 	// Note: we have to do this here because, unlike the tendermint mempool reactor, there
 	// is no routine that gossips transactions after they enter the pool
 	if res.Code == abci.CodeTypeOK {
-		err = c.node.p2pClient.GossipTx(ctx, tx)
+		err = c.gossipTx(ctx, tx)
 		if err != nil {
 			// the transaction must be removed from the mempool if it cannot be gossiped.
 			// if this does not occur, then the user will not be able to try again using
@@ -237,6 +244,15 @@ func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (*ctype
 	}, nil
 }
 
+// gossipTx publishes transaction to the P2P network. If mempool reactor broadcasting is enabled, the reactor sends
+// transactions from the mempool to peers instead.
+func (c *FullClient) gossipTx(ctx context.Context, tx cmtypes.Tx) error {
+	if c.node.nodeConfig.MempoolBroadcastPeers > 0 {
+		return nil
+	}
+	return c.node.p2pClient.GossipTx(ctx, tx)
+}
+
 // Subscribe subscribe given subscriber to a query.
 func (c *FullClient) Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (out <-chan ctypes.ResultEvent, err error) {
 	q, err := cmquery.New(query)
@@ -365,6 +381,81 @@ func (c *FullClient) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error)
 	return &res, nil
 }
 
+// Peers returns detailed information about connected peers, including gossip scores and traffic counters.
+// It requires unsafe RPC to be enabled, as it exposes addresses and traffic of peers.
+func (c *FullClient) Peers(ctx context.Context) ([]p2p.PeerInfo, error) {
+	if !c.node.nodeConfig.RPC.Unsafe {
+		return nil, ErrUnsafeRPCDisabled
+	}
+	return c.node.p2pClient.PeerInfos(), nil
+}
+
+// P2PDiagnostics returns state of P2P networking, for debugging peering issues.
+// It requires unsafe RPC to be enabled, as it exposes addresses of the node and its peers.
+func (c *FullClient) P2PDiagnostics(ctx context.Context) (*p2p.Diagnostics, error) {
+	if !c.node.nodeConfig.RPC.Unsafe {
+		return nil, ErrUnsafeRPCDisabled
+	}
+	return c.node.p2pClient.Diagnostics(), nil
+}
+
+// IndexerStatus returns indexing progress of the node, including number of blocks that are not indexed yet.
+func (c *FullClient) IndexerStatus(ctx context.Context) (*txindex.Status, error) {
+	status := c.node.IndexerService.Status(c.node.Store.Height())
+	return &status, nil
+}
+
+// DAStatus returns status of publishing blocks to DA layer and retrieving blocks from it.
+func (c *FullClient) DAStatus(ctx context.Context) (*block.DAStatus, error) {
+	status := c.node.blockManager.DAStatus()
+	return &status, nil
+}
+
+// SequencerStatus returns status of block production by the node.
+func (c *FullClient) SequencerStatus(ctx context.Context) (*block.SequencerStatus, error) {
+	status := c.node.blockManager.SequencerStatus()
+	status.Aggregator = c.node.nodeConfig.Aggregator
+	return &status, nil
+}
+
+// DialPeer connects to the peer with given multiaddr. It requires unsafe RPC to be enabled.
+func (c *FullClient) DialPeer(ctx context.Context, addr string) error {
+	if !c.node.nodeConfig.RPC.Unsafe {
+		return ErrUnsafeRPCDisabled
+	}
+	return c.node.p2pClient.DialPeer(ctx, addr)
+}
+
+// BlockPeer blocks and disconnects the peer. It requires unsafe RPC to be enabled.
+func (c *FullClient) BlockPeer(ctx context.Context, id string) error {
+	return c.managePeer(id, func(p peer.ID) error {
+		return c.node.p2pClient.BlockPeer(ctx, p)
+	})
+}
+
+// UnblockPeer unblocks the peer, also if it was banned automatically. It requires unsafe RPC to be enabled.
+func (c *FullClient) UnblockPeer(ctx context.Context, id string) error {
+	return c.managePeer(id, func(p peer.ID) error {
+		return c.node.p2pClient.UnblockPeer(ctx, p)
+	})
+}
+
+// DisconnectPeer closes connections to the peer. It requires unsafe RPC to be enabled.
+func (c *FullClient) DisconnectPeer(ctx context.Context, id string) error {
+	return c.managePeer(id, c.node.p2pClient.DisconnectPeer)
+}
+
+func (c *FullClient) managePeer(id string, fn func(peer.ID) error) error {
+	if !c.node.nodeConfig.RPC.Unsafe {
+		return ErrUnsafeRPCDisabled
+	}
+	p, err := peer.Decode(id)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+	return fn(p)
+}
+
 // DumpConsensusState always returns error as there is no consensus state in Rollkit.
 func (c *FullClient) DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error) {
 	return nil, ErrConsensusStateNotAvailable
@@ -405,7 +496,11 @@ func (c *FullClient) ConsensusParams(ctx context.Context, height *int64) (*ctype
 }
 
 // Health endpoint returns empty value. It can be used to monitor service availability.
+// Error is returned if the node has stopped indexing blocks, or failed to index the latest blocks.
 func (c *FullClient) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
+	if err := c.node.IndexerService.Err(); err != nil {
+		return nil, fmt.Errorf("indexer is not healthy: %w", err)
+	}
 	return &ctypes.ResultHealth{}, nil
 }
 
@@ -496,6 +591,23 @@ func (c *FullClient) BlockResults(ctx context.Context, height *int64) (*ctypes.R
 	}, nil
 }
 
+// DAInclusionProof returns DA inclusion proof of block at given height.
+//
+// If height is nil, proof of the latest DA included block is returned. Proofs are stored only by the node that
+// submitted blocks to DA.
+func (c *FullClient) DAInclusionProof(ctx context.Context, height *int64) (*types.DAInclusionProof, error) {
+	var h uint64
+	if height == nil {
+		h = c.node.blockManager.GetDAIncludedHeight()
+	} else {
+		if *height <= 0 {
+			return nil, errors.New("height must be greater than zero")
+		}
+		h = uint64(*height)
+	}
+	return c.node.Store.GetDAInclusionProof(ctx, h)
+}
+
 // Commit returns signed header (aka commit) at given height.
 func (c *FullClient) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
 	heightValue := c.normalizeHeight(height)
@@ -585,52 +697,62 @@ func (c *FullClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.R
 
 // TxSearch returns detailed information about transactions matching query.
 func (c *FullClient) TxSearch(ctx context.Context, query string, prove bool, pagePtr, perPagePtr *int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return c.txSearch(ctx, query, prove, nil, pagePtr, perPagePtr, orderBy)
+}
+
+// TxSearchAfter returns detailed information about transactions matching query, like TxSearch, but only
+// transactions following the given position (usually of the last transaction of the previous page) in the
+// selected order are paginated and counted. Paging through results with a cursor, instead of page numbers,
+// doesn't skip entries of previous pages.
+func (c *FullClient) TxSearchAfter(ctx context.Context, query string, prove bool, after indexer.Position, pagePtr, perPagePtr *int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return c.txSearch(ctx, query, prove, &after, pagePtr, perPagePtr, orderBy)
+}
+
+func (c *FullClient) txSearch(ctx context.Context, query string, prove bool, after *indexer.Position, pagePtr, perPagePtr *int, orderBy string) (*ctypes.ResultTxSearch, error) {
 	q, err := cmquery.New(query)
 	if err != nil {
 		return nil, err
 	}
 
-	results, err := c.node.TxIndexer.Search(ctx, q)
+	orderDesc, err := parseOrderBy(orderBy)
 	if err != nil {
 		return nil, err
 	}
-
-	// sort results (must be done before pagination)
-	switch orderBy {
-	case "desc":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index > results[j].Index
-			}
-			return results[i].Height > results[j].Height
-		})
-	case "asc", "":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index < results[j].Index
-			}
-			return results[i].Height < results[j].Height
-		})
-	default:
-		return nil, errors.New("expected order_by to be either `asc` or `desc` or empty")
-	}
-
-	// paginate results
-	totalCount := len(results)
 	perPage := validatePerPage(perPagePtr)
 
-	page, err := validatePage(pagePtr, perPage, totalCount)
-	if err != nil {
-		return nil, err
+	var (
+		results    []*abci.TxResult
+		totalCount int
+	)
+	if idx, ok := c.node.TxIndexer.(txindex.PaginatedTxIndexer); ok {
+		// sort and paginate in the indexer, so that only transactions from requested page are loaded
+		results, totalCount, err = idx.SearchPage(ctx, q, searchPagination(after, pagePtr, perPage, orderDesc))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := validatePage(pagePtr, perPage, totalCount); err != nil {
+			return nil, err
+		}
+	} else {
+		results, err = c.node.TxIndexer.Search(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		// only results following the cursor are paginated
+		results, totalCount = indexer.Paginate(results, txResultPosition, indexer.Pagination{OrderDesc: orderDesc, After: after})
+		page, err := validatePage(pagePtr, perPage, totalCount)
+		if err != nil {
+			return nil, err
+		}
+		results, _ = indexer.Paginate(results, txResultPosition, indexer.Pagination{
+			OrderDesc: orderDesc,
+			Offset:    validateSkipCount(page, perPage),
+			Limit:     perPage,
+		})
 	}
 
-	skipCount := validateSkipCount(page, perPage)
-	pageSize := min(perPage, totalCount-skipCount)
-
-	apiResults := make([]*ctypes.ResultTx, 0, pageSize)
-	for i := skipCount; i < skipCount+pageSize; i++ {
-		r := results[i]
-
+	apiResults := make([]*ctypes.ResultTx, 0, len(results))
+	for _, r := range results {
 		var proof cmtypes.TxProof
 		/*if prove {
 			block := nil                               //env.BlockStore.GetBlock(r.Height)
@@ -653,47 +775,62 @@ func (c *FullClient) TxSearch(ctx context.Context, query string, prove bool, pag
 // BlockSearch defines a method to search for a paginated set of blocks by
 // BeginBlock and EndBlock event search criteria.
 func (c *FullClient) BlockSearch(ctx context.Context, query string, page, perPage *int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	return c.blockSearch(ctx, query, nil, page, perPage, orderBy)
+}
+
+// BlockSearchAfter searches for blocks like BlockSearch, but only blocks following the given height (usually of
+// the last block of the previous page) in the selected order are paginated and counted.
+func (c *FullClient) BlockSearchAfter(ctx context.Context, query string, afterHeight int64, page, perPage *int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	after := heightPosition(afterHeight)
+	return c.blockSearch(ctx, query, &after, page, perPage, orderBy)
+}
+
+func (c *FullClient) blockSearch(ctx context.Context, query string, after *indexer.Position, page, perPage *int, orderBy string) (*ctypes.ResultBlockSearch, error) {
 	q, err := cmquery.New(query)
 	if err != nil {
 		return nil, err
 	}
 
-	results, err := c.node.BlockIndexer.Search(ctx, q)
+	orderDesc, err := parseOrderBy(orderBy)
 	if err != nil {
 		return nil, err
 	}
-
-	// Sort the results
-	switch orderBy {
-	case "desc":
-		sort.Slice(results, func(i, j int) bool {
-			return results[i] > results[j]
-		})
-
-	case "asc", "":
-		sort.Slice(results, func(i, j int) bool {
-			return results[i] < results[j]
-		})
-	default:
-		return nil, errors.New("expected order_by to be either `asc` or `desc` or empty")
-	}
-
-	// Paginate
-	totalCount := len(results)
 	perPageVal := validatePerPage(perPage)
 
-	pageVal, err := validatePage(page, perPageVal, totalCount)
-	if err != nil {
-		return nil, err
+	var (
+		results    []int64
+		totalCount int
+	)
+	if idx, ok := c.node.BlockIndexer.(indexer.PaginatedBlockIndexer); ok {
+		results, totalCount, err = idx.SearchPage(ctx, q, searchPagination(after, page, perPageVal, orderDesc))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := validatePage(page, perPageVal, totalCount); err != nil {
+			return nil, err
+		}
+	} else {
+		results, err = c.node.BlockIndexer.Search(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		// only results following the cursor are paginated
+		results, totalCount = indexer.Paginate(results, heightPosition, indexer.Pagination{OrderDesc: orderDesc, After: after})
+		pageVal, err := validatePage(page, perPageVal, totalCount)
+		if err != nil {
+			return nil, err
+		}
+		results, _ = indexer.Paginate(results, heightPosition, indexer.Pagination{
+			OrderDesc: orderDesc,
+			Offset:    validateSkipCount(pageVal, perPageVal),
+			Limit:     perPageVal,
+		})
 	}
 
-	skipCount := validateSkipCount(pageVal, perPageVal)
-	pageSize := min(perPageVal, totalCount-skipCount)
-
 	// Fetch the blocks
-	blocks := make([]*ctypes.ResultBlock, 0, pageSize)
-	for i := skipCount; i < skipCount+pageSize; i++ {
-		header, data, err := c.node.Store.GetBlockData(ctx, uint64(results[i]))
+	blocks := make([]*ctypes.ResultBlock, 0, len(results))
+	for _, height := range results {
+		header, data, err := c.node.Store.GetBlockData(ctx, uint64(height))
 		if err != nil {
 			return nil, err
 		}
@@ -766,6 +903,9 @@ func (c *FullClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 		return nil, fmt.Errorf("failed to load node p2p2 info: %w", err)
 	}
 	txIndexerStatus := "on"
+	if c.node.nodeConfig.Indexer.Tx.Mode == rconfig.IndexerModeNull {
+		txIndexerStatus = "off"
+	}
 
 	result := &ctypes.ResultStatus{
 		NodeInfo: corep2p.DefaultNodeInfo{
@@ -989,6 +1129,41 @@ func validatePage(pagePtr *int, perPage, totalCount int) (int, error) {
 	return page, nil
 }
 
+// parseOrderBy returns true if search results should be sorted in descending order.
+func parseOrderBy(orderBy string) (bool, error) {
+	switch orderBy {
+	case "desc":
+		return true, nil
+	case "asc", "":
+		return false, nil
+	default:
+		return false, errors.New("expected order_by to be either `asc` or `desc` or empty")
+	}
+}
+
+// searchPagination returns pagination of search results selecting requested page, counted from the cursor if it
+// is set. Page is validated after search, when total number of results is known.
+func searchPagination(after *indexer.Position, pagePtr *int, perPage int, orderDesc bool) indexer.Pagination {
+	page := 1
+	if pagePtr != nil {
+		page = *pagePtr
+	}
+	return indexer.Pagination{
+		OrderDesc: orderDesc,
+		After:     after,
+		Offset:    validateSkipCount(page, perPage),
+		Limit:     perPage,
+	}
+}
+
+func txResultPosition(r *abci.TxResult) indexer.Position {
+	return indexer.Position{Height: r.Height, Index: r.Index}
+}
+
+func heightPosition(height int64) indexer.Position {
+	return indexer.Position{Height: height}
+}
+
 func validateSkipCount(page, perPage int) int {
 	skipCount := (page - 1) * perPage
 	if skipCount < 0 {
@@ -369,6 +369,56 @@ func TestGetCommit(t *testing.T) {
 	})
 }
 
+func TestDAInclusionProof(t *testing.T) {
+	chainID := "TestDAInclusionProof"
+	require := require.New(t)
+	_, rpc := getRPC(t, chainID)
+
+	ctx := context.Background()
+	proof := &types.DAInclusionProof{
+		Height:     3,
+		DAHeight:   10,
+		Namespace:  []byte{1, 2, 3},
+		ID:         []byte{4, 5, 6},
+		Commitment: []byte{7, 8, 9},
+		Proof:      []byte{10, 11, 12},
+	}
+	require.NoError(rpc.node.Store.SaveDAInclusionProof(ctx, 3, proof))
+
+	h := int64(3)
+	res, err := rpc.DAInclusionProof(ctx, &h)
+	require.NoError(err)
+	require.Equal(proof, res)
+
+	h = 4
+	_, err = rpc.DAInclusionProof(ctx, &h)
+	require.Error(err)
+
+	for _, h := range []int64{0, -1} {
+		_, err = rpc.DAInclusionProof(ctx, &h)
+		require.ErrorContains(err, "height must be greater than zero")
+	}
+}
+
+func TestPeerManagementRequiresUnsafeRPC(t *testing.T) {
+	require := require.New(t)
+	_, rpc := getRPC(t, "TestPeerManagementRequiresUnsafeRPC")
+	ctx := context.Background()
+
+	_, err := rpc.Peers(ctx)
+	require.ErrorIs(err, ErrUnsafeRPCDisabled)
+	_, err = rpc.P2PDiagnostics(ctx)
+	require.ErrorIs(err, ErrUnsafeRPCDisabled)
+	require.ErrorIs(rpc.DialPeer(ctx, "/ip4/127.0.0.1/tcp/26656"), ErrUnsafeRPCDisabled)
+	require.ErrorIs(rpc.BlockPeer(ctx, "peer"), ErrUnsafeRPCDisabled)
+	require.ErrorIs(rpc.UnblockPeer(ctx, "peer"), ErrUnsafeRPCDisabled)
+	require.ErrorIs(rpc.DisconnectPeer(ctx, "peer"), ErrUnsafeRPCDisabled)
+
+	rpc.node.nodeConfig.RPC.Unsafe = true
+	err = rpc.BlockPeer(ctx, "peer")
+	require.ErrorContains(err, "invalid peer ID")
+}
+
 func TestCometBFTLightClientCompability(t *testing.T) {
 	chainID := "TestCometBFTLightClientCompability"
 	require := require.New(t)
@@ -496,6 +546,31 @@ func TestBlockSearch(t *testing.T) {
 		})
 
 	}
+
+	cursorTests := []struct {
+		query      string
+		after      int64
+		orderBy    string
+		totalCount int
+		heights    []int64
+	}{
+		{"block.height >= 2 AND end_event.foo <= 10", 7, "desc", 5, []int64{6, 5, 4}},
+		{"begin_event.proposer = 'FCAA001'", 6, "asc", 4, []int64{7, 8, 9}},
+		{"begin_event.proposer = 'FCAA001'", 8, "asc", 2, []int64{9, 10}},
+	}
+	for _, test := range cursorTests {
+		t.Run(fmt.Sprintf("%s after %d", test.query, test.after), func(t *testing.T) {
+			page, perPage := 1, 3
+			result, err := rpc.BlockSearchAfter(ctx, test.query, test.after, &page, &perPage, test.orderBy)
+			require.NoError(err)
+			assert.Equal(test.totalCount, result.TotalCount)
+			heights := make([]int64, 0, len(result.Blocks))
+			for _, b := range result.Blocks {
+				heights = append(heights, b.Block.Height)
+			}
+			assert.Equal(test.heights, heights)
+		})
+	}
 }
 
 func TestGetBlockByHash(t *testing.T) {
@@ -1015,6 +1090,27 @@ func TestStatus(t *testing.T) {
 		}
 		assert.Equal(rpc.config.ListenAddress, resp.NodeInfo.Other.RPCAddress)
 	})
+	t.Run("IndexerStatus", func(t *testing.T) {
+		status, err := rpc.IndexerStatus(context.Background())
+		require.NoError(err)
+		assert.Equal(latestHeader.Height(), status.LatestHeight)
+		assert.Equal(status.LatestHeight-status.IndexedHeight, status.Lag)
+	})
+	t.Run("DAStatus", func(t *testing.T) {
+		status, err := rpc.DAStatus(context.Background())
+		require.NoError(err)
+		assert.Zero(status.DAIncludedHeight)
+		assert.Equal(latestHeader.Height(), status.PendingHeaders)
+		assert.Nil(status.LastSubmission)
+	})
+	t.Run("SequencerStatus", func(t *testing.T) {
+		status, err := rpc.SequencerStatus(context.Background())
+		require.NoError(err)
+		assert.True(status.Aggregator)
+		assert.False(status.LazyAggregator)
+		assert.True(status.Proposer)
+		assert.Zero(status.BatchQueueLength)
+	})
 }
 
 func TestFutureGenesisTime(t *testing.T) {
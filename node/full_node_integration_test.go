@@ -680,6 +680,8 @@ func doTestMaxPending(maxPending uint64, t *testing.T) {
 	// make sure mock DA is not accepting any submissions
 	mockDA.On("MaxBlobSize", mock.Anything).Return(uint64(123456789), nil)
 	mockDA.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("DA not available"))
+	mockDA.On("Commit", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockDA.On("GetProofs", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	dalc := da.NewDAClient(mockDA, 1234, 5678, goDA.Namespace(MockDANamespace), nil, log.NewNopLogger())
 	require.NotNil(dalc)
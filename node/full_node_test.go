@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/da"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/p2p"
 	test "github.com/rollkit/rollkit/test/log"
 	"github.com/rollkit/rollkit/test/mocks"
 	"github.com/rollkit/rollkit/types"
@@ -57,6 +59,32 @@ func TestMempoolDirectly(t *testing.T) {
 	verifyMempoolSize(node, t)
 }
 
+// Tests that transactions failing CheckTx because of the application state don't count towards banning the
+// relaying peer, while invalid transactions do.
+func TestTxValidatorStateDependentFailures(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	app := &mocks.Application{}
+	app.On("InitChain", mock.Anything, mock.Anything).Return(&abci.ResponseInitChain{}, nil)
+	// e.g. nonce mismatch, which depends on the state of the application
+	app.On("CheckTx", mock.Anything, mock.Anything).Return(&abci.ResponseCheckTx{Code: 32, Log: "invalid sequence"}, nil)
+	node, _ := createAggregatorWithApp(ctx, "TestTxValidatorStateDependentFailures", app, 0, types.DefaultSigningKeyType, t)
+	fn := node.(*FullNode)
+
+	validator := fn.newTxValidator(p2p.NopMetrics())
+	relay := getPeerID(t)
+	for i := uint64(0); i <= config.DefaultNodeConfig.P2P.BanThreshold; i++ {
+		tx := []byte(fmt.Sprintf("tx%d", i))
+		assert.Equal(pubsub.ValidationIgnore, validator(&p2p.GossipMessage{Data: tx, From: relay}))
+	}
+	app.AssertNumberOfCalls(t, "CheckTx", int(config.DefaultNodeConfig.P2P.BanThreshold)+1)
+
+	// too large transactions are invalid on every node
+	tooLarge := make([]byte, cmconfig.DefaultMempoolConfig().MaxTxBytes+1)
+	assert.Equal(pubsub.ValidationReject, validator(&p2p.GossipMessage{Data: tooLarge, From: relay}))
+}
+
 // Tests that the node is able to sync multiple blocks even if blocks arrive out of order
 func TestTrySyncNextBlockMultiple(t *testing.T) {
 	chainID := "TestTrySyncNextBlockMultiple"
@@ -204,8 +232,10 @@ func TestPendingBlocks(t *testing.T) {
 	mockDA := new(damock.MockDA)
 	mockDA.On("MaxBlobSize", mock.Anything).Return(uint64(10240), nil)
 	mockDA.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("DA not available"))
+	mockDA.On("Commit", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockDA.On("GetProofs", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
-	dac := da.NewDAClient(mockDA, 1234, -1, goDA.Namespace(MockDAAddress), nil, nil)
+	dac := da.NewDAClient(mockDA, 1234, -1, goDA.Namespace(MockDAAddress), nil, test.NewLogger(t))
 	dbPath, err := os.MkdirTemp("", "testdb")
 	require.NoError(t, err)
 	defer func() {
@@ -21,7 +21,7 @@ func getMockDA(t *testing.T) *da.DAClient {
 	namespace := make([]byte, len(MockDANamespace)/2)
 	_, err := hex.Decode(namespace, []byte(MockDANamespace))
 	require.NoError(t, err)
-	return da.NewDAClient(goDATest.NewDummyDA(), -1, -1, namespace, nil, log.TestingLogger())
+	return da.NewDAClient(da.TypedErrors(goDATest.NewDummyDA()), -1, -1, namespace, nil, log.TestingLogger())
 }
 
 func TestMockTester(t *testing.T) {
@@ -11,6 +11,7 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	cmtypes "github.com/cometbft/cometbft/types"
 	ds "github.com/ipfs/go-datastore"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
 
 	"github.com/rollkit/rollkit/block"
@@ -61,7 +62,7 @@ func newLightNode(
 		}
 	}()
 
-	_, p2pMetrics, _, _, abciMetrics := metricsProvider(genesis.ChainID)
+	_, p2pMetrics, _, _, abciMetrics, _ := metricsProvider(genesis.ChainID)
 
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
 	proxyApp := proxy.NewAppConns(clientCreator, abciMetrics)
@@ -136,9 +137,9 @@ func (ln *LightNode) OnStop() {
 	ln.Logger.Error("errors while stopping node:", "errors", err)
 }
 
-// Dummy validator that always returns a callback function with boolean `false`
+// Dummy validator that ignores all messages, as light node doesn't process transactions.
 func (ln *LightNode) falseValidator() p2p.GossipValidator {
-	return func(*p2p.GossipMessage) bool {
-		return false
+	return func(*p2p.GossipMessage) pubsub.ValidationResult {
+		return pubsub.ValidationIgnore
 	}
 }
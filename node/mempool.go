@@ -5,7 +5,12 @@ import (
 	"math"
 	"sync"
 
+	cmtypes "github.com/cometbft/cometbft/types"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/p2p"
 )
 
 const (
@@ -81,3 +86,25 @@ func newMempoolIDs() *mempoolIDs {
 		nextID:    1, // reserve unknownPeerID(0) for mempoolReactor.BroadcastTx
 	}
 }
+
+// mempoolTxReceiver passes transactions received by mempool reactor through the same validator as gossiped
+// transactions. Senders of invalid transactions are reported to P2P client, so they count toward banning.
+type mempoolTxReceiver struct {
+	validator p2p.GossipValidator
+	p2p       *p2p.Client
+	protocol  string
+}
+
+var _ mempool.TxReceiver = &mempoolTxReceiver{}
+
+// ReceiveTx implements mempool.TxReceiver.
+func (r *mempoolTxReceiver) ReceiveTx(tx cmtypes.Tx, from peer.ID) {
+	if r.validator(&p2p.GossipMessage{Data: tx, From: from}) == pubsub.ValidationReject {
+		r.ReportInvalid(from)
+	}
+}
+
+// ReportInvalid implements mempool.TxReceiver.
+func (r *mempoolTxReceiver) ReportInvalid(from peer.ID) {
+	r.p2p.ReportInvalidMessage(from, r.protocol)
+}
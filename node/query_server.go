@@ -0,0 +1,148 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+// blockSubscriptionCapacity is the number of blocks buffered for a subscriber of the query service. Subscription is
+// canceled if the subscriber can't receive blocks fast enough.
+const blockSubscriptionCapacity = 100
+
+// querySubscriberID is used to generate unique subscriber names of query service block subscriptions.
+var querySubscriberID atomic.Uint64
+
+// queryServer implements the gRPC query service of a full node.
+type queryServer struct {
+	node *FullNode
+}
+
+var _ pb.QueryServiceServer = (*queryServer)(nil)
+
+// QueryServer returns the gRPC query service exposing blocks, transactions and state of the node.
+func (n *FullNode) QueryServer() pb.QueryServiceServer {
+	return &queryServer{node: n}
+}
+
+// GetBlock implements pb.QueryServiceServer.
+func (s *queryServer) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.GetBlockResponse, error) {
+	header, data, err := s.getBlockData(ctx, req.Height)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetBlockResponse{Header: header, Data: data}, nil
+}
+
+// GetHeader implements pb.QueryServiceServer.
+func (s *queryServer) GetHeader(ctx context.Context, req *pb.GetHeaderRequest) (*pb.GetHeaderResponse, error) {
+	header, _, err := s.getBlockData(ctx, req.Height)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetHeaderResponse{Header: header}, nil
+}
+
+// GetTx implements pb.QueryServiceServer.
+func (s *queryServer) GetTx(ctx context.Context, req *pb.GetTxRequest) (*pb.GetTxResponse, error) {
+	res, err := s.node.TxIndexer.Get(req.Hash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get tx: %v", err)
+	}
+	if res == nil {
+		return nil, status.Errorf(codes.NotFound, "tx %X not found", req.Hash)
+	}
+	return &pb.GetTxResponse{
+		Hash:   cmtypes.Tx(res.Tx).Hash(),
+		Height: uint64(res.Height), //nolint:gosec
+		Index:  res.Index,
+		Tx:     res.Tx,
+		Result: &res.Result,
+	}, nil
+}
+
+// GetState implements pb.QueryServiceServer.
+func (s *queryServer) GetState(ctx context.Context, _ *pb.GetStateRequest) (*pb.GetStateResponse, error) {
+	state, err := s.node.Store.GetState(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load state: %v", err)
+	}
+	pbState, err := state.ToProto()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode state: %v", err)
+	}
+	return &pb.GetStateResponse{State: pbState}, nil
+}
+
+// GetDAInclusion implements pb.QueryServiceServer.
+func (s *queryServer) GetDAInclusion(ctx context.Context, req *pb.GetDAInclusionRequest) (*pb.GetDAInclusionResponse, error) {
+	header, _, err := s.node.Store.GetBlockData(ctx, req.Height)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "block %d not found: %v", req.Height, err)
+	}
+	res := &pb.GetDAInclusionResponse{
+		Height:           req.Height,
+		DaIncludedHeight: s.node.blockManager.GetDAIncludedHeight(),
+	}
+	res.Included = req.Height <= res.DaIncludedHeight || s.node.blockManager.IsDAIncluded(header.Hash())
+	// proofs are stored only by the node that submitted the block
+	if proof, err := s.node.Store.GetDAInclusionProof(ctx, req.Height); err == nil {
+		res.DaHeight = proof.DAHeight
+	}
+	return res, nil
+}
+
+// SubscribeBlocks implements pb.QueryServiceServer.
+func (s *queryServer) SubscribeBlocks(_ *pb.SubscribeBlocksRequest, stream pb.QueryService_SubscribeBlocksServer) error {
+	ctx := stream.Context()
+	subscriber := fmt.Sprintf("query-service-%d", querySubscriberID.Add(1))
+	sub, err := s.node.eventBus.Subscribe(ctx, subscriber, cmtypes.EventQueryNewBlock, blockSubscriptionCapacity)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to subscribe: %v", err)
+	}
+	defer func() {
+		if err := s.node.eventBus.UnsubscribeAll(context.Background(), subscriber); err != nil {
+			s.node.Logger.Debug("failed to unsubscribe", "subscriber", subscriber, "err", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Canceled():
+			return status.Errorf(codes.ResourceExhausted, "subscription canceled: %v", sub.Err())
+		case msg := <-sub.Out():
+			block := msg.Data().(cmtypes.EventDataNewBlock).Block
+			header, data, err := s.getBlockData(ctx, uint64(block.Height)) //nolint:gosec
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&pb.SubscribeBlocksResponse{Header: header, Data: data}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// getBlockData returns block at given height, or the latest block if height is 0, encoded as protobuf messages.
+func (s *queryServer) getBlockData(ctx context.Context, height uint64) (*pb.SignedHeader, *pb.Data, error) {
+	if height == 0 {
+		height = s.node.Store.Height()
+	}
+	header, data, err := s.node.Store.GetBlockData(ctx, height)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "block %d not found: %v", height, err)
+	}
+	pbHeader, err := header.ToProto()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to encode header: %v", err)
+	}
+	return pbHeader, data.ToProto(), nil
+}
@@ -0,0 +1,132 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/types"
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+func TestQueryServer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := "TestQueryServer"
+	_, rpc := getRPC(t, chainID)
+	node := rpc.node
+	server := node.QueryServer()
+	ctx := context.Background()
+
+	var headers []*types.SignedHeader
+	for height := uint64(1); height <= 2; height++ {
+		header, data := types.GetRandomBlock(height, 2, chainID)
+		require.NoError(node.Store.SaveBlockData(ctx, header, data, &header.Signature))
+		node.Store.SetHeight(ctx, height)
+		headers = append(headers, header)
+	}
+	state, err := types.NewFromGenesisDoc(node.genesis)
+	require.NoError(err)
+	state.LastBlockHeight = 2
+	require.NoError(node.Store.UpdateState(ctx, state))
+
+	block, err := server.GetBlock(ctx, &pb.GetBlockRequest{Height: 1})
+	require.NoError(err)
+	assert.Equal(uint64(1), block.Header.Header.Height)
+	assert.Len(block.Data.Txs, 2)
+
+	latest, err := server.GetHeader(ctx, &pb.GetHeaderRequest{})
+	require.NoError(err)
+	assert.Equal([]byte(headers[1].Hash()), mustHeaderHash(t, latest.Header))
+
+	_, err = server.GetBlock(ctx, &pb.GetBlockRequest{Height: 3})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	stateRes, err := server.GetState(ctx, &pb.GetStateRequest{})
+	require.NoError(err)
+	assert.Equal(chainID, stateRes.State.ChainId)
+	assert.Equal(uint64(2), stateRes.State.LastBlockHeight)
+
+	tx := cmtypes.Tx("tx1")
+	require.NoError(node.TxIndexer.Index(&abci.TxResult{Height: 2, Index: 1, Tx: tx, Result: abci.ExecTxResult{Code: 7}}))
+	txRes, err := server.GetTx(ctx, &pb.GetTxRequest{Hash: tx.Hash()})
+	require.NoError(err)
+	assert.Equal(uint64(2), txRes.Height)
+	assert.Equal(uint32(1), txRes.Index)
+	assert.Equal([]byte(tx), txRes.Tx)
+	assert.Equal(uint32(7), txRes.Result.Code)
+	_, err = server.GetTx(ctx, &pb.GetTxRequest{Hash: cmtypes.Tx("tx2").Hash()})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	inclusion, err := server.GetDAInclusion(ctx, &pb.GetDAInclusionRequest{Height: 1})
+	require.NoError(err)
+	assert.False(inclusion.Included)
+	assert.Equal(uint64(1), inclusion.Height)
+}
+
+func TestQueryServerSubscribeBlocks(t *testing.T) {
+	require := require.New(t)
+
+	chainID := "TestQueryServerSubscribeBlocks"
+	_, rpc := getRPC(t, chainID)
+	node := rpc.node
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := node.eventBus.NumClients()
+	stream := &blockStream{ctx: ctx, blocks: make(chan *pb.SubscribeBlocksResponse, 1)}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- node.QueryServer().SubscribeBlocks(&pb.SubscribeBlocksRequest{}, stream)
+	}()
+	require.Eventually(func() bool { return node.eventBus.NumClients() > clients }, time.Second, 10*time.Millisecond)
+
+	header, data := types.GetRandomBlock(1, 1, chainID)
+	require.NoError(node.Store.SaveBlockData(ctx, header, data, &header.Signature))
+	require.NoError(node.eventBus.PublishEventNewBlock(cmtypes.EventDataNewBlock{
+		Block: &cmtypes.Block{Header: cmtypes.Header{Height: 1}},
+	}))
+
+	select {
+	case block := <-stream.blocks:
+		require.Equal(uint64(1), block.Header.Header.Height)
+		require.Equal(data.Txs[0], types.Tx(block.Data.Txs[0]))
+	case <-time.After(time.Second):
+		t.Fatal("block not received")
+	}
+
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+	require.Eventually(func() bool { return node.eventBus.NumClients() == clients }, time.Second, 10*time.Millisecond)
+}
+
+func mustHeaderHash(t *testing.T, pbHeader *pb.SignedHeader) []byte {
+	t.Helper()
+	var header types.SignedHeader
+	require.NoError(t, header.FromProto(pbHeader))
+	return header.Hash()
+}
+
+// blockStream is a server stream of block subscription, passing sent blocks to the channel.
+type blockStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	blocks chan *pb.SubscribeBlocksResponse
+}
+
+func (s *blockStream) Context() context.Context { return s.ctx }
+
+func (s *blockStream) Send(block *pb.SubscribeBlocksResponse) error {
+	s.blocks <- block
+	return nil
+}
@@ -0,0 +1,229 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cometbft/cometbft/libs/log"
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/state/indexer"
+	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
+	blocknull "github.com/rollkit/rollkit/state/indexer/block/null"
+	"github.com/rollkit/rollkit/state/indexer/sink/psql"
+	"github.com/rollkit/rollkit/state/txindex"
+	"github.com/rollkit/rollkit/state/txindex/kv"
+	txnull "github.com/rollkit/rollkit/state/txindex/null"
+	"github.com/rollkit/rollkit/store"
+)
+
+// prefixes used in indexer KV store to separate block events and indexer status from transaction index
+const (
+	blockEventsPrefix   = "block_events"
+	indexerStatusPrefix = "indexer_status"
+)
+
+// Reindex rebuilds transaction and block indexes of blocks in [from, to] range, using blocks and block responses
+// persisted in the node's store. Blocks are not re-executed. If to is 0, blocks up to the latest height are
+// reindexed. The node must not be running.
+func Reindex(ctx context.Context, conf config.NodeConfig, from, to uint64, logger log.Logger) (err error) {
+	if err := conf.Indexer.Validate(); err != nil {
+		return err
+	}
+	baseKV, err := initBaseKV(conf, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, baseKV.Close())
+	}()
+
+	blocks := store.New(newPrefixKV(baseKV, mainPrefix))
+	state, err := blocks.GetState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if to == 0 {
+		to = state.LastBlockHeight
+	}
+	if to > state.LastBlockHeight {
+		return fmt.Errorf("cannot reindex up to height %d, latest height is %d", to, state.LastBlockHeight)
+	}
+	from = max(from, state.InitialHeight)
+
+	indexerKV := newPrefixKV(baseKV, indexerPrefix)
+	txIndexer, blockIndexer, closeIndexers, err := initIndexers(ctx, conf.Indexer, state.ChainID, indexerKV)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, closeIndexers())
+	}()
+	if err := txindex.Reindex(ctx, blocks, txIndexer, blockIndexer, from, to, logger); err != nil {
+		return err
+	}
+	if err := txindex.PruneLegacyEntries(ctx, txIndexer, blockIndexer, logger); err != nil {
+		return err
+	}
+
+	// if reindexed blocks directly follow already indexed blocks, indexes are now complete up to reindexed height
+	statusKV := newPrefixKV(indexerKV, indexerStatusPrefix)
+	indexed, err := txindex.LoadIndexedHeight(ctx, statusKV)
+	if err != nil {
+		return err
+	}
+	if from <= max(indexed+1, state.InitialHeight) && to > indexed {
+		if err := txindex.SaveIndexedHeight(ctx, statusKV, to); err != nil {
+			return err
+		}
+	}
+	// all blocks are reindexed, so no index entries in a previous format are left to be found by searches
+	if from == state.InitialHeight && to == state.LastBlockHeight {
+		return txindex.SaveIndexFormat(ctx, statusKV, indexer.KeyFormatVersion)
+	}
+	return nil
+}
+
+// initIndexers creates transaction and block indexers according to indexer configuration. Returned function
+// releases resources used by indexers and must be called after indexers are no longer used.
+func initIndexers(
+	ctx context.Context,
+	conf config.IndexerConfig,
+	chainID string,
+	kvStore ds.TxnDatastore,
+) (txindex.TxIndexer, indexer.BlockIndexer, func() error, error) {
+	closeIndexers := func() error { return nil }
+
+	var sink *psql.EventSink
+	if conf.Tx.Mode == config.IndexerModePsql || conf.Block.Mode == config.IndexerModePsql {
+		var err error
+		sink, err = psql.NewEventSink(conf.PsqlConn, chainID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create psql event sink: %w", err)
+		}
+		sink.SetTxEventFilter(newEventFilter(conf.Tx))
+		sink.SetBlockEventFilter(newEventFilter(conf.Block))
+		closeIndexers = sink.Close
+	}
+
+	var txIndexer txindex.TxIndexer
+	switch conf.Tx.Mode {
+	case config.IndexerModeNull:
+		txIndexer = &txnull.TxIndex{}
+	case config.IndexerModePsql:
+		txIndexer = sink.TxIndexer()
+	default:
+		kvIndexer := kv.NewTxIndex(ctx, kvStore)
+		kvIndexer.SetEventFilter(newEventFilter(conf.Tx))
+		txIndexer = kvIndexer
+	}
+
+	var blockIndexer indexer.BlockIndexer
+	switch conf.Block.Mode {
+	case config.IndexerModeNull:
+		blockIndexer = &blocknull.BlockerIndexer{}
+	case config.IndexerModePsql:
+		blockIndexer = sink.BlockIndexer()
+	default:
+		kvIndexer := blockidxkv.New(ctx, newPrefixKV(kvStore, blockEventsPrefix))
+		kvIndexer.SetEventFilter(newEventFilter(conf.Block))
+		blockIndexer = kvIndexer
+	}
+	return txIndexer, blockIndexer, closeIndexers, nil
+}
+
+func newEventFilter(conf config.EventIndexConfig) *indexer.EventFilter {
+	return indexer.NewEventFilter(splitList(conf.Include), splitList(conf.Exclude))
+}
+
+func splitList(list string) []string {
+	var res []string
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// indexedHeights returns the height up to which all blocks are indexed and the height of the latest stored block.
+// Blocks between them are missing in indexes, e.g. because indexing failed or the node stopped before blocks were
+// indexed, and are reindexed by the indexer service. If KV indexes were written in the key format of previous
+// versions, it returns true: the format version is not persisted until entries in the previous format are deleted
+// by the indexer service.
+func indexedHeights(
+	ctx context.Context,
+	blocks store.Store,
+	txIdxr txindex.TxIndexer,
+	blockIdxr indexer.BlockIndexer,
+	statusKV ds.Datastore,
+	initialHeight uint64,
+	logger log.Logger,
+) (uint64, uint64, bool, error) {
+	indexed, err := txindex.LoadIndexedHeight(ctx, statusKV)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	format, err := txindex.LoadIndexFormat(ctx, statusKV)
+	if err != nil {
+		return indexed, 0, false, err
+	}
+	// store height is set by the block manager, which is created later; take it from the last saved state
+	var height uint64
+	state, err := blocks.GetState(ctx)
+	switch {
+	case err == nil:
+		height = state.LastBlockHeight
+	case !errors.Is(err, ds.ErrNotFound):
+		return indexed, 0, false, err
+	}
+	legacyKV := false
+	if format == 0 && height > 0 {
+		// indexes were created by previous versions, which tracked neither the indexed height nor the index format
+		indexed, legacyKV, err = legacyIndexedHeight(txIdxr, blockIdxr, initialHeight, height, logger)
+		if err != nil {
+			return 0, height, false, err
+		}
+		if err := txindex.SaveIndexedHeight(ctx, statusKV, indexed); err != nil {
+			return indexed, height, false, err
+		}
+	}
+	if format < indexer.KeyFormatVersion && !legacyKV {
+		if err := txindex.SaveIndexFormat(ctx, statusKV, indexer.KeyFormatVersion); err != nil {
+			return indexed, height, false, err
+		}
+	}
+	return max(indexed, initialHeight-1), height, legacyKV, nil
+}
+
+// legacyIndexedHeight returns the height up to which all blocks are indexed in indexes created by previous
+// versions, and true if KV indexes were written by them. KV indexes written by them have keys in a previous
+// format, which are not found by searches, so all blocks have to be reindexed. Other indexes are assumed to be
+// complete if the latest block is indexed, or if it can't be checked because block indexing is disabled.
+func legacyIndexedHeight(
+	txIdxr txindex.TxIndexer,
+	blockIdxr indexer.BlockIndexer,
+	initialHeight, height uint64,
+	logger log.Logger,
+) (uint64, bool, error) {
+	_, txKV := txIdxr.(*kv.TxIndex)
+	_, blockKV := blockIdxr.(*blockidxkv.BlockerIndexer)
+	if txKV || blockKV {
+		logger.Info("index format changed, reindexing all blocks in the background", "from", initialHeight, "to", height)
+		return 0, true, nil
+	}
+	if _, ok := blockIdxr.(*blocknull.BlockerIndexer); ok {
+		return height, false, nil
+	}
+	has, err := blockIdxr.Has(int64(height)) //nolint:gosec
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to check if block %d is indexed: %w", height, err)
+	}
+	if has {
+		return height, false, nil
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,197 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmconfig "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/state/indexer"
+	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
+	blocknull "github.com/rollkit/rollkit/state/indexer/block/null"
+	"github.com/rollkit/rollkit/state/indexer/sink/psql"
+	"github.com/rollkit/rollkit/state/txindex"
+	"github.com/rollkit/rollkit/state/txindex/kv"
+	txnull "github.com/rollkit/rollkit/state/txindex/null"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestIndexedHeights(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	logger := log.TestingLogger()
+
+	baseKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blocks := store.New(newPrefixKV(baseKV, mainPrefix))
+	txIndexer, blockIndexer, _, err := initIndexers(ctx, config.DefaultNodeConfig.Indexer, "TestIndexedHeights", newPrefixKV(baseKV, indexerPrefix))
+	require.NoError(err)
+	statusKV := ds.NewMapDatastore()
+
+	// nothing is stored yet
+	indexed, latest, legacyKV, err := indexedHeights(ctx, blocks, txIndexer, blockIndexer, statusKV, 1, logger)
+	require.NoError(err)
+	assert.Zero(indexed)
+	assert.Zero(latest)
+	assert.False(legacyKV)
+	format, err := txindex.LoadIndexFormat(ctx, statusKV)
+	require.NoError(err)
+	assert.Equal(uint64(indexer.KeyFormatVersion), format)
+
+	genesis, _ := types.GetGenesisWithPrivkey(types.DefaultSigningKeyType, "TestIndexedHeights")
+	state, err := types.NewFromGenesisDoc(genesis)
+	require.NoError(err)
+	state.LastBlockHeight = 3
+	require.NoError(blocks.UpdateState(ctx, state))
+
+	// blocks after tracked indexed height are missing
+	require.NoError(txindex.SaveIndexedHeight(ctx, statusKV, 1))
+	indexed, latest, _, err = indexedHeights(ctx, blocks, txIndexer, blockIndexer, statusKV, 1, logger)
+	require.NoError(err)
+	assert.Equal(uint64(1), indexed)
+	assert.Equal(uint64(3), latest)
+
+	// KV indexes created by previous versions have keys in a previous format, so all blocks are missing, even if
+	// the latest block is indexed; the format is persisted once entries in the previous format are deleted
+	statusKV = ds.NewMapDatastore()
+	require.NoError(blockIndexer.Index(cmtypes.EventDataNewBlockEvents{Height: 3}))
+	indexed, _, legacyKV, err = indexedHeights(ctx, blocks, txIndexer, blockIndexer, statusKV, 1, logger)
+	require.NoError(err)
+	assert.Zero(indexed)
+	assert.True(legacyKV)
+	format, err = txindex.LoadIndexFormat(ctx, statusKV)
+	require.NoError(err)
+	assert.Zero(format)
+
+	// once the format is tracked, reindexed blocks are not missing anymore
+	require.NoError(txindex.SaveIndexFormat(ctx, statusKV, indexer.KeyFormatVersion))
+	require.NoError(txindex.SaveIndexedHeight(ctx, statusKV, 2))
+	indexed, _, legacyKV, err = indexedHeights(ctx, blocks, txIndexer, blockIndexer, statusKV, 1, logger)
+	assert.False(legacyKV)
+	require.NoError(err)
+	assert.Equal(uint64(2), indexed)
+
+	// with indexing disabled, indexes without tracked height are assumed to be complete
+	statusKV = ds.NewMapDatastore()
+	indexed, latest, legacyKV, err = indexedHeights(ctx, blocks, &txnull.TxIndex{}, &blocknull.BlockerIndexer{}, statusKV, 1, logger)
+	require.NoError(err)
+	assert.False(legacyKV)
+	assert.Equal(uint64(3), indexed)
+	assert.Equal(uint64(3), latest)
+	saved, err := txindex.LoadIndexedHeight(ctx, statusKV)
+	require.NoError(err)
+	assert.Equal(uint64(3), saved)
+}
+
+func TestReindexGapOnStartup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	chainID := "TestReindexGapOnStartup"
+	genesis, genesisValidatorKey := types.GetGenesisWithPrivkey(types.DefaultSigningKeyType, chainID)
+	signingKey, err := types.PrivKeyToSigningKey(genesisValidatorKey)
+	require.NoError(err)
+	conf := config.NodeConfig{
+		RootDir:          t.TempDir(),
+		DBPath:           "data",
+		DAAddress:        MockDAAddress,
+		DANamespace:      MockDANamespace,
+		SequencerAddress: MockSequencerAddress,
+	}
+
+	// blocks stored by a node that didn't track indexed height and didn't index them
+	baseKV, err := initBaseKV(conf, log.TestingLogger())
+	require.NoError(err)
+	blocks := store.New(newPrefixKV(baseKV, mainPrefix))
+	state, err := types.NewFromGenesisDoc(genesis)
+	require.NoError(err)
+	for height := uint64(1); height <= 3; height++ {
+		header, data := types.GetRandomBlock(height, 1, chainID)
+		require.NoError(blocks.SaveBlockData(ctx, header, data, &types.Signature{}))
+		require.NoError(blocks.SaveBlockResponses(ctx, height, &abci.ResponseFinalizeBlock{
+			TxResults: []*abci.ExecTxResult{{}},
+		}))
+	}
+	state.LastBlockHeight = 3
+	require.NoError(blocks.UpdateState(ctx, state))
+	// index entry written by previous versions, without zero-padded height and index
+	legacyKey := ds.NewKey("tx.height/1/1/0")
+	require.NoError(newPrefixKV(baseKV, indexerPrefix).Put(ctx, legacyKey, []byte{1}))
+	require.NoError(baseKV.Close())
+
+	p2pKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	node, err := newFullNode(ctx, conf, p2pKey, signingKey, proxy.NewLocalClientCreator(setupMockApplication()), genesis, DefaultMetricsProvider(cmconfig.DefaultInstrumentationConfig()), log.TestingLogger())
+	require.NoError(err)
+
+	// blocks are reindexed in the background
+	require.Eventually(func() bool { return node.IndexerService.IndexedHeight() == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.False(node.IndexerService.Status(3).Reindexing)
+	for height := int64(1); height <= 3; height++ {
+		ok, err := node.BlockIndexer.Has(height)
+		require.NoError(err)
+		assert.True(ok)
+	}
+	require.NoError(node.IndexerService.Stop())
+	require.NoError(node.Store.Close())
+
+	// entries in the previous format are deleted before reindexing, and the current format is persisted
+	baseKV, err = initBaseKV(conf, log.TestingLogger())
+	require.NoError(err)
+	defer func() {
+		assert.NoError(baseKV.Close())
+	}()
+	indexerKV := newPrefixKV(baseKV, indexerPrefix)
+	has, err := indexerKV.Has(ctx, legacyKey)
+	require.NoError(err)
+	assert.False(has)
+	format, err := txindex.LoadIndexFormat(ctx, newPrefixKV(indexerKV, indexerStatusPrefix))
+	require.NoError(err)
+	assert.Equal(uint64(indexer.KeyFormatVersion), format)
+}
+
+func TestInitIndexers(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+
+	txIndexer, blockIndexer, _, err := initIndexers(ctx, config.DefaultNodeConfig.Indexer, "TestInitIndexers", kvStore)
+	require.NoError(t, err)
+	assert.IsType(&kv.TxIndex{}, txIndexer)
+	assert.IsType(&blockidxkv.BlockerIndexer{}, blockIndexer)
+
+	txIndexer, blockIndexer, _, err = initIndexers(ctx, config.IndexerConfig{
+		Tx:    config.EventIndexConfig{Mode: config.IndexerModeKV, Exclude: "message, transfer.sender"},
+		Block: config.EventIndexConfig{Mode: config.IndexerModeNull},
+	}, "TestInitIndexers", kvStore)
+	require.NoError(t, err)
+	assert.IsType(&kv.TxIndex{}, txIndexer)
+	assert.IsType(&blocknull.BlockerIndexer{}, blockIndexer)
+
+	// connection to PostgreSQL is established lazily
+	txIndexer, blockIndexer, closeIndexers, err := initIndexers(ctx, config.IndexerConfig{
+		Tx:       config.EventIndexConfig{Mode: config.IndexerModePsql},
+		Block:    config.EventIndexConfig{Mode: config.IndexerModePsql},
+		PsqlConn: "postgresql://localhost:5432/db?sslmode=disable",
+	}, "TestInitIndexers", kvStore)
+	require.NoError(t, err)
+	assert.IsType(psql.TxIndexer{}, txIndexer)
+	assert.IsType(psql.BlockIndexer{}, blockIndexer)
+	assert.NoError(closeIndexers())
+
+	assert.Equal([]string{"message", "transfer.sender"}, splitList(" message, transfer.sender,"))
+}
@@ -0,0 +1,207 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+const (
+	// addrBookMaxAge defines how long peer addresses are kept in address book after last successful connection.
+	addrBookMaxAge = 7 * 24 * time.Hour
+
+	// addrBookMaxSize is the maximum number of peers kept in address book. Least recently seen peers are evicted first.
+	addrBookMaxSize = 1000
+
+	// addrBookPruneInterval defines how often stale address book entries are evicted.
+	addrBookPruneInterval = 1 * time.Hour
+
+	// addrBookDialTimeout is the time limit for redialing peers from address book on startup.
+	addrBookDialTimeout = 10 * time.Second
+)
+
+// addrBookKey is the datastore prefix of address book entries.
+var addrBookKey = datastore.NewKey("/p2p/addrbook")
+
+type addrBookEntry struct {
+	Addrs    []string  `json:"addrs"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// addrBook persists addresses of peers that were successfully connected, so they can be redialed after restart.
+type addrBook struct {
+	ds     datastore.Datastore
+	logger log.Logger
+
+	// mtx serializes pruning with recording of new entries.
+	mtx sync.Mutex
+}
+
+func newAddrBook(ds datastore.Datastore, logger log.Logger) *addrBook {
+	return &addrBook{
+		ds:     ds,
+		logger: logger,
+	}
+}
+
+// record stores peer addresses with current time as last seen time.
+func (b *addrBook) record(ctx context.Context, p peer.ID, addrs []multiaddr.Multiaddr) error {
+	if len(addrs) == 0 {
+		return nil
+	}
+	entry := addrBookEntry{
+		Addrs:    make([]string, 0, len(addrs)),
+		LastSeen: time.Now(),
+	}
+	for _, addr := range addrs {
+		entry.Addrs = append(entry.Addrs, addr.String())
+	}
+	value, err := json.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.ds.Put(ctx, addrBookKey.ChildString(p.String()), value)
+}
+
+// peers evicts stale entries and returns remaining peers, most recently seen first.
+func (b *addrBook) peers(ctx context.Context) ([]peer.AddrInfo, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	results, err := b.ds.Query(ctx, query.Query{Prefix: addrBookKey.String()})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return nil, err
+	}
+
+	type peerEntry struct {
+		info     peer.AddrInfo
+		lastSeen time.Time
+	}
+	valid := make([]peerEntry, 0, len(entries))
+	for _, entry := range entries {
+		key := datastore.NewKey(entry.Key)
+		var abEntry addrBookEntry
+		p, err := peer.Decode(key.BaseNamespace())
+		if err == nil {
+			err = json.Unmarshal(entry.Value, &abEntry)
+		}
+		if err != nil || time.Since(abEntry.LastSeen) > addrBookMaxAge {
+			if err := b.ds.Delete(ctx, key); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		info := peer.AddrInfo{ID: p}
+		for _, a := range abEntry.Addrs {
+			addr, err := multiaddr.NewMultiaddr(a)
+			if err != nil {
+				b.logger.Debug("invalid address in address book", "peer", p, "address", a)
+				continue
+			}
+			info.Addrs = append(info.Addrs, addr)
+		}
+		valid = append(valid, peerEntry{info: info, lastSeen: abEntry.LastSeen})
+	}
+
+	sort.Slice(valid, func(i, j int) bool {
+		return valid[i].lastSeen.After(valid[j].lastSeen)
+	})
+	for len(valid) > addrBookMaxSize {
+		evicted := valid[len(valid)-1]
+		if err := b.ds.Delete(ctx, addrBookKey.ChildString(evicted.info.ID.String())); err != nil {
+			return nil, err
+		}
+		valid = valid[:len(valid)-1]
+	}
+
+	res := make([]peer.AddrInfo, 0, len(valid))
+	for _, v := range valid {
+		res = append(res, v.info)
+	}
+	return res, nil
+}
+
+// setupAddrBook starts recording addresses of identified peers and redials peers known from previous runs.
+func (c *Client) setupAddrBook(ctx context.Context) error {
+	sub, err := c.host.EventBus().Subscribe(new(event.EvtPeerIdentificationCompleted))
+	if err != nil {
+		return err
+	}
+	go c.recordPeersLoop(ctx, sub)
+
+	peers, err := c.addrBook.peers(ctx)
+	if err != nil {
+		return err
+	}
+	c.redialPeers(ctx, peers)
+	return nil
+}
+
+func (c *Client) recordPeersLoop(ctx context.Context, sub event.Subscription) {
+	defer sub.Close() //nolint:errcheck
+	ticker := time.NewTicker(addrBookPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.addrBook.peers(ctx); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to prune address book", "error", err)
+			}
+		case e, ok := <-sub.Out():
+			if !ok {
+				return
+			}
+			evt := e.(event.EvtPeerIdentificationCompleted)
+			if err := c.addrBook.record(ctx, evt.Peer, evt.ListenAddrs); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to record peer address", "peer", evt.Peer, "error", err)
+			}
+		}
+	}
+}
+
+// redialPeers concurrently connects to at most peerLimit given peers, waiting at most addrBookDialTimeout.
+func (c *Client) redialPeers(ctx context.Context, peers []peer.AddrInfo) {
+	if len(peers) == 0 {
+		return
+	}
+	if len(peers) > peerLimit {
+		peers = peers[:peerLimit]
+	}
+	c.logger.Info("redialing peers from address book", "count", len(peers))
+	ctx, cancel := context.WithTimeout(ctx, addrBookDialTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, p := range peers {
+		if p.ID == c.host.ID() {
+			continue
+		}
+		wg.Add(1)
+		go func(p peer.AddrInfo) {
+			defer wg.Done()
+			if err := c.host.Connect(ctx, p); err != nil {
+				c.logger.Debug("failed to redial peer", "peer", p.ID, "error", err)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
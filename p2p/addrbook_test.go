@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestAddrBook(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	book := newAddrBook(ds, test.NewLogger(t))
+
+	randomPeer := func() peer.ID {
+		_, pubKey, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		id, err := peer.IDFromPublicKey(pubKey)
+		require.NoError(err)
+		return id
+	}
+	addr := multiaddr.StringCast("/ip4/127.0.0.1/tcp/7676")
+
+	older, newer, stale := randomPeer(), randomPeer(), randomPeer()
+	require.NoError(book.record(ctx, older, []multiaddr.Multiaddr{addr}))
+	require.NoError(book.record(ctx, newer, []multiaddr.Multiaddr{addr}))
+	// peers without addresses are not recorded
+	require.NoError(book.record(ctx, randomPeer(), nil))
+
+	staleEntry, err := json.Marshal(&addrBookEntry{
+		Addrs:    []string{addr.String()},
+		LastSeen: time.Now().Add(-addrBookMaxAge - time.Minute),
+	})
+	require.NoError(err)
+	require.NoError(ds.Put(ctx, addrBookKey.ChildString(stale.String()), staleEntry))
+	require.NoError(ds.Put(ctx, addrBookKey.ChildString("invalid"), []byte("invalid")))
+
+	peers, err := book.peers(ctx)
+	require.NoError(err)
+	require.Len(peers, 2)
+	assert.Equal(newer, peers[0].ID)
+	assert.Equal(older, peers[1].ID)
+	assert.Equal([]multiaddr.Multiaddr{addr}, peers[0].Addrs)
+
+	// stale and invalid entries are evicted
+	has, err := ds.Has(ctx, addrBookKey.ChildString(stale.String()))
+	require.NoError(err)
+	assert.False(has)
+	has, err = ds.Has(ctx, addrBookKey.ChildString("invalid"))
+	require.NoError(err)
+	assert.False(has)
+}
+
+func TestAddrBookRecordsConnectedPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		1: {conns: []int{0}},
+	}, make([]GossipValidator, 2), test.NewLogger(t))
+
+	require.Eventually(t, func() bool {
+		peers, err := clients[1].addrBook.peers(ctx)
+		require.NoError(t, err)
+		return len(peers) == 1 && peers[0].ID == clients[0].host.ID()
+	}, 5*time.Second, 50*time.Millisecond)
+}
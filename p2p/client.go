@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cometbft/cometbft/p2p"
@@ -17,6 +18,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/crypto"
 	cdiscovery "github.com/libp2p/go-libp2p/core/discovery"
 	"github.com/libp2p/go-libp2p/core/host"
+	libp2pmetrics "github.com/libp2p/go-libp2p/core/metrics"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	discovery "github.com/libp2p/go-libp2p/p2p/discovery/routing"
@@ -28,6 +30,7 @@ import (
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/ratelimit"
 	"github.com/rollkit/rollkit/third_party/log"
 )
 
@@ -41,6 +44,9 @@ const (
 
 	// txTopicSuffix is added after namespace to create pubsub topic for TX gossiping.
 	txTopicSuffix = "-tx"
+
+	// blockTopicSuffix is added after namespace to create pubsub topic for combined block gossiping.
+	blockTopicSuffix = "-block"
 )
 
 // Client is a P2P client, implemented with libp2p.
@@ -62,6 +68,26 @@ type Client struct {
 	txGossiper  *Gossiper
 	txValidator GossipValidator
 
+	blockGossiper  *Gossiper
+	blockValidator GossipValidator
+
+	// syncTopics are GossipSub topics of header and data sync services
+	syncTopics []string
+
+	banner *peerBanner
+	scores *peerScores
+	mesh   *meshTracker
+
+	// reachability is the last network.Reachability reported by AutoNAT
+	reachability atomic.Int32
+
+	addrBook *addrBook
+	bwc      *libp2pmetrics.BandwidthCounter
+
+	// bandwidth limits of header and data exchange servers' streams, shared by all of them
+	sendLimiter      *ratelimit.Bucket
+	peerSendLimiters *ratelimit.Buckets[peer.ID]
+
 	// cancel is used to cancel context passed to libp2p functions
 	// it's required because of discovery.Advertise call
 	cancel context.CancelFunc
@@ -82,6 +108,12 @@ func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds
 	if conf.ListenAddress == "" {
 		conf.ListenAddress = config.DefaultListenAddress
 	}
+	if conf.BanThreshold == 0 {
+		conf.BanThreshold = defaultBanThreshold
+	}
+	if conf.BanDuration == 0 {
+		conf.BanDuration = defaultBanDuration
+	}
 
 	gater, err := conngater.NewBasicConnectionGater(ds)
 	if err != nil {
@@ -89,12 +121,20 @@ func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds
 	}
 
 	return &Client{
-		conf:    conf,
-		gater:   gater,
-		privKey: privKey,
-		chainID: chainID,
-		logger:  logger,
-		metrics: metrics,
+		conf:     conf,
+		gater:    gater,
+		banner:   newPeerBanner(gater, ds, conf.BanThreshold, conf.BanDuration, logger, metrics),
+		scores:   newPeerScores(),
+		mesh:     newMeshTracker(),
+		bwc:      libp2pmetrics.NewBandwidthCounter(),
+		addrBook: newAddrBook(ds, logger),
+		privKey:  privKey,
+		chainID:  chainID,
+		logger:   logger,
+		metrics:  metrics,
+
+		sendLimiter:      ratelimit.NewBucket(float64(conf.MaxSendRate)),
+		peerSendLimiters: ratelimit.NewBuckets[peer.ID](float64(conf.MaxSendRatePerPeer)),
 	}, nil
 }
 
@@ -122,13 +162,25 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		c.logger.Info("listening on", "address", fmt.Sprintf("%s/p2p/%s", a, c.host.ID()))
 	}
 
+	blockedPeers := c.parseAddrInfoList(c.conf.BlockedPeers)
 	c.logger.Debug("blocking blacklisted peers", "blacklist", c.conf.BlockedPeers)
-	if err := c.setupBlockedPeers(c.parseAddrInfoList(c.conf.BlockedPeers)); err != nil {
+	if err := c.setupBlockedPeers(blockedPeers); err != nil {
 		return err
 	}
 
+	allowedPeers := c.parseAddrInfoList(c.conf.AllowedPeers)
 	c.logger.Debug("allowing whitelisted peers", "whitelist", c.conf.AllowedPeers)
-	if err := c.setupAllowedPeers(c.parseAddrInfoList(c.conf.AllowedPeers)); err != nil {
+	if err := c.setupAllowedPeers(allowedPeers); err != nil {
+		return err
+	}
+
+	c.logger.Debug("setting up peer banning")
+	if err := c.setupPeerBanning(ctx, blockedPeers, allowedPeers); err != nil {
+		return err
+	}
+
+	c.logger.Debug("setting up diagnostics")
+	if err := c.setupDiagnostics(ctx); err != nil {
 		return err
 	}
 
@@ -142,6 +194,11 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		return err
 	}
 
+	c.logger.Debug("setting up address book")
+	if err := c.setupAddrBook(ctx); err != nil {
+		return err
+	}
+
 	c.logger.Debug("setting up active peer discovery")
 	if err := c.peerDiscovery(ctx); err != nil {
 		return err
@@ -154,8 +211,13 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 func (c *Client) Close() error {
 	c.cancel()
 
+	var blockGossiperErr error
+	if c.blockGossiper != nil {
+		blockGossiperErr = c.blockGossiper.Close()
+	}
 	return errors.Join(
 		c.txGossiper.Close(),
+		blockGossiperErr,
 		c.dht.Close(),
 		c.host.Close(),
 	)
@@ -167,11 +229,31 @@ func (c *Client) GossipTx(ctx context.Context, tx []byte) error {
 	return c.txGossiper.Publish(ctx, tx)
 }
 
+// ReportInvalidMessage reports peer that sent invalid message outside of GossipSub, e.g. using given stream
+// protocol. Such messages count toward banning of the peer, like messages rejected by gossip validators.
+func (c *Client) ReportInvalidMessage(p peer.ID, protocol string) {
+	c.banner.reportInvalid(p, protocol)
+}
+
 // SetTxValidator sets the callback function, that will be invoked during message gossiping.
 func (c *Client) SetTxValidator(val GossipValidator) {
 	c.txValidator = val
 }
 
+// GossipBlock sends the combined block (header and data) to the P2P network.
+func (c *Client) GossipBlock(ctx context.Context, block []byte) error {
+	if c.blockGossiper == nil {
+		return errCombinedBlockGossipDisabled
+	}
+	c.logger.Debug("Gossiping block", "len", len(block))
+	return c.blockGossiper.Publish(ctx, block)
+}
+
+// SetBlockValidator sets the callback function, that will be invoked during combined block gossiping.
+func (c *Client) SetBlockValidator(val GossipValidator) {
+	c.blockValidator = val
+}
+
 // Addrs returns listen addresses of Client.
 func (c *Client) Addrs() []multiaddr.Multiaddr {
 	return c.host.Addrs()
@@ -210,6 +292,11 @@ type PeerConnection struct {
 	RemoteIP         string               `json:"remote_ip"`
 }
 
+// DirectPeers returns trusted full nodes the aggregator pushes blocks to directly.
+func (c *Client) DirectPeers() []peer.AddrInfo {
+	return c.parseAddrInfoList(c.conf.DirectPeers)
+}
+
 // PeerIDs returns list of peer IDs of connected peers excluding self and inactive
 func (c *Client) PeerIDs() []peer.ID {
 	peerIDs := make([]peer.ID, 0)
@@ -243,13 +330,104 @@ func (c *Client) Peers() []PeerConnection {
 	return res
 }
 
+// PeerInfo describes connected peer, including its gossip score and traffic counters.
+type PeerInfo struct {
+	ID         peer.ID  `json:"id"`
+	Addrs      []string `json:"addrs"`
+	IsOutbound bool     `json:"is_outbound"`
+	// Score is the GossipSub score of the peer.
+	Score float64 `json:"score"`
+	// BytesIn and BytesOut are total number of bytes received from and sent to the peer.
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+	// RateIn and RateOut are current receive and send rates, in bytes per second.
+	RateIn  float64 `json:"rate_in"`
+	RateOut float64 `json:"rate_out"`
+}
+
+// PeerInfos returns detailed information about peers connected to Client.
+func (c *Client) PeerInfos() []PeerInfo {
+	peers := c.host.Network().Peers()
+	res := make([]PeerInfo, 0, len(peers))
+	for _, p := range peers {
+		info := PeerInfo{
+			ID:    p,
+			Score: c.scores.get(p),
+		}
+		for _, conn := range c.host.Network().ConnsToPeer(p) {
+			info.Addrs = append(info.Addrs, conn.RemoteMultiaddr().String())
+			info.IsOutbound = info.IsOutbound || conn.Stat().Direction == network.DirOutbound
+		}
+		stats := c.bwc.GetBandwidthForPeer(p)
+		info.BytesIn, info.BytesOut = stats.TotalIn, stats.TotalOut
+		info.RateIn, info.RateOut = stats.RateIn, stats.RateOut
+		res = append(res, info)
+	}
+	return res
+}
+
+// DialPeer connects to the peer with given multiaddr. Address has to include peer ID (/p2p/<peer ID> suffix).
+func (c *Client) DialPeer(ctx context.Context, addr string) error {
+	maddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return fmt.Errorf("invalid peer address: %w", err)
+	}
+	info, err := peer.AddrInfoFromP2pAddr(maddr)
+	if err != nil {
+		return fmt.Errorf("invalid peer address: %w", err)
+	}
+	return c.host.Connect(ctx, *info)
+}
+
+// BlockPeer blocks the peer in connection gater and disconnects it.
+//
+// Blocked peer remains blocked until explicitly unblocked, even if it was previously banned automatically.
+func (c *Client) BlockPeer(ctx context.Context, id peer.ID) error {
+	if err := c.gater.BlockPeer(id); err != nil {
+		return err
+	}
+	if err := c.banner.forget(ctx, id); err != nil {
+		return err
+	}
+	return c.DisconnectPeer(id)
+}
+
+// UnblockPeer removes the peer from connection gater block list, including automatic bans.
+func (c *Client) UnblockPeer(ctx context.Context, id peer.ID) error {
+	if err := c.gater.UnblockPeer(id); err != nil {
+		return err
+	}
+	return c.banner.forget(ctx, id)
+}
+
+// DisconnectPeer closes all connections to the peer.
+func (c *Client) DisconnectPeer(id peer.ID) error {
+	return c.host.Network().ClosePeer(id)
+}
+
 func (c *Client) listen(ctx context.Context) (host.Host, error) {
 	maddr, err := multiaddr.NewMultiaddr(c.conf.ListenAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return libp2p.New(libp2p.ListenAddrs(maddr), libp2p.Identity(c.privKey), libp2p.ConnectionGater(c.gater))
+	gater, err := c.connectionGater()
+	if err != nil {
+		return nil, err
+	}
+	pnetOpts, err := c.privateNetworkOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []libp2p.Option{
+		libp2p.ListenAddrs(maddr),
+		libp2p.Identity(c.privKey),
+		libp2p.ConnectionGater(gater),
+		libp2p.BandwidthReporter(c.bwc),
+	}
+	opts = append(opts, pnetOpts...)
+	return libp2p.New(append(opts, c.natOptions()...)...)
 }
 
 func (c *Client) setupDHT(ctx context.Context) error {
@@ -326,6 +504,17 @@ func (c *Client) setupAllowedPeers(peers []peer.AddrInfo) error {
 	return nil
 }
 
+func (c *Client) setupPeerBanning(ctx context.Context, blockedPeers, allowedPeers []peer.AddrInfo) error {
+	c.banner.host = c.host
+	c.banner.protect(blockedPeers)
+	c.banner.protect(allowedPeers)
+	if err := c.banner.liftExpiredBans(ctx); err != nil {
+		return fmt.Errorf("failed to lift expired peer bans: %w", err)
+	}
+	go c.banner.liftExpiredBansLoop(ctx)
+	return nil
+}
+
 func (c *Client) advertise(ctx context.Context) error {
 	discutil.Advertise(ctx, c.disc, c.getNamespace(), cdiscovery.TTL(reAdvertisePeriod))
 	return nil
@@ -354,7 +543,12 @@ func (c *Client) tryConnect(ctx context.Context, peer peer.AddrInfo) {
 
 func (c *Client) setupGossiping(ctx context.Context) error {
 	var err error
-	c.ps, err = pubsub.NewGossipSub(ctx, c.host)
+	c.ps, err = pubsub.NewGossipSub(ctx, c.host,
+		pubsub.WithPeerScore(peerScoreParams(c.gossipTopics())),
+		pubsub.WithPeerScoreInspect(pubsub.PeerScoreInspectFn(c.scores.update), peerScoreInspectInterval),
+		pubsub.WithRawTracer(c.banner),
+		pubsub.WithRawTracer(c.mesh),
+	)
 	if err != nil {
 		return err
 	}
@@ -365,6 +559,18 @@ func (c *Client) setupGossiping(ctx context.Context) error {
 	}
 	go c.txGossiper.ProcessMessages(ctx)
 
+	if c.conf.CombinedBlockGossip {
+		var opts []GossiperOption
+		if c.blockValidator != nil {
+			opts = append(opts, WithValidator(c.blockValidator))
+		}
+		c.blockGossiper, err = NewGossiper(c.host, c.ps, c.getBlockTopic(), c.logger, opts...)
+		if err != nil {
+			return err
+		}
+		go c.blockGossiper.ProcessMessages(ctx)
+	}
+
 	return nil
 }
 
@@ -402,3 +608,7 @@ func (c *Client) getNamespace() string {
 func (c *Client) getTxTopic() string {
 	return c.getNamespace() + txTopicSuffix
 }
+
+func (c *Client) getBlockTopic() string {
+	return c.getNamespace() + blockTopicSuffix
+}
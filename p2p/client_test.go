@@ -10,6 +10,7 @@ import (
 	"github.com/ipfs/go-datastore"
 	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/ipfs/go-log"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
@@ -40,6 +41,14 @@ func TestClientStartup(t *testing.T) {
 			BlockedPeers:  "/ip4/127.0.0.1/tcp/7676/p2p/12D3KooWM1NFkZozoatQi3JvFE57eBaX56mNgBA68Lk5MTPxBE4U",
 			AllowedPeers:  "",
 		}},
+		{"nat_traversal", config.P2PConfig{
+			EnableNAT:          true,
+			EnableHolePunching: true,
+			StaticRelays:       "/ip4/127.0.0.1/tcp/7677/p2p/12D3KooWAPRFbmWF5dAXvxLnEDxiHWhUuApVDpNNZwShiFAiJqrj",
+		}},
+		{"relay_service", config.P2PConfig{
+			EnableRelayService: true,
+		}},
 	}
 
 	for _, testCase := range testCases {
@@ -114,17 +123,17 @@ func TestGossiping(t *testing.T) {
 	wg.Add(3)
 
 	// ensure that Tx is delivered to client
-	assertRecv := func(tx *GossipMessage) bool {
+	assertRecv := func(tx *GossipMessage) pubsub.ValidationResult {
 		logger.Debug("received tx", "body", string(tx.Data), "from", tx.From)
 		assert.Equal(expectedMsg, tx.Data)
 		wg.Done()
-		return true
+		return pubsub.ValidationAccept
 	}
 
 	// ensure that Tx is not delivered to client
-	assertNotRecv := func(*GossipMessage) bool {
+	assertNotRecv := func(*GossipMessage) pubsub.ValidationResult {
 		t.Fatal("unexpected Tx received")
-		return false
+		return pubsub.ValidationReject
 	}
 
 	validators := []GossipValidator{assertRecv, assertNotRecv, assertNotRecv, assertRecv, assertRecv}
@@ -153,6 +162,36 @@ func TestGossiping(t *testing.T) {
 	wg.Wait()
 }
 
+func TestPeerManagement(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := startTestNetwork(ctx, t, 2, nil, make([]GossipValidator, 2), test.NewLogger(t))
+	require.Empty(clients[0].PeerInfos())
+
+	other := clients[1].host
+	addr := other.Addrs()[0].String() + "/p2p/" + other.ID().String()
+	require.Error(clients[0].DialPeer(ctx, other.Addrs()[0].String()))
+	require.NoError(clients[0].DialPeer(ctx, addr))
+
+	peers := clients[0].PeerInfos()
+	require.Len(peers, 1)
+	assert.Equal(other.ID(), peers[0].ID)
+	assert.True(peers[0].IsOutbound)
+	assert.NotEmpty(peers[0].Addrs)
+
+	require.NoError(clients[0].BlockPeer(ctx, other.ID()))
+	assert.Equal([]peer.ID{other.ID()}, clients[0].ConnectionGater().ListBlockedPeers())
+
+	require.NoError(clients[0].UnblockPeer(ctx, other.ID()))
+	assert.Empty(clients[0].ConnectionGater().ListBlockedPeers())
+
+	require.NoError(clients[0].DialPeer(ctx, addr))
+	require.NoError(clients[0].DisconnectPeer(other.ID()))
+}
+
 func TestSeedStringParsing(t *testing.T) {
 	t.Parallel()
 
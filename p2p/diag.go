@@ -0,0 +1,206 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// Diagnostics describes the state of P2P networking of the node.
+type Diagnostics struct {
+	ID          peer.ID  `json:"id"`
+	ListenAddrs []string `json:"listen_addrs"`
+	// Reachability is the NAT reachability status detected by AutoNAT (Unknown, Public or Private).
+	Reachability        string `json:"reachability"`
+	DHTRoutingTableSize int    `json:"dht_routing_table_size"`
+	ConnectedPeers      int    `json:"connected_peers"`
+	// Topics describes peers of every gossip topic.
+	Topics []TopicDiagnostics `json:"topics"`
+	// ValidationFailures lists peers that recently sent messages rejected by topic validators.
+	ValidationFailures []ValidationFailures `json:"validation_failures"`
+}
+
+// TopicDiagnostics describes peers of a gossip topic.
+type TopicDiagnostics struct {
+	Topic string `json:"topic"`
+	// Peers are connected peers subscribed to the topic.
+	Peers []peer.ID `json:"peers"`
+	// Mesh are peers in the GossipSub mesh of the topic.
+	Mesh []peer.ID `json:"mesh"`
+}
+
+// ValidationFailures describes invalid messages received from a peer since a given time.
+type ValidationFailures struct {
+	Peer  peer.ID   `json:"peer"`
+	Count uint64    `json:"count"`
+	Since time.Time `json:"since"`
+}
+
+// Diagnostics returns current state of P2P networking.
+func (c *Client) Diagnostics() *Diagnostics {
+	diag := &Diagnostics{
+		ID:             c.host.ID(),
+		Reachability:   network.Reachability(c.reachability.Load()).String(),
+		ConnectedPeers: len(c.host.Network().Peers()),
+	}
+	for _, addr := range c.host.Addrs() {
+		diag.ListenAddrs = append(diag.ListenAddrs, addr.String())
+	}
+	if c.dht != nil {
+		diag.DHTRoutingTableSize = c.dht.RoutingTable().Size()
+	}
+	for _, topic := range c.gossipTopics() {
+		diag.Topics = append(diag.Topics, TopicDiagnostics{
+			Topic: topic,
+			Peers: sortedPeers(c.ps.ListPeers(topic)),
+			Mesh:  c.mesh.peers(topic),
+		})
+	}
+	diag.ValidationFailures = c.banner.validationFailures()
+	return diag
+}
+
+// setupDiagnostics tracks NAT reachability status of the host.
+func (c *Client) setupDiagnostics(ctx context.Context) error {
+	sub, err := c.host.EventBus().Subscribe(new(event.EvtLocalReachabilityChanged))
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer sub.Close() //nolint:errcheck
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+				c.reachability.Store(int32(e.(event.EvtLocalReachabilityChanged).Reachability))
+			}
+		}
+	}()
+	return nil
+}
+
+// validationFailures returns peers that sent invalid messages within invalidMessagesWindow.
+func (b *peerBanner) validationFailures() []ValidationFailures {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	res := make([]ValidationFailures, 0, len(b.invalid))
+	for p, invalid := range b.invalid {
+		if time.Since(invalid.since) > invalidMessagesWindow {
+			continue
+		}
+		res = append(res, ValidationFailures{Peer: p, Count: invalid.count, Since: invalid.since})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Count > res[j].Count
+	})
+	return res
+}
+
+func sortedPeers(peers []peer.ID) []peer.ID {
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i] < peers[j]
+	})
+	return peers
+}
+
+// meshTracker tracks GossipSub mesh membership of peers.
+type meshTracker struct {
+	mtx    sync.Mutex
+	topics map[string]map[peer.ID]struct{}
+}
+
+var _ pubsub.RawTracer = &meshTracker{}
+
+func newMeshTracker() *meshTracker {
+	return &meshTracker{
+		topics: make(map[string]map[peer.ID]struct{}),
+	}
+}
+
+// peers returns peers in the mesh of the topic.
+func (t *meshTracker) peers(topic string) []peer.ID {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	res := make([]peer.ID, 0, len(t.topics[topic]))
+	for p := range t.topics[topic] {
+		res = append(res, p)
+	}
+	return sortedPeers(res)
+}
+
+// Graft is a part of pubsub.RawTracer interface.
+func (t *meshTracker) Graft(p peer.ID, topic string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.topics[topic] == nil {
+		t.topics[topic] = make(map[peer.ID]struct{})
+	}
+	t.topics[topic][p] = struct{}{}
+}
+
+// Prune is a part of pubsub.RawTracer interface.
+func (t *meshTracker) Prune(p peer.ID, topic string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.topics[topic], p)
+}
+
+// RemovePeer is a part of pubsub.RawTracer interface.
+func (t *meshTracker) RemovePeer(p peer.ID) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, mesh := range t.topics {
+		delete(mesh, p)
+	}
+}
+
+// Leave is a part of pubsub.RawTracer interface.
+func (t *meshTracker) Leave(topic string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.topics, topic)
+}
+
+// AddPeer is a part of pubsub.RawTracer interface.
+func (t *meshTracker) AddPeer(peer.ID, protocol.ID) {}
+
+// Join is a part of pubsub.RawTracer interface.
+func (t *meshTracker) Join(string) {}
+
+// ValidateMessage is a part of pubsub.RawTracer interface.
+func (t *meshTracker) ValidateMessage(*pubsub.Message) {}
+
+// DeliverMessage is a part of pubsub.RawTracer interface.
+func (t *meshTracker) DeliverMessage(*pubsub.Message) {}
+
+// RejectMessage is a part of pubsub.RawTracer interface.
+func (t *meshTracker) RejectMessage(*pubsub.Message, string) {}
+
+// DuplicateMessage is a part of pubsub.RawTracer interface.
+func (t *meshTracker) DuplicateMessage(*pubsub.Message) {}
+
+// ThrottlePeer is a part of pubsub.RawTracer interface.
+func (t *meshTracker) ThrottlePeer(peer.ID) {}
+
+// RecvRPC is a part of pubsub.RawTracer interface.
+func (t *meshTracker) RecvRPC(*pubsub.RPC) {}
+
+// SendRPC is a part of pubsub.RawTracer interface.
+func (t *meshTracker) SendRPC(*pubsub.RPC, peer.ID) {}
+
+// DropRPC is a part of pubsub.RawTracer interface.
+func (t *meshTracker) DropRPC(*pubsub.RPC, peer.ID) {}
+
+// UndeliverableMessage is a part of pubsub.RawTracer interface.
+func (t *meshTracker) UndeliverableMessage(*pubsub.Message) {}
@@ -0,0 +1,50 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestDiagnostics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	accept := func(*GossipMessage) pubsub.ValidationResult { return pubsub.ValidationAccept }
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: {conns: []int{1}, chainID: "TestDiagnostics"},
+		1: {conns: []int{}, chainID: "TestDiagnostics"},
+	}, []GossipValidator{accept, accept}, test.NewLogger(t))
+
+	// wait for GossipSub heartbeat to graft peers into mesh
+	txTopic := clients[0].getTxTopic()
+	require.Eventually(func() bool {
+		diag := clients[0].Diagnostics()
+		return len(diag.Topics) > 0 && len(diag.Topics[0].Mesh) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	diag := clients[0].Diagnostics()
+	assert.Equal(clients[0].host.ID(), diag.ID)
+	assert.Equal(1, diag.ConnectedPeers)
+	assert.Equal("Unknown", diag.Reachability)
+	assert.NotEmpty(diag.ListenAddrs)
+	assert.Len(diag.Topics, len(clients[0].gossipTopics()))
+	assert.Equal(txTopic, diag.Topics[0].Topic)
+	assert.Equal(clients[1].host.ID(), diag.Topics[0].Peers[0])
+	assert.Equal(clients[1].host.ID(), diag.Topics[0].Mesh[0])
+	assert.Empty(diag.ValidationFailures)
+
+	clients[0].banner.RejectMessage(&pubsub.Message{ReceivedFrom: clients[1].host.ID()}, pubsub.RejectValidationFailed)
+	diag = clients[0].Diagnostics()
+	require.Len(diag.ValidationFailures, 1)
+	assert.Equal(clients[1].host.ID(), diag.ValidationFailures[0].Peer)
+	assert.Equal(uint64(1), diag.ValidationFailures[0].Count)
+}
@@ -3,5 +3,8 @@ package p2p
 import "errors"
 
 var (
-	errNoPrivKey = errors.New("private key not provided")
+	errNoPrivKey      = errors.New("private key not provided")
+	errEmptyAllowlist = errors.New("allowlist-only mode requires at least one allowed peer")
+
+	errCombinedBlockGossipDisabled = errors.New("combined block gossip is disabled")
 )
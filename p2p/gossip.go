@@ -18,7 +18,11 @@ type GossipMessage struct {
 }
 
 // GossipValidator is a callback function type.
-type GossipValidator func(*GossipMessage) bool
+//
+// Validators should return pubsub.ValidationReject only for invalid messages (e.g. malformed or with invalid
+// signature), as rejected messages count toward banning of the sender. Messages that are valid, but not accepted
+// by the node (e.g. transactions failing CheckTx) should be ignored with pubsub.ValidationIgnore.
+type GossipValidator func(*GossipMessage) pubsub.ValidationResult
 
 // GossiperOption sets optional parameters of Gossiper.
 type GossiperOption func(*Gossiper) error
@@ -104,8 +108,8 @@ func (g *Gossiper) ProcessMessages(ctx context.Context) {
 	}
 }
 
-func wrapValidator(validator GossipValidator) pubsub.Validator {
-	return func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+func wrapValidator(validator GossipValidator) pubsub.ValidatorEx {
+	return func(_ context.Context, _ peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
 		return validator(&GossipMessage{
 			Data: msg.Data,
 			From: msg.GetFrom(),
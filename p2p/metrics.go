@@ -30,6 +30,10 @@ type Metrics struct {
 	MessageReceiveBytesTotal metrics.Counter `metrics_labels:"message_type"`
 	// Number of bytes of each message type sent.
 	MessageSendBytesTotal metrics.Counter `metrics_labels:"message_type"`
+	// Number of peers banned for sending invalid messages.
+	BannedPeers metrics.Counter
+	// Number of exchange requests rejected because of request rate limits.
+	RateLimitedRequests metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -83,6 +87,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "message_send_bytes_total",
 			Help:      "Number of bytes of each message type sent.",
 		}, append(labels, "message_type")).With(labelsAndValues...),
+		BannedPeers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "banned_peers_total",
+			Help:      "Number of peers banned for sending invalid messages.",
+		}, labels).With(labelsAndValues...),
+		RateLimitedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rate_limited_requests_total",
+			Help:      "Number of exchange requests rejected because of request rate limits.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -96,5 +112,7 @@ func NopMetrics() *Metrics {
 		NumTxs:                   discard.NewGauge(),
 		MessageReceiveBytesTotal: discard.NewCounter(),
 		MessageSendBytesTotal:    discard.NewCounter(),
+		BannedPeers:              discard.NewCounter(),
+		RateLimitedRequests:      discard.NewCounter(),
 	}
 }
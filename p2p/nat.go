@@ -0,0 +1,29 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p"
+)
+
+// natOptions returns libp2p options enabling NAT traversal and relaying, as configured in P2PConfig.
+//
+// All features are opt-in:
+//   - EnableNAT maps listen port on NAT device (UPnP/NAT-PMP) and serves AutoNAT reachability checks to other peers,
+//   - StaticRelays are used by AutoRelay to obtain relayed addresses, if node is not publicly reachable,
+//   - EnableHolePunching tries to upgrade relayed connections to direct connections (DCUtR),
+//   - EnableRelayService makes node a circuit relay v2 for other peers.
+func (c *Client) natOptions() []libp2p.Option {
+	var opts []libp2p.Option
+	if c.conf.EnableNAT {
+		opts = append(opts, libp2p.NATPortMap(), libp2p.EnableNATService())
+	}
+	if relays := c.parseAddrInfoList(c.conf.StaticRelays); len(relays) > 0 {
+		opts = append(opts, libp2p.EnableRelay(), libp2p.EnableAutoRelayWithStaticRelays(relays))
+	}
+	if c.conf.EnableHolePunching {
+		opts = append(opts, libp2p.EnableRelay(), libp2p.EnableHolePunching())
+	}
+	if c.conf.EnableRelayService {
+		opts = append(opts, libp2p.EnableRelay(), libp2p.EnableRelayService())
+	}
+	return opts
+}
@@ -0,0 +1,324 @@
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+const (
+	// defaultBanThreshold is used only if BanThreshold is not configured for P2P client.
+	defaultBanThreshold = 10
+
+	// defaultBanDuration is used only if BanDuration is not configured for P2P client.
+	defaultBanDuration = 1 * time.Hour
+
+	// invalidMessagesWindow is the period after which the counter of invalid messages received from a peer is reset.
+	invalidMessagesWindow = 10 * time.Minute
+
+	// banCheckInterval defines how often expired bans are lifted.
+	banCheckInterval = 1 * time.Minute
+
+	// peerScoreInspectInterval defines how often peer scores are refreshed from GossipSub.
+	peerScoreInspectInterval = 10 * time.Second
+)
+
+// bansKey is the datastore prefix of persisted bans.
+var bansKey = datastore.NewKey("/p2p/bans")
+
+// AddSyncTopic registers GossipSub topic used by a sync service (see block.SyncService), so that it's covered by
+// peer scoring and diagnostics. It must be called before Start.
+func (c *Client) AddSyncTopic(topic string) {
+	c.syncTopics = append(c.syncTopics, topic)
+}
+
+// gossipTopics returns topics used to gossip transactions, registered sync topics, and combined blocks if enabled.
+func (c *Client) gossipTopics() []string {
+	topics := append([]string{c.getTxTopic()}, c.syncTopics...)
+	if c.conf.CombinedBlockGossip {
+		topics = append(topics, c.getBlockTopic())
+	}
+	return topics
+}
+
+// peerScoreParams returns GossipSub peer scoring configuration for given topics.
+//
+// Peers are penalized for invalid messages (rejected by topic validators) and for GossipSub protocol misbehaviour.
+// The penalty for invalid messages grows quadratically: peer stops receiving gossip after 3 invalid messages,
+// its messages are not published after 4, and it's graylisted (ignored) after 6. Penalties decay within an hour.
+func peerScoreParams(topics []string) (*pubsub.PeerScoreParams, *pubsub.PeerScoreThresholds) {
+	topicParams := make(map[string]*pubsub.TopicScoreParams, len(topics))
+	for _, topic := range topics {
+		topicParams[topic] = &pubsub.TopicScoreParams{
+			SkipAtomicValidation:           true,
+			TopicWeight:                    1,
+			TimeInMeshQuantum:              time.Second,
+			InvalidMessageDeliveriesWeight: -100,
+			InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+		}
+	}
+	params := &pubsub.PeerScoreParams{
+		SkipAtomicValidation:      true,
+		Topics:                    topicParams,
+		AppSpecificScore:          func(peer.ID) float64 { return 0 },
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(time.Hour),
+		DecayInterval:             pubsub.DefaultDecayInterval,
+		DecayToZero:               pubsub.DefaultDecayToZero,
+		RetainScore:               time.Hour,
+	}
+	thresholds := &pubsub.PeerScoreThresholds{
+		SkipAtomicValidation: true,
+		GossipThreshold:      -500,
+		PublishThreshold:     -1000,
+		GraylistThreshold:    -2500,
+	}
+	return params, thresholds
+}
+
+// peerScores holds the most recent GossipSub scores of connected peers.
+type peerScores struct {
+	mtx    sync.RWMutex
+	scores map[peer.ID]float64
+}
+
+func newPeerScores() *peerScores {
+	return &peerScores{scores: make(map[peer.ID]float64)}
+}
+
+func (s *peerScores) update(scores map[peer.ID]float64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.scores = scores
+}
+
+func (s *peerScores) get(p peer.ID) float64 {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.scores[p]
+}
+
+type invalidMessages struct {
+	count uint64
+	since time.Time
+}
+
+// peerBanner tracks invalid messages received via GossipSub and blocks peers that repeatedly send them.
+//
+// Bans are persisted in the datastore and lifted after the ban duration.
+type peerBanner struct {
+	gater     *conngater.BasicConnectionGater
+	host      host.Host
+	ds        datastore.Datastore
+	threshold uint64
+	duration  time.Duration
+
+	// protected peers (e.g. explicitly allowed or blocked) are never banned nor unbanned.
+	protected map[peer.ID]struct{}
+
+	mtx     sync.Mutex
+	invalid map[peer.ID]*invalidMessages
+
+	logger  log.Logger
+	metrics *Metrics
+}
+
+var _ pubsub.RawTracer = &peerBanner{}
+
+func newPeerBanner(gater *conngater.BasicConnectionGater, ds datastore.Datastore, threshold uint64, duration time.Duration, logger log.Logger, metrics *Metrics) *peerBanner {
+	return &peerBanner{
+		gater:     gater,
+		ds:        ds,
+		threshold: threshold,
+		duration:  duration,
+		protected: make(map[peer.ID]struct{}),
+		invalid:   make(map[peer.ID]*invalidMessages),
+		logger:    logger,
+		metrics:   metrics,
+	}
+}
+
+// protect ensures that peers are never banned nor unbanned automatically.
+func (b *peerBanner) protect(peers []peer.AddrInfo) {
+	for _, p := range peers {
+		b.protected[p.ID] = struct{}{}
+	}
+}
+
+// RejectMessage is invoked by GossipSub when message is rejected.
+func (b *peerBanner) RejectMessage(msg *pubsub.Message, reason string) {
+	if reason != pubsub.RejectValidationFailed && reason != pubsub.RejectInvalidSignature {
+		return
+	}
+	b.reportInvalid(msg.ReceivedFrom, msg.GetTopic())
+}
+
+// reportInvalid counts invalid message received from the peer via given topic or protocol, and bans the peer once
+// the threshold is reached.
+func (b *peerBanner) reportInvalid(p peer.ID, topic string) {
+	if _, ok := b.protected[p]; ok || (b.host != nil && p == b.host.ID()) {
+		return
+	}
+
+	b.mtx.Lock()
+	invalid, ok := b.invalid[p]
+	if !ok || time.Since(invalid.since) > invalidMessagesWindow {
+		invalid = &invalidMessages{since: time.Now()}
+		b.invalid[p] = invalid
+	}
+	invalid.count++
+	ban := invalid.count >= b.threshold
+	if ban {
+		delete(b.invalid, p)
+	}
+	b.mtx.Unlock()
+
+	if ban {
+		b.ban(context.Background(), p, topic)
+	}
+}
+
+func (b *peerBanner) ban(ctx context.Context, p peer.ID, topic string) {
+	b.logger.Info("banning peer for sending invalid messages", "peer", p, "topic", topic, "duration", b.duration)
+	if err := b.gater.BlockPeer(p); err != nil {
+		b.logger.Error("failed to block peer", "peer", p, "error", err)
+		return
+	}
+	b.metrics.BannedPeers.Add(1)
+	expiry := make([]byte, 8)
+	binary.BigEndian.PutUint64(expiry, uint64(time.Now().Add(b.duration).UnixNano())) //nolint:gosec
+	if err := b.ds.Put(ctx, bansKey.ChildString(p.String()), expiry); err != nil {
+		b.logger.Error("failed to persist peer ban", "peer", p, "error", err)
+	}
+	if b.host != nil {
+		if err := b.host.Network().ClosePeer(p); err != nil {
+			b.logger.Error("failed to disconnect banned peer", "peer", p, "error", err)
+		}
+	}
+}
+
+// forget removes persisted ban and invalid messages counter of the peer.
+func (b *peerBanner) forget(ctx context.Context, p peer.ID) error {
+	b.mtx.Lock()
+	delete(b.invalid, p)
+	b.mtx.Unlock()
+	return b.ds.Delete(ctx, bansKey.ChildString(p.String()))
+}
+
+// liftExpiredBans unblocks peers with expired bans and removes them from the datastore.
+func (b *peerBanner) liftExpiredBans(ctx context.Context) error {
+	results, err := b.ds.Query(ctx, query.Query{Prefix: bansKey.String()})
+	if err != nil {
+		return err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		key := datastore.NewKey(entry.Key)
+		p, err := peer.Decode(key.BaseNamespace())
+		if err != nil || len(entry.Value) != 8 {
+			b.logger.Error("invalid peer ban entry", "key", entry.Key)
+			continue
+		}
+		if now.Before(time.Unix(0, int64(binary.BigEndian.Uint64(entry.Value)))) { //nolint:gosec
+			continue
+		}
+		if _, ok := b.protected[p]; !ok {
+			b.logger.Info("lifting expired peer ban", "peer", p)
+			if err := b.gater.UnblockPeer(p); err != nil {
+				return err
+			}
+		}
+		if err := b.ds.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneInvalidMessages removes counters of invalid messages that are older than invalidMessagesWindow, so that
+// peers that sent few invalid messages are not tracked forever.
+func (b *peerBanner) pruneInvalidMessages() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for p, invalid := range b.invalid {
+		if time.Since(invalid.since) > invalidMessagesWindow {
+			delete(b.invalid, p)
+		}
+	}
+}
+
+// liftExpiredBansLoop periodically lifts expired bans and prunes stale invalid messages counters, until ctx is
+// canceled.
+func (b *peerBanner) liftExpiredBansLoop(ctx context.Context) {
+	ticker := time.NewTicker(banCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.pruneInvalidMessages()
+			if err := b.liftExpiredBans(ctx); err != nil && ctx.Err() == nil {
+				b.logger.Error("failed to lift expired peer bans", "error", err)
+			}
+		}
+	}
+}
+
+// AddPeer is a part of pubsub.RawTracer interface.
+func (b *peerBanner) AddPeer(peer.ID, protocol.ID) {}
+
+// RemovePeer is a part of pubsub.RawTracer interface.
+func (b *peerBanner) RemovePeer(peer.ID) {}
+
+// Join is a part of pubsub.RawTracer interface.
+func (b *peerBanner) Join(string) {}
+
+// Leave is a part of pubsub.RawTracer interface.
+func (b *peerBanner) Leave(string) {}
+
+// Graft is a part of pubsub.RawTracer interface.
+func (b *peerBanner) Graft(peer.ID, string) {}
+
+// Prune is a part of pubsub.RawTracer interface.
+func (b *peerBanner) Prune(peer.ID, string) {}
+
+// ValidateMessage is a part of pubsub.RawTracer interface.
+func (b *peerBanner) ValidateMessage(*pubsub.Message) {}
+
+// DeliverMessage is a part of pubsub.RawTracer interface.
+func (b *peerBanner) DeliverMessage(*pubsub.Message) {}
+
+// DuplicateMessage is a part of pubsub.RawTracer interface.
+func (b *peerBanner) DuplicateMessage(*pubsub.Message) {}
+
+// ThrottlePeer is a part of pubsub.RawTracer interface.
+func (b *peerBanner) ThrottlePeer(peer.ID) {}
+
+// RecvRPC is a part of pubsub.RawTracer interface.
+func (b *peerBanner) RecvRPC(*pubsub.RPC) {}
+
+// SendRPC is a part of pubsub.RawTracer interface.
+func (b *peerBanner) SendRPC(*pubsub.RPC, peer.ID) {}
+
+// DropRPC is a part of pubsub.RawTracer interface.
+func (b *peerBanner) DropRPC(*pubsub.RPC, peer.ID) {}
+
+// UndeliverableMessage is a part of pubsub.RawTracer interface.
+func (b *peerBanner) UndeliverableMessage(*pubsub.Message) {}
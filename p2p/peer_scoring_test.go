@@ -0,0 +1,121 @@
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goheaderp2p "github.com/celestiaorg/go-header/p2p"
+
+	"github.com/rollkit/rollkit/config"
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestPeerBanning(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	gater, err := conngater.NewBasicConnectionGater(ds)
+	require.NoError(err)
+	banner := newPeerBanner(gater, ds, 3, time.Hour, test.NewLogger(t), NopMetrics())
+
+	_, pubKey, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	misbehaving, err := peer.IDFromPublicKey(pubKey)
+	require.NoError(err)
+	msg := &pubsub.Message{ReceivedFrom: misbehaving}
+
+	// ignored messages don't count
+	for i := 0; i < 5; i++ {
+		banner.RejectMessage(msg, pubsub.RejectValidationIgnored)
+	}
+	assert.Empty(gater.ListBlockedPeers())
+
+	// messages rejected by validators and invalid messages received outside GossipSub are counted together
+	for i := 0; i < 2; i++ {
+		banner.RejectMessage(msg, pubsub.RejectValidationFailed)
+	}
+	assert.Empty(gater.ListBlockedPeers())
+	banner.reportInvalid(misbehaving, "/mempool")
+	assert.Equal([]peer.ID{misbehaving}, gater.ListBlockedPeers())
+
+	// ban is persisted and not lifted before it expires
+	gater, err = conngater.NewBasicConnectionGater(ds)
+	require.NoError(err)
+	banner = newPeerBanner(gater, ds, 3, time.Hour, test.NewLogger(t), NopMetrics())
+	require.NoError(banner.liftExpiredBans(ctx))
+	assert.Equal([]peer.ID{misbehaving}, gater.ListBlockedPeers())
+
+	// expired ban is lifted
+	expired := make([]byte, 8)
+	require.NoError(ds.Put(ctx, bansKey.ChildString(misbehaving.String()), expired))
+	require.NoError(banner.liftExpiredBans(ctx))
+	assert.Empty(gater.ListBlockedPeers())
+	has, err := ds.Has(ctx, bansKey.ChildString(misbehaving.String()))
+	require.NoError(err)
+	assert.False(has)
+
+	// stale invalid messages counters are pruned
+	banner.RejectMessage(msg, pubsub.RejectValidationFailed)
+	banner.pruneInvalidMessages()
+	assert.Len(banner.invalid, 1)
+	banner.invalid[misbehaving].since = time.Now().Add(-invalidMessagesWindow - time.Second)
+	banner.pruneInvalidMessages()
+	assert.Empty(banner.invalid)
+}
+
+func TestPeerBanningProtectedPeers(t *testing.T) {
+	require := require.New(t)
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	gater, err := conngater.NewBasicConnectionGater(ds)
+	require.NoError(err)
+	banner := newPeerBanner(gater, ds, 1, time.Hour, test.NewLogger(t), NopMetrics())
+
+	_, pubKey, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	allowed, err := peer.IDFromPublicKey(pubKey)
+	require.NoError(err)
+	banner.protect([]peer.AddrInfo{{ID: allowed}})
+
+	banner.RejectMessage(&pubsub.Message{ReceivedFrom: allowed}, pubsub.RejectValidationFailed)
+	require.Empty(gater.ListBlockedPeers())
+}
+
+func TestPeerScoreParams(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	client, err := NewClient(config.P2PConfig{}, privKey, "TestChain", dssync.MutexWrap(datastore.NewMapDatastore()), test.NewLogger(t), NopMetrics())
+	require.NoError(err)
+	client.AddSyncTopic(goheaderp2p.PubsubTopicID("TestChain-headerSync"))
+	client.AddSyncTopic(goheaderp2p.PubsubTopicID("TestChain-dataSync"))
+	params, thresholds := peerScoreParams(client.gossipTopics())
+	require.Len(params.Topics, 3)
+	require.ErrorIs(client.GossipBlock(ctx, []byte("block")), errCombinedBlockGossipDisabled)
+
+	client.conf.CombinedBlockGossip = true
+	params, thresholds = peerScoreParams(client.gossipTopics())
+	require.Len(params.Topics, 4)
+
+	host, err := mocknet.New().GenPeer()
+	require.NoError(err)
+	_, err = pubsub.NewGossipSub(ctx, host, pubsub.WithPeerScore(params, thresholds))
+	require.NoError(err)
+}
@@ -0,0 +1,99 @@
+package p2p
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/pnet"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// privateNetworkOptions returns libp2p options restricting connectivity to nodes sharing the pre-shared key
+// read from PrivateNetworkKeyFile, if configured.
+//
+// Key file uses the format of IPFS swarm.key files:
+//
+//	/key/swarm/psk/1.0.0/
+//	/base16/
+//	<64 hex characters>
+func (c *Client) privateNetworkOptions() ([]libp2p.Option, error) {
+	if c.conf.PrivateNetworkKeyFile == "" {
+		return nil, nil
+	}
+	f, err := os.Open(c.conf.PrivateNetworkKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open private network key file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	psk, err := pnet.DecodeV1PSK(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private network key: %w", err)
+	}
+	c.logger.Info("private network mode enabled")
+	return []libp2p.Option{libp2p.PrivateNetwork(psk)}, nil
+}
+
+// connectionGater returns connection gater used by libp2p host.
+//
+// In allowlist-only mode, connections with peers not listed in AllowedPeers are rejected.
+func (c *Client) connectionGater() (connmgr.ConnectionGater, error) {
+	if !c.conf.AllowlistOnly {
+		return c.gater, nil
+	}
+	allowed := c.parseAddrInfoList(c.conf.AllowedPeers)
+	if len(allowed) == 0 {
+		return nil, errEmptyAllowlist
+	}
+	c.logger.Info("allowlist-only mode enabled", "allowed", len(allowed))
+	return newAllowlistGater(c.gater, allowed), nil
+}
+
+// allowlistGater extends BasicConnectionGater by rejecting all peers that are not explicitly allowed.
+type allowlistGater struct {
+	*conngater.BasicConnectionGater
+	allowed map[peer.ID]struct{}
+}
+
+var _ connmgr.ConnectionGater = &allowlistGater{}
+
+func newAllowlistGater(gater *conngater.BasicConnectionGater, allowed []peer.AddrInfo) *allowlistGater {
+	g := &allowlistGater{
+		BasicConnectionGater: gater,
+		allowed:              make(map[peer.ID]struct{}, len(allowed)),
+	}
+	for _, p := range allowed {
+		g.allowed[p.ID] = struct{}{}
+	}
+	return g
+}
+
+func (g *allowlistGater) isAllowed(p peer.ID) bool {
+	_, ok := g.allowed[p]
+	return ok
+}
+
+// InterceptPeerDial rejects outbound connections to peers that are not allowed.
+func (g *allowlistGater) InterceptPeerDial(p peer.ID) bool {
+	return g.isAllowed(p) && g.BasicConnectionGater.InterceptPeerDial(p)
+}
+
+// InterceptAddrDial rejects outbound connections to peers that are not allowed.
+func (g *allowlistGater) InterceptAddrDial(p peer.ID, a multiaddr.Multiaddr) bool {
+	return g.isAllowed(p) && g.BasicConnectionGater.InterceptAddrDial(p, a)
+}
+
+// InterceptSecured rejects connections with peers that are not allowed, once remote peer ID is known.
+func (g *allowlistGater) InterceptSecured(dir network.Direction, p peer.ID, cma network.ConnMultiaddrs) bool {
+	return g.isAllowed(p) && g.BasicConnectionGater.InterceptSecured(dir, p, cma)
+}
+
+// InterceptUpgraded is a part of connmgr.ConnectionGater interface.
+func (g *allowlistGater) InterceptUpgraded(conn network.Conn) (bool, control.DisconnectReason) {
+	return g.BasicConnectionGater.InterceptUpgraded(conn)
+}
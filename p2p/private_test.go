@@ -0,0 +1,106 @@
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestPrivateNetwork(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	writeKey := func(name string) string {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		require.NoError(err)
+		path := filepath.Join(dir, name)
+		content := "/key/swarm/psk/1.0.0/\n/base16/\n" + hex.EncodeToString(key)
+		require.NoError(os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	key1, key2 := writeKey("swarm1.key"), writeKey("swarm2.key")
+
+	newHost := func(keyFile string) host.Host {
+		privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0", PrivateNetworkKeyFile: keyFile}
+		client, err := NewClient(conf, privKey, "TestPrivateNetwork", dssync.MutexWrap(datastore.NewMapDatastore()), test.NewLogger(t), NopMetrics())
+		require.NoError(err)
+		h, err := client.listen(ctx)
+		require.NoError(err)
+		t.Cleanup(func() { _ = h.Close() })
+		return h
+	}
+	addrInfo := func(h host.Host) peer.AddrInfo {
+		return peer.AddrInfo{ID: h.ID(), Addrs: h.Addrs()}
+	}
+
+	h1, h2, h3, public := newHost(key1), newHost(key1), newHost(key2), newHost("")
+	require.NoError(h1.Connect(ctx, addrInfo(h2)))
+	require.Error(h1.Connect(ctx, addrInfo(h3)))
+	require.Error(h1.Connect(ctx, addrInfo(public)))
+	require.Error(public.Connect(ctx, addrInfo(h1)))
+
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0", PrivateNetworkKeyFile: filepath.Join(dir, "missing.key")}
+	client, err := NewClient(conf, privKey, "TestPrivateNetwork", dssync.MutexWrap(datastore.NewMapDatastore()), test.NewLogger(t), NopMetrics())
+	require.NoError(err)
+	_, err = client.listen(ctx)
+	require.ErrorContains(err, "failed to open private network key file")
+}
+
+func TestAllowlistGater(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	newPeerID := func() peer.ID {
+		_, pubKey, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		id, err := peer.IDFromPublicKey(pubKey)
+		require.NoError(err)
+		return id
+	}
+	allowed, other := newPeerID(), newPeerID()
+
+	basic, err := conngater.NewBasicConnectionGater(dssync.MutexWrap(datastore.NewMapDatastore()))
+	require.NoError(err)
+	gater := newAllowlistGater(basic, []peer.AddrInfo{{ID: allowed}})
+
+	assert.True(gater.InterceptPeerDial(allowed))
+	assert.False(gater.InterceptPeerDial(other))
+	assert.True(gater.InterceptSecured(network.DirInbound, allowed, nil))
+	assert.False(gater.InterceptSecured(network.DirInbound, other, nil))
+
+	// blocking still applies to allowed peers
+	require.NoError(basic.BlockPeer(allowed))
+	assert.False(gater.InterceptPeerDial(allowed))
+}
+
+func TestAllowlistOnlyRequiresAllowedPeers(t *testing.T) {
+	privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0", AllowlistOnly: true}
+	client, err := NewClient(conf, privKey, "TestAllowlistOnly", dssync.MutexWrap(datastore.NewMapDatastore()), test.NewLogger(t), NopMetrics())
+	require.NoError(t, err)
+	_, err = client.listen(context.Background())
+	require.ErrorIs(t, err, errEmptyAllowlist)
+}
@@ -0,0 +1,230 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/rollkit/rollkit/ratelimit"
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+const (
+	// limitedStreamChunkSize is the maximum size of a single throttled write.
+	limitedStreamChunkSize = 16 << 10
+	// limitedStreamMaxWait is the maximum time a single chunk waits for send limiters. It also bounds the debt of
+	// limiters, as a chunk that would have to wait longer is not sent at all. Send rates are at least
+	// config.MinSendRate, so that a chunk can be sent within this time.
+	limitedStreamMaxWait = 10 * time.Second
+)
+
+// errSendRateExceeded is returned by writes that would wait for send limiters longer than limitedStreamMaxWait.
+var errSendRateExceeded = errors.New("send rate limit exceeded")
+
+// bandwidthLimitedHost throttles writes to all streams, inbound and outbound, of protocols registered with it.
+type bandwidthLimitedHost struct {
+	host.Host
+
+	send        *ratelimit.Bucket
+	sendPerPeer *ratelimit.Buckets[peer.ID]
+}
+
+// newBandwidthLimitedHost returns host limiting the rate of data sent to all peers (send) and every single peer
+// (sendPerPeer). If both limiters are nil, h is returned as is.
+func newBandwidthLimitedHost(h host.Host, send *ratelimit.Bucket, sendPerPeer *ratelimit.Buckets[peer.ID]) host.Host {
+	if send == nil && sendPerPeer == nil {
+		return h
+	}
+	return &bandwidthLimitedHost{Host: h, send: send, sendPerPeer: sendPerPeer}
+}
+
+func (h *bandwidthLimitedHost) limit(stream network.Stream) network.Stream {
+	return newLimitedStream(stream, h.send, h.sendPerPeer.Get(stream.Conn().RemotePeer()))
+}
+
+// SetStreamHandler registers handler of throttled streams.
+func (h *bandwidthLimitedHost) SetStreamHandler(pid protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(pid, func(stream network.Stream) {
+		handler(h.limit(stream))
+	})
+}
+
+// SetStreamHandlerMatch registers handler of throttled streams.
+func (h *bandwidthLimitedHost) SetStreamHandlerMatch(pid protocol.ID, match func(protocol.ID) bool, handler network.StreamHandler) {
+	h.Host.SetStreamHandlerMatch(pid, match, func(stream network.Stream) {
+		handler(h.limit(stream))
+	})
+}
+
+// NewStream opens throttled stream.
+func (h *bandwidthLimitedHost) NewStream(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+	stream, err := h.Host.NewStream(ctx, p, pids...)
+	if err != nil {
+		return nil, err
+	}
+	return h.limit(stream), nil
+}
+
+// limitedHost rejects streams of peers exceeding request rate limit.
+type limitedHost struct {
+	host.Host
+
+	requests *ratelimit.Buckets[peer.ID]
+
+	logger  log.Logger
+	metrics *Metrics
+}
+
+// ExchangeServerHost returns host used by header and data exchange servers.
+//
+// Streams handled by protocols registered with returned host are subject to request rate limits, separate for every
+// returned host, and to bandwidth limits shared by all returned hosts. Other protocols, e.g. GossipSub, DHT and
+// identify, are not limited, so that serving blocks to syncing peers can't delay gossip nor fail its writes.
+func (c *Client) ExchangeServerHost() host.Host {
+	return &limitedHost{
+		Host:     newBandwidthLimitedHost(c.host, c.sendLimiter, c.peerSendLimiters),
+		requests: ratelimit.NewBuckets[peer.ID](c.conf.ExchangeRequestRate),
+		logger:   c.logger,
+		metrics:  c.metrics,
+	}
+}
+
+// SetStreamHandler registers handler, rejecting streams of peers exceeding request rate limit.
+func (h *limitedHost) SetStreamHandler(pid protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(pid, func(stream network.Stream) {
+		p := stream.Conn().RemotePeer()
+		if !h.requests.Get(p).Allow() {
+			h.logger.Debug("request rate limit exceeded", "peer", p, "protocol", pid)
+			h.metrics.RateLimitedRequests.Add(1)
+			_ = stream.Reset()
+			return
+		}
+		handler(stream)
+	})
+}
+
+// limitedStream throttles writes to the stream.
+type limitedStream struct {
+	network.Stream
+	limiters []*ratelimit.Bucket
+
+	mtx      sync.Mutex
+	deadline time.Time
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newLimitedStream(stream network.Stream, limiters ...*ratelimit.Bucket) *limitedStream {
+	return &limitedStream{
+		Stream:   stream,
+		limiters: limiters,
+		closed:   make(chan struct{}),
+	}
+}
+
+// Write writes data in chunks, waiting until all limiters allow sending a chunk. Waiting is interrupted if the
+// stream is closed or write deadline is exceeded, and a chunk is not sent at all if it would have to wait longer
+// than limitedStreamMaxWait.
+func (s *limitedStream) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		chunk := b[:min(len(b), limitedStreamChunkSize)]
+		if err := s.wait(float64(len(chunk))); err != nil {
+			return written, err
+		}
+		n, err := s.Stream.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
+
+// wait reserves n tokens from all limiters and waits until they are available.
+func (s *limitedStream) wait(n float64) error {
+	var wait time.Duration
+	for i, l := range s.limiters {
+		w, ok := l.Reserve(n, limitedStreamMaxWait)
+		if !ok {
+			s.cancel(i, n)
+			return errSendRateExceeded
+		}
+		wait = max(wait, w)
+	}
+	if wait == 0 {
+		return nil
+	}
+	s.mtx.Lock()
+	deadline := s.deadline
+	s.mtx.Unlock()
+	if !deadline.IsZero() && time.Until(deadline) < wait {
+		s.cancel(len(s.limiters), n)
+		return os.ErrDeadlineExceeded
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-s.closed:
+		s.cancel(len(s.limiters), n)
+		return network.ErrReset
+	}
+}
+
+// cancel returns n tokens to first count limiters.
+func (s *limitedStream) cancel(count int, n float64) {
+	for _, l := range s.limiters[:count] {
+		l.Cancel(n)
+	}
+}
+
+func (s *limitedStream) interrupt() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// SetDeadline sets deadline of the stream, including waiting for send limiters.
+func (s *limitedStream) SetDeadline(t time.Time) error {
+	s.setDeadline(t)
+	return s.Stream.SetDeadline(t)
+}
+
+// SetWriteDeadline sets write deadline of the stream, including waiting for send limiters.
+func (s *limitedStream) SetWriteDeadline(t time.Time) error {
+	s.setDeadline(t)
+	return s.Stream.SetWriteDeadline(t)
+}
+
+func (s *limitedStream) setDeadline(t time.Time) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.deadline = t
+}
+
+// Close closes the stream, interrupting pending writes.
+func (s *limitedStream) Close() error {
+	s.interrupt()
+	return s.Stream.Close()
+}
+
+// CloseWrite closes the stream for writing, interrupting pending writes.
+func (s *limitedStream) CloseWrite() error {
+	s.interrupt()
+	return s.Stream.CloseWrite()
+}
+
+// Reset resets the stream, interrupting pending writes.
+func (s *limitedStream) Reset() error {
+	s.interrupt()
+	return s.Stream.Reset()
+}
@@ -0,0 +1,130 @@
+package p2p
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/ratelimit"
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestLimitedHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn, err := mocknet.FullMeshConnected(2)
+	require.NoError(err)
+	server, client := mn.Hosts()[0], mn.Hosts()[1]
+
+	const protocolID = "/test/exchange"
+	h := &limitedHost{
+		Host:     server,
+		requests: ratelimit.NewBuckets[peer.ID](1),
+		logger:   test.NewLogger(t),
+		metrics:  NopMetrics(),
+	}
+	h.SetStreamHandler(protocolID, func(s network.Stream) {
+		defer s.Close() //nolint:errcheck
+		_, _ = s.Write([]byte("response"))
+	})
+
+	read := func() error {
+		s, err := client.NewStream(ctx, server.ID(), protocolID)
+		require.NoError(err)
+		defer s.Close() //nolint:errcheck
+		_, err = io.ReadAll(s)
+		return err
+	}
+
+	assert.NoError(read())
+	// second request within a second is rejected
+	assert.Error(read())
+}
+
+func TestExchangeServerHostBandwidthLimits(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mn, err := mocknet.FullMeshConnected(1)
+	require.NoError(err)
+	c := &Client{
+		host:        mn.Hosts()[0],
+		sendLimiter: ratelimit.NewBucket(config.MinSendRate),
+		logger:      test.NewLogger(t),
+		metrics:     NopMetrics(),
+	}
+
+	// only exchange servers are throttled, other protocols use the host as is
+	h, ok := c.ExchangeServerHost().(*limitedHost)
+	require.True(ok)
+	assert.IsType(&bandwidthLimitedHost{}, h.Host)
+	assert.Equal(mn.Hosts()[0], c.Host())
+}
+
+func TestBandwidthLimitedHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn, err := mocknet.FullMeshConnected(2)
+	require.NoError(err)
+	server, client := mn.Hosts()[0], mn.Hosts()[1]
+	assert.Equal(server, newBandwidthLimitedHost(server, nil, nil))
+
+	const (
+		protocolID = "/test/bandwidth"
+		rate       = 64 << 10
+		size       = rate + rate/2
+	)
+	h := newBandwidthLimitedHost(server, ratelimit.NewBucket(rate), nil)
+
+	write := func(stream network.Stream, size int) error {
+		_, err := stream.Write(make([]byte, size))
+		return err
+	}
+
+	// outbound streams are limited too: burst is used immediately, remaining part is throttled
+	client.SetStreamHandler(protocolID, func(s network.Stream) {
+		defer s.Close() //nolint:errcheck
+		_, _ = io.Copy(io.Discard, s)
+	})
+	s, err := h.NewStream(ctx, client.ID(), protocolID)
+	require.NoError(err)
+	defer s.Reset() //nolint:errcheck
+	start := time.Now()
+	require.NoError(write(s, size))
+	assert.GreaterOrEqual(time.Since(start), 400*time.Millisecond)
+
+	// write is not delayed beyond deadline (mock streams don't support deadlines, but limiter respects it anyway)
+	_ = s.SetWriteDeadline(time.Now().Add(100 * time.Millisecond))
+	assert.ErrorIs(write(s, rate), os.ErrDeadlineExceeded)
+	_ = s.SetWriteDeadline(time.Time{})
+
+	// chunk that would wait too long is not sent, and tokens are not taken
+	assert.ErrorIs(write(newLimitedStream(s, ratelimit.NewBucket(1)), 1<<10), errSendRateExceeded)
+
+	// pending write is interrupted by reset
+	done := make(chan error, 1)
+	go func() { done <- write(s, 2*rate) }()
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(s.Reset())
+	select {
+	case err := <-done:
+		assert.Error(err)
+	case <-time.After(time.Second):
+		t.Fatal("write not interrupted")
+	}
+}
@@ -0,0 +1,143 @@
+// Package ratelimit provides token bucket rate limiters shared by P2P and RPC servers.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucketsPruneSize is the number of per-key limiters after which limiters of idle keys are removed.
+const bucketsPruneSize = 1000
+
+// Bucket is a token bucket rate limiter, refilled with rate tokens per second, up to one second worth of tokens.
+// A nil *Bucket doesn't limit anything.
+type Bucket struct {
+	rate  float64
+	burst float64
+
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket returns new limiter, or nil if rate is not positive (unlimited).
+func NewBucket(rate float64) *Bucket {
+	if rate <= 0 {
+		return nil
+	}
+	burst := math.Max(rate, 1)
+	return &Bucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// refill must be called with mtx held.
+func (b *Bucket) refill() {
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// Allow takes a single token, if available.
+func (b *Bucket) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Reserve takes n tokens, even if they are not available yet, and returns how long caller has to wait for them.
+// If caller would have to wait longer than maxWait, tokens are not taken and false is returned, so the debt of the
+// bucket never exceeds maxWait worth of tokens.
+func (b *Bucket) Reserve(n float64, maxWait time.Duration) (time.Duration, bool) {
+	if b == nil {
+		return 0, true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.refill()
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0, true
+	}
+	wait := time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+	if wait > maxWait {
+		return 0, false
+	}
+	b.tokens -= n
+	return wait, true
+}
+
+// Cancel returns n tokens taken by Reserve, e.g. if caller gave up waiting for them.
+func (b *Bucket) Cancel(n float64) {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.refill()
+	b.tokens = math.Min(b.burst, b.tokens+n)
+}
+
+// Full reports whether bucket wasn't used recently.
+func (b *Bucket) Full() bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.refill()
+	return b.tokens >= b.burst
+}
+
+// Buckets holds separate limiters for every key (e.g. peer ID or IP address). A nil *Buckets doesn't limit anything.
+type Buckets[K comparable] struct {
+	rate float64
+
+	mtx     sync.Mutex
+	buckets map[K]*Bucket
+}
+
+// NewBuckets returns new per-key limiters, or nil if rate is not positive (unlimited).
+func NewBuckets[K comparable](rate float64) *Buckets[K] {
+	if rate <= 0 {
+		return nil
+	}
+	return &Buckets[K]{
+		rate:    rate,
+		buckets: make(map[K]*Bucket),
+	}
+}
+
+// Get returns limiter of the key.
+func (l *Buckets[K]) Get(key K) *Bucket {
+	if l == nil {
+		return nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= bucketsPruneSize {
+			for k, bucket := range l.buckets {
+				if bucket.Full() {
+					delete(l.buckets, k)
+				}
+			}
+		}
+		b = NewBucket(l.rate)
+		l.buckets[key] = b
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	var unlimited *Bucket
+	assert.Nil(NewBucket(0))
+	assert.True(unlimited.Allow())
+	wait, ok := unlimited.Reserve(1<<30, 0)
+	assert.True(ok)
+	assert.Zero(wait)
+	unlimited.Cancel(1)
+
+	b := NewBucket(2)
+	assert.True(b.Allow())
+	assert.True(b.Allow())
+	assert.False(b.Allow())
+	assert.False(b.Full())
+
+	b = NewBucket(1000)
+	wait, ok = b.Reserve(1000, 0)
+	assert.True(ok)
+	assert.Zero(wait)
+	wait, ok = b.Reserve(500, time.Second)
+	assert.True(ok)
+	assert.InDelta(500*time.Millisecond, wait, float64(50*time.Millisecond))
+
+	// debt is bounded by maximum wait
+	_, ok = b.Reserve(1000, time.Second)
+	assert.False(ok)
+
+	// cancelled tokens can be reserved again, up to burst
+	b.Cancel(500)
+	b.Cancel(2000)
+	wait, ok = b.Reserve(1000, 0)
+	assert.True(ok)
+	assert.Zero(wait)
+}
+
+func TestBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	var unlimited *Buckets[string]
+	assert.Nil(NewBuckets[string](0))
+	assert.Nil(unlimited.Get("a"))
+
+	l := NewBuckets[string](1)
+	assert.True(l.Get("a").Allow())
+	assert.False(l.Get("a").Allow())
+	// limits are separate for every key
+	assert.True(l.Get("b").Allow())
+
+	// limiters of idle keys are pruned
+	for i := 0; i < bucketsPruneSize; i++ {
+		l.Get(strconv.Itoa(i))
+	}
+	assert.LessOrEqual(len(l.buckets), bucketsPruneSize)
+	assert.Contains(l.buckets, "a")
+}
@@ -0,0 +1,310 @@
+// Package access implements authentication, method access control and rate limiting of RPC callers, shared by
+// JSON-RPC and gRPC servers.
+package access
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	rollconf "github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/ratelimit"
+)
+
+// maxJWTLifetime is the longest accepted validity of a JWT, from now to its expiration time, so that leaked tokens
+// can't be used indefinitely.
+const maxJWTLifetime = time.Hour
+
+var (
+	// ErrUnauthorized is returned if caller's credentials are invalid or missing.
+	ErrUnauthorized = errors.New("invalid credentials")
+	// ErrMethodNotAllowed is returned if caller is not allowed to call the method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	// ErrRateLimited is returned if caller exceeded the request rate limit.
+	ErrRateLimited = errors.New("request rate limit exceeded")
+)
+
+// Control authenticates RPC callers, checks if they are allowed to call methods and limits rate of requests from
+// every IP address. A nil *Control allows everything.
+type Control struct {
+	token     string
+	jwtSecret []byte
+
+	// public and authenticated are sets of methods allowed for anonymous and authenticated callers. A nil
+	// authenticated set allows all methods.
+	public        map[string]struct{}
+	authenticated map[string]struct{}
+	// grpcMethods maps full gRPC method names to names of methods in access lists.
+	grpcMethods map[string]string
+
+	limiter *ratelimit.Buckets[string]
+}
+
+// New returns access control configured by conf, or nil if neither authentication nor rate limiting is configured.
+// Method names in access lists must be one of methods; if methods is nil, access lists are not used.
+func New(conf rollconf.RPCConfig, methods []string) (*Control, error) {
+	if !conf.AuthEnabled() && conf.RequestRate <= 0 {
+		return nil, nil
+	}
+	c := &Control{
+		token:   conf.AuthToken,
+		limiter: ratelimit.NewBuckets[string](conf.RequestRate),
+	}
+	if conf.JWTSecretFile != "" {
+		secret, err := os.ReadFile(conf.JWTSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT secret file: %w", err)
+		}
+		c.jwtSecret = bytes.TrimSpace(secret)
+		if len(c.jwtSecret) == 0 {
+			return nil, errors.New("JWT secret file is empty")
+		}
+	}
+	if !conf.AuthEnabled() || methods == nil {
+		return c, nil
+	}
+
+	known := make(map[string]struct{}, len(methods))
+	for _, name := range methods {
+		known[name] = struct{}{}
+	}
+	var err error
+	if c.public, err = parseMethodList(conf.PublicMethods, known); err != nil {
+		return nil, err
+	}
+	if conf.AuthenticatedMethods != "" {
+		if c.authenticated, err = parseMethodList(conf.AuthenticatedMethods, known); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// NewGRPC returns access control of gRPC calls configured by conf, like New. Access lists apply to gRPC methods
+// mapped by grpcMethods (from full gRPC method names) to one of methods; other gRPC methods are not public, and are
+// only allowed for authenticated callers if authenticated methods are not restricted.
+func NewGRPC(conf rollconf.RPCConfig, methods []string, grpcMethods map[string]string) (*Control, error) {
+	c, err := New(conf, methods)
+	if c == nil || err != nil {
+		return c, err
+	}
+	known := make(map[string]struct{}, len(methods))
+	for _, name := range methods {
+		known[name] = struct{}{}
+	}
+	for grpcName, name := range grpcMethods {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("gRPC method %s mapped to unknown RPC method: %s", grpcName, name)
+		}
+	}
+	c.grpcMethods = grpcMethods
+	return c, nil
+}
+
+// parseMethodList parses comma separated list of method names.
+func parseMethodList(list string, known map[string]struct{}) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown RPC method in access list: %s", name)
+		}
+		set[name] = struct{}{}
+	}
+	return set, nil
+}
+
+// AuthEnabled reports whether callers are authenticated, and methods restricted.
+func (c *Control) AuthEnabled() bool {
+	return c != nil && (c.token != "" || c.jwtSecret != nil)
+}
+
+// Allow takes a token from the limiter of the IP address.
+func (c *Control) Allow(ip string) bool {
+	if c == nil {
+		return true
+	}
+	return c.limiter.Get(ip).Allow()
+}
+
+// Authenticate checks the value of the Authorization header. Callers without credentials are anonymous, but
+// callers with invalid credentials are rejected.
+func (c *Control) Authenticate(header string) (bool, error) {
+	if !c.AuthEnabled() || header == "" {
+		return false, nil
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false, ErrUnauthorized
+	}
+	if c.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) == 1 {
+		return true, nil
+	}
+	if c.jwtSecret != nil {
+		if err := verifyJWT(token, c.jwtSecret, time.Now()); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+		}
+		return true, nil
+	}
+	return false, ErrUnauthorized
+}
+
+// CheckMethod returns an error if caller is not allowed to call the method.
+func (c *Control) CheckMethod(method string, authenticated bool) error {
+	if !c.AuthEnabled() {
+		return nil
+	}
+	allowed := c.public
+	if authenticated {
+		if c.authenticated == nil {
+			return nil
+		}
+		allowed = c.authenticated
+	}
+	if _, ok := allowed[method]; !ok {
+		return fmt.Errorf("%w: %s", ErrMethodNotAllowed, method)
+	}
+	return nil
+}
+
+// UnaryServerInterceptor returns gRPC interceptor applying access control to unary calls.
+func (c *Control) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := c.checkGRPC(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns gRPC interceptor applying access control to streams. Every stream counts as a
+// single request.
+func (c *Control) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := c.checkGRPC(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// checkGRPC rejects gRPC calls exceeding rate limit, with invalid credentials or of methods the caller is not
+// allowed to call. Anonymous callers of methods that are not public are rejected as unauthenticated.
+func (c *Control) checkGRPC(ctx context.Context, fullMethod string) error {
+	if c == nil {
+		return nil
+	}
+	if p, ok := peer.FromContext(ctx); ok && !c.Allow(addrIP(p.Addr)) {
+		return status.Error(codes.ResourceExhausted, ErrRateLimited.Error())
+	}
+	if !c.AuthEnabled() {
+		return nil
+	}
+	var header string
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		header = values[0]
+	}
+	authenticated, err := c.Authenticate(header)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, ErrUnauthorized.Error())
+	}
+	method, ok := c.grpcMethods[fullMethod]
+	if !ok {
+		method = fullMethod
+	}
+	if err := c.CheckMethod(method, authenticated); err != nil {
+		if !authenticated {
+			return status.Error(codes.Unauthenticated, ErrUnauthorized.Error())
+		}
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// addrIP returns IP address of the client, without port.
+func addrIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// verifyJWT verifies the signature and time claims of a JWT signed with HS256. The expiration time is required, and
+// must be within maxJWTLifetime from now.
+func verifyJWT(token string, secret []byte, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return fmt.Errorf("invalid JWT header: %w", err)
+	}
+	if header.Alg != "HS256" {
+		return fmt.Errorf("unsupported JWT algorithm: %s", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid JWT signature: %w", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("invalid JWT signature")
+	}
+
+	var claims struct {
+		ExpiresAt *float64 `json:"exp"`
+		NotBefore *float64 `json:"nbf"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return fmt.Errorf("invalid JWT claims: %w", err)
+	}
+	unix := float64(now.Unix())
+	if claims.ExpiresAt == nil {
+		return errors.New("JWT expiration time is missing")
+	}
+	if unix >= *claims.ExpiresAt {
+		return errors.New("JWT expired")
+	}
+	if *claims.ExpiresAt > unix+maxJWTLifetime.Seconds() {
+		return fmt.Errorf("JWT expiration time is more than %s ahead", maxJWTLifetime)
+	}
+	if claims.NotBefore != nil && unix < *claims.NotBefore {
+		return errors.New("JWT not valid yet")
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,184 @@
+package access
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/rollkit/rollkit/config"
+)
+
+func TestNew(t *testing.T) {
+	c, err := New(config.RPCConfig{}, []string{"health"})
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = New(config.RPCConfig{AuthToken: "token", PublicMethods: "health,unknown"}, []string{"health"})
+	assert.ErrorContains(t, err, "unknown RPC method")
+
+	// access lists are not used without methods
+	c, err = New(config.RPCConfig{AuthToken: "token", PublicMethods: "health"}, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, c.CheckMethod("health", false), ErrMethodNotAllowed)
+	assert.NoError(t, c.CheckMethod("health", true))
+}
+
+func TestGRPCInterceptors(t *testing.T) {
+	c, err := New(config.RPCConfig{AuthToken: "token", RequestRate: 2}, nil)
+	require.NoError(t, err)
+	unary := c.UnaryServerInterceptor()
+	stream := c.StreamServerInterceptor()
+
+	call := func(ip, token string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1000}})
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	assert.NoError(t, call("10.0.0.1", "token"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("10.0.0.1", "")))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call("10.0.0.1", "token")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("10.0.0.2", "invalid")))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.3"), Port: 1000}})
+	err = stream(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// nil access control allows everything
+	var unlimited *Control
+	_, err = unlimited.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestGRPCMethodAccess(t *testing.T) {
+	methods := []string{"block", "tx", "subscribe"}
+	grpcMethods := map[string]string{
+		"/test.Service/GetBlock":  "block",
+		"/test.Service/GetTx":     "tx",
+		"/test.Service/Subscribe": "subscribe",
+	}
+
+	_, err := NewGRPC(config.RPCConfig{AuthToken: "token"}, methods, map[string]string{"/test.Service/Get": "unknown"})
+	assert.ErrorContains(t, err, "unknown RPC method")
+
+	c, err := NewGRPC(config.RPCConfig{AuthToken: "token", PublicMethods: "block,subscribe", AuthenticatedMethods: "block,tx"}, methods, grpcMethods)
+	require.NoError(t, err)
+	unary := c.UnaryServerInterceptor()
+	stream := c.StreamServerInterceptor()
+
+	call := func(method, token string) error {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	cases := []struct {
+		method string
+		token  string
+		code   codes.Code
+	}{
+		{"/test.Service/GetBlock", "", codes.OK},
+		{"/test.Service/GetBlock", "token", codes.OK},
+		{"/test.Service/GetTx", "", codes.Unauthenticated},
+		{"/test.Service/GetTx", "token", codes.OK},
+		{"/test.Service/GetBlock", "invalid", codes.Unauthenticated},
+		{"/test.Service/Unmapped", "token", codes.PermissionDenied},
+		{"/test.Service/Unmapped", "", codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.code, status.Code(call(tc.method, tc.token)), "%s with token %q", tc.method, tc.token)
+	}
+
+	subscribe := func(token string) error {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+		return stream(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Subscribe"}, func(any, grpc.ServerStream) error {
+			return nil
+		})
+	}
+	assert.NoError(t, subscribe(""))
+	assert.Equal(t, codes.PermissionDenied, status.Code(subscribe("token")))
+
+	// without access lists, unmapped methods are allowed for authenticated callers
+	c, err = NewGRPC(config.RPCConfig{AuthToken: "token"}, methods, grpcMethods)
+	require.NoError(t, err)
+	unary = c.UnaryServerInterceptor()
+	assert.NoError(t, call("/test.Service/Unmapped", "token"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call("/test.Service/GetBlock", "")))
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestVerifyJWT(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1_700_000_000, 0)
+	sign := func(claims string) string {
+		enc := base64.RawURLEncoding
+		unsigned := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(unsigned))
+		return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+	}
+	exp := func(d time.Duration) string {
+		return `{"exp":` + strconv.FormatInt(now.Add(d).Unix(), 10) + `}`
+	}
+
+	cases := []struct {
+		name   string
+		claims string
+		err    string
+	}{
+		{"valid", exp(time.Minute), ""},
+		{"longest lifetime", exp(maxJWTLifetime), ""},
+		{"missing expiration", `{}`, "expiration time is missing"},
+		{"expired", exp(-time.Second), "expired"},
+		{"expiration too far ahead", exp(maxJWTLifetime + time.Second), "more than 1h0m0s ahead"},
+		{"not valid yet", `{"exp":` + strconv.FormatInt(now.Add(time.Minute).Unix(), 10) + `,"nbf":` + strconv.FormatInt(now.Add(time.Second).Unix(), 10) + `}`, "not valid yet"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := verifyJWT(sign(c.claims), secret, now)
+			if c.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Subscriptions counts event subscriptions of RPC clients, identified by IP address, and limits the number of
+// subscribing clients and the number of subscriptions of a single client. Limits apply to clients rather than
+// connections, so that they can't be bypassed by opening more connections.
+type Subscriptions struct {
+	maxClients   int
+	maxPerClient int
+
+	mtx     sync.Mutex
+	clients map[string]int
+}
+
+// NewSubscriptions returns subscription counter limiting the number of subscribing clients to maxClients, and the
+// number of subscriptions of a single client to maxPerClient.
+func NewSubscriptions(maxClients, maxPerClient int) *Subscriptions {
+	return &Subscriptions{
+		maxClients:   maxClients,
+		maxPerClient: maxPerClient,
+		clients:      make(map[string]int),
+	}
+}
+
+// Add counts new subscription of the client, unless subscription limits are reached.
+func (s *Subscriptions) Add(ip string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	n := s.clients[ip]
+	if n == 0 && len(s.clients) >= s.maxClients {
+		return fmt.Errorf("max_subscription_clients %d reached", s.maxClients)
+	}
+	if n >= s.maxPerClient {
+		return fmt.Errorf("max_subscriptions_per_client %d reached", s.maxPerClient)
+	}
+	s.clients[ip] = n + 1
+	return nil
+}
+
+// Release uncounts n subscriptions of the client.
+func (s *Subscriptions) Release(ip string, n int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.clients[ip] > n {
+		s.clients[ip] -= n
+	} else {
+		delete(s.clients, ip)
+	}
+}
+
+// StreamServerInterceptor returns gRPC interceptor counting streams of given methods as subscriptions of the caller
+// while they are open. Streams exceeding subscription limits are rejected.
+func (s *Subscriptions) StreamServerInterceptor(methods ...string) grpc.StreamServerInterceptor {
+	subscribe := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		subscribe[method] = struct{}{}
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := subscribe[info.FullMethod]; !ok {
+			return handler(srv, ss)
+		}
+		ip := streamIP(ss.Context())
+		if err := s.Add(ip); err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		defer s.Release(ip, 1)
+		return handler(srv, ss)
+	}
+}
+
+// streamIP returns IP address of the gRPC caller, or an empty string if it's unknown.
+func streamIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return addrIP(p.Addr)
+}
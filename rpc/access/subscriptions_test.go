@@ -0,0 +1,60 @@
+package access
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestSubscriptions(t *testing.T) {
+	s := NewSubscriptions(1, 2)
+
+	require.NoError(t, s.Add("10.0.0.1"))
+	require.NoError(t, s.Add("10.0.0.1"))
+	assert.ErrorContains(t, s.Add("10.0.0.1"), "max_subscriptions_per_client 2 reached")
+	assert.ErrorContains(t, s.Add("10.0.0.2"), "max_subscription_clients 1 reached")
+
+	s.Release("10.0.0.1", 1)
+	assert.NoError(t, s.Add("10.0.0.1"))
+	s.Release("10.0.0.1", 5)
+	assert.NoError(t, s.Add("10.0.0.2"))
+}
+
+func TestSubscriptionsStreamServerInterceptor(t *testing.T) {
+	const subscribe = "/test.Service/Subscribe"
+	interceptor := NewSubscriptions(1, 1).StreamServerInterceptor(subscribe)
+	stream := func(ip, method string, handler grpc.StreamHandler) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1000}})
+		return interceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method}, handler)
+	}
+	noop := func(any, grpc.ServerStream) error { return nil }
+
+	// open subscription stream counts towards limits of its caller and all callers
+	opened, done := make(chan struct{}), make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- stream("10.0.0.1", subscribe, func(any, grpc.ServerStream) error {
+			close(opened)
+			<-done
+			return nil
+		})
+	}()
+	<-opened
+	assert.Equal(t, codes.ResourceExhausted, status.Code(stream("10.0.0.1", subscribe, noop)))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(stream("10.0.0.2", subscribe, noop)))
+
+	// other streams are not counted
+	assert.NoError(t, stream("10.0.0.1", "/test.Service/Other", noop))
+
+	// subscription is released once the stream ends
+	close(done)
+	require.NoError(t, <-errs)
+	assert.NoError(t, stream("10.0.0.2", subscribe, noop))
+}
@@ -0,0 +1,45 @@
+package json
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// JSON-RPC error codes of calls rejected by access control, from the range reserved for implementation-defined server
+// errors (as in EIP-1474).
+const (
+	errCodeMethodNotAllowed json2.ErrorCode = -32004
+	errCodeRateLimited      json2.ErrorCode = -32005
+	errCodeUnauthorized     json2.ErrorCode = -32006
+)
+
+// authenticatedKey is the context key marking requests of authenticated callers.
+type authenticatedKey struct{}
+
+// isAuthenticated reports whether request was authenticated by the handler.
+func isAuthenticated(r *http.Request) bool {
+	authenticated, _ := r.Context().Value(authenticatedKey{}).(bool)
+	return authenticated
+}
+
+// withAuthenticated marks request as authenticated.
+func withAuthenticated(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), authenticatedKey{}, true))
+}
+
+// remoteIP returns IP address of the request's client, without port.
+func remoteIP(r *http.Request) string {
+	return addrIP(r.RemoteAddr)
+}
+
+// addrIP returns IP address of the remote address, without port.
+func addrIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,166 @@
+package json
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	cmconfig "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/rpc/access"
+	"github.com/rollkit/rollkit/test/mocks"
+)
+
+func TestAccessControl(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "jwt.secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("jwt-secret\n"), 0600))
+
+	client := &mocks.Client{}
+	client.On("Health", mock.Anything).Return(&coretypes.ResultHealth{}, nil)
+	client.On("NumUnconfirmedTxs", mock.Anything).Return(&coretypes.ResultUnconfirmedTxs{}, nil)
+	handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{
+		AuthToken:            "token",
+		JWTSecretFile:        secretFile,
+		PublicMethods:        "health",
+		AuthenticatedMethods: "health,num_unconfirmed_txs",
+	}, log.TestingLogger())
+	require.NoError(t, err)
+
+	now := time.Now().Unix()
+	cases := []struct {
+		name       string
+		method     string
+		token      string
+		code       int
+		errMessage string
+	}{
+		{"public", "health", "", http.StatusOK, ""},
+		{"anonymous", "num_unconfirmed_txs", "", http.StatusOK, access.ErrMethodNotAllowed.Error()},
+		{"token", "num_unconfirmed_txs", "token", http.StatusOK, ""},
+		{"not allowed", "abci_info", "token", http.StatusOK, access.ErrMethodNotAllowed.Error()},
+		{"invalid token", "health", "invalid", http.StatusUnauthorized, ""},
+		{"jwt", "num_unconfirmed_txs", signJWT(`{"alg":"HS256","typ":"JWT"}`, `{"exp":`+strconv.FormatInt(now+60, 10)+`}`, "jwt-secret"), http.StatusOK, ""},
+		{"jwt without expiration", "health", signJWT(`{"alg":"HS256","typ":"JWT"}`, `{}`, "jwt-secret"), http.StatusUnauthorized, ""},
+		{"expired jwt", "health", signJWT(`{"alg":"HS256","typ":"JWT"}`, `{"exp":`+strconv.FormatInt(now-60, 10)+`}`, "jwt-secret"), http.StatusUnauthorized, ""},
+		{"jwt with other secret", "health", signJWT(`{"alg":"HS256","typ":"JWT"}`, `{}`, "other-secret"), http.StatusUnauthorized, ""},
+		{"unsigned jwt", "health", signJWT(`{"alg":"none"}`, `{}`, "jwt-secret"), http.StatusUnauthorized, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jsonReq, err := json2.EncodeClientRequest(c.method, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(jsonReq))
+			if c.token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			require.Equal(t, c.code, resp.Code)
+			var result interface{}
+			err = json2.DecodeClientResponse(resp.Body, &result)
+			if c.code == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", resp.Header().Get("WWW-Authenticate"))
+				assert.ErrorContains(t, err, access.ErrUnauthorized.Error())
+				var rpcErr *json2.Error
+				require.ErrorAs(t, err, &rpcErr)
+				assert.Equal(t, errCodeUnauthorized, rpcErr.Code)
+				return
+			}
+			if c.errMessage == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, c.errMessage)
+				var rpcErr *json2.Error
+				require.ErrorAs(t, err, &rpcErr)
+				assert.Equal(t, errCodeMethodNotAllowed, rpcErr.Code)
+			}
+		})
+	}
+
+	t.Run("URI", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/num_unconfirmed_txs", nil))
+		assert.Contains(t, resp.Body.String(), access.ErrMethodNotAllowed.Error())
+
+		req := httptest.NewRequest(http.MethodGet, "/num_unconfirmed_txs", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		resp = httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		assert.NotContains(t, resp.Body.String(), "error")
+	})
+}
+
+func TestAccessControlConfig(t *testing.T) {
+	client := &mocks.Client{}
+	_, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{AuthToken: "token", PublicMethods: "health,unknown"}, log.TestingLogger())
+	assert.ErrorContains(t, err, "unknown RPC method")
+
+	_, err = GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{JWTSecretFile: filepath.Join(t.TempDir(), "missing")}, log.TestingLogger())
+	assert.ErrorContains(t, err, "JWT secret file")
+}
+
+func TestRateLimit(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("Health", mock.Anything).Return(&coretypes.ResultHealth{}, nil)
+	handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{RequestRate: 2}, log.TestingLogger())
+	require.NoError(t, err)
+
+	request := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.RemoteAddr = remoteAddr
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1000"))
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1001"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:1002"))
+	// rejected request gets the same JSON-RPC error as rejected calls in a batch
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.RemoteAddr = "10.0.0.1:1003"
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"request rate limit exceeded","data":null},"id":null}`, resp.Body.String())
+	// limits are separate for every IP address
+	assert.Equal(t, http.StatusOK, request("10.0.0.2:1000"))
+
+	time.Sleep(600 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1000"))
+
+	// every call in a batch is a separate request
+	body := `[{"jsonrpc":"2.0","id":1,"method":"health"},{"jsonrpc":"2.0","id":2,"method":"health"},{"jsonrpc":"2.0","id":3,"method":"health"}]`
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.RemoteAddr = "10.0.0.3:1000"
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[{"jsonrpc":"2.0","result":{},"id":1},{"jsonrpc":"2.0","result":{},"id":2},{"jsonrpc":"2.0","error":{"code":-32005,"message":"request rate limit exceeded","data":null},"id":3}]`, resp.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.3:1001"))
+}
+
+func signJWT(header, claims, secret string) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
@@ -1,58 +1,196 @@
 package json
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 
-	"github.com/cometbft/cometbft/libs/bytes"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmjson "github.com/cometbft/cometbft/libs/json"
 
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/rollkit/rollkit/rpc/access"
 	"github.com/rollkit/rollkit/third_party/log"
 )
 
+var (
+	errRequestTooLarge  = errors.New("request body too large")
+	errResponseTooLarge = errors.New("response size limit exceeded")
+	errEmptyBatch       = errors.New("empty batch")
+	errBatchTooLarge    = errors.New("too many calls in batch")
+)
+
 type handler struct {
 	srv    *service
 	mux    *http.ServeMux
 	codec  rpc.Codec
+	access *access.Control
 	logger log.Logger
+
+	// maxResponseBytes limits size of results of single calls and of batch responses. 0 means unlimited.
+	maxResponseBytes uint64
 }
 
-func newHandler(s *service, codec rpc.Codec, logger log.Logger) *handler {
+func newHandler(s *service, codec rpc.Codec, ac *access.Control, maxResponseBytes uint64, logger log.Logger) *handler {
 	mux := http.NewServeMux()
 	h := &handler{
-		srv:    s,
-		mux:    mux,
-		codec:  codec,
-		logger: logger,
+		srv:              s,
+		mux:              mux,
+		codec:            codec,
+		access:           ac,
+		logger:           logger,
+		maxResponseBytes: maxResponseBytes,
 	}
 
 	mux.HandleFunc("/", h.serveJSONRPC)
 	mux.HandleFunc("/websocket", h.wsHandler)
 	for name, method := range s.methods {
 		logger.Debug("registering method", "name", name)
-		mux.HandleFunc("/"+name, h.newHandler(method))
+		mux.HandleFunc("/"+name, h.newHandler(name, method))
 	}
 
 	return h
 }
+
+// ServeHTTP rejects requests exceeding rate limit or with invalid credentials, and dispatches remaining requests.
+// Rejected requests get JSON-RPC error response, like calls rejected later, with HTTP 429 or 401 status.
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.access.Allow(remoteIP(r)) {
+		h.writeError(w, http.StatusTooManyRequests, errCodeRateLimited, access.ErrRateLimited)
+		return
+	}
+	authenticated, err := h.access.Authenticate(r.Header.Get("Authorization"))
+	if err != nil {
+		h.logger.Debug("failed to authenticate RPC caller", "remote", r.RemoteAddr, "error", err)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		h.writeError(w, http.StatusUnauthorized, errCodeUnauthorized, access.ErrUnauthorized)
+		return
+	}
+	if authenticated {
+		r = withAuthenticated(r)
+	}
+	if h.srv.config.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.srv.config.MaxBodyBytes)
+	}
 	h.mux.ServeHTTP(w, r)
 }
 
-// serveJSONRPC serves HTTP request
+// serveJSONRPC serves HTTP request, containing a single call or a batch of calls.
 func (h *handler) serveJSONRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, errRequestTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		h.serveBatch(w, r, trimmed)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
 	h.serveJSONRPCforWS(w, r, nil)
 }
 
+// serveBatch serves JSON-RPC 2.0 batch request. Calls are executed in order, and responses of all calls except
+// notifications are returned in a single array. If responses exceed the response size limit, the response of the call
+// exceeding the limit is replaced by an error, as its size is known only once the call is executed, and all following
+// calls return the same error without being executed. Every call takes a token from the rate limiter (the first one
+// was taken by ServeHTTP); calls exceeding the rate limit return an error.
+func (h *handler) serveBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var calls []json.RawMessage
+	if err := json.Unmarshal(body, &calls); err != nil {
+		h.writeError(w, http.StatusOK, json2.E_PARSE, err)
+		return
+	}
+	if len(calls) == 0 {
+		h.writeError(w, http.StatusOK, json2.E_INVALID_REQ, errEmptyBatch)
+		return
+	}
+	if maxCalls := h.srv.config.MaxRequestBatchSize; maxCalls > 0 && len(calls) > maxCalls {
+		h.writeError(w, http.StatusOK, json2.E_INVALID_REQ, fmt.Errorf("%w: %d calls, limit is %d", errBatchTooLarge, len(calls), maxCalls))
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(calls))
+	size := 0
+	exceeded := false
+	for i, call := range calls {
+		var resp []byte
+		limited := !exceeded && i > 0 && !h.access.Allow(remoteIP(r))
+		if limited {
+			resp = errorResponse(call, errCodeRateLimited, access.ErrRateLimited)
+		} else if !exceeded {
+			req := r.Clone(r.Context())
+			req.Body = io.NopCloser(bytes.NewReader(call))
+			req.ContentLength = int64(len(call))
+			buf := new(bytes.Buffer)
+			h.serveJSONRPCforWS(newResponseWriter(buf), req, nil)
+			resp = bytes.TrimSpace(buf.Bytes())
+			exceeded = h.maxResponseBytes > 0 && uint64(size+len(resp)) > h.maxResponseBytes
+		}
+		if exceeded {
+			resp = errorResponse(call, json2.E_SERVER, errResponseTooLarge)
+		}
+		// notifications don't have responses
+		if len(resp) == 0 {
+			continue
+		}
+		size += len(resp)
+		responses = append(responses, resp)
+	}
+	if len(responses) == 0 {
+		return
+	}
+
+	w.Header().Set("x-content-type-options", "nosniff")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(responses); err != nil {
+		h.logger.Error("failed to encode RPC response", "error", err)
+	}
+}
+
+// errorResponse returns error response to the call, or nil if the call is a notification.
+func errorResponse(call json.RawMessage, code json2.ErrorCode, err error) []byte {
+	var req struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(call, &req) == nil && (req.ID == nil || bytes.Equal(req.ID, []byte("null"))) {
+		return nil
+	}
+	resp, _ := json.Marshal(response{
+		Version: "2.0",
+		Error:   &json2.Error{Code: code, Message: err.Error()},
+		ID:      req.ID,
+	})
+	return resp
+}
+
+// writeError writes error response with given HTTP status, not related to any specific call.
+func (h *handler) writeError(w http.ResponseWriter, status int, code json2.ErrorCode, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	resp := response{
+		Version: "2.0",
+		Error:   &json2.Error{Code: code, Message: err.Error()},
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode RPC response", "error", err)
+	}
+}
+
 // serveJSONRPC serves HTTP request
 // implementation is highly inspired by Gorilla RPC v2 (but simplified a lot)
 func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsConn *wsConn) {
@@ -74,7 +212,20 @@ func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsCo
 	}
 	methodSpec, ok := h.srv.methods[method]
 	if !ok {
-		codecReq.WriteError(w, int(json2.E_NO_METHOD), err)
+		codecReq.WriteError(w, int(json2.E_NO_METHOD), &json2.Error{Code: json2.E_NO_METHOD, Message: "method not found: " + method})
+		return
+	}
+	authenticated := isAuthenticated(r)
+	if wsConn != nil {
+		// every WebSocket message is a separate request
+		if !h.access.Allow(remoteIP(r)) {
+			codecReq.WriteError(w, int(errCodeRateLimited), &json2.Error{Code: errCodeRateLimited, Message: access.ErrRateLimited.Error()})
+			return
+		}
+		authenticated = wsConn.authenticated
+	}
+	if err := h.access.CheckMethod(method, authenticated); err != nil {
+		codecReq.WriteError(w, int(errCodeMethodNotAllowed), &json2.Error{Code: errCodeMethodNotAllowed, Message: err.Error()})
 		return
 	}
 
@@ -115,14 +266,22 @@ func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsCo
 			codecReq.WriteError(w, http.StatusInternalServerError, err)
 			return
 		}
+		if h.maxResponseBytes > 0 && uint64(len(raw)) > h.maxResponseBytes {
+			codecReq.WriteError(w, http.StatusInternalServerError, errResponseTooLarge)
+			return
+		}
 		codecReq.WriteResponse(w, raw)
 	} else {
 		codecReq.WriteError(w, statusCode, errResult)
 	}
 }
 
-func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http.Request) {
+func (h *handler) newHandler(name string, methodSpec *method) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.access.CheckMethod(name, isAuthenticated(r)); err != nil {
+			h.encodeAndWriteResponse(w, nil, err, int(errCodeMethodNotAllowed))
+			return
+		}
 		args := reflect.New(methodSpec.argsType)
 		values, err := url.ParseQuery(r.URL.RawQuery)
 		if err != nil {
@@ -163,10 +322,14 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 				return
 			}
 		}
-		rets := methodSpec.m.Call([]reflect.Value{
+		callArgs := []reflect.Value{
 			reflect.ValueOf(r),
 			args,
-		})
+		}
+		if methodSpec.ws {
+			callArgs = append(callArgs, reflect.ValueOf((*wsConn)(nil)))
+		}
+		rets := methodSpec.m.Call(callArgs)
 
 		// Extract the result to error if needed.
 		statusCode := http.StatusOK
@@ -197,6 +360,8 @@ func (h *handler) encodeAndWriteResponse(w http.ResponseWriter, result interface
 		bytes, err := cmjson.Marshal(result)
 		if err != nil {
 			resp.Error = &json2.Error{Code: json2.E_INTERNAL, Data: err.Error()}
+		} else if h.maxResponseBytes > 0 && uint64(len(bytes)) > h.maxResponseBytes {
+			resp.Error = &json2.Error{Code: json2.E_INTERNAL, Data: errResponseTooLarge.Error()}
 		} else {
 			resp.Result = bytes
 		}
@@ -245,12 +410,12 @@ func setPointerParam(rawVal string, args *reflect.Value, i int) error {
 			return err
 		}
 		field.Set(reflect.ValueOf(&val))
-	case reflect.TypeOf((*bytes.HexBytes)(nil)):
+	case reflect.TypeOf((*cmbytes.HexBytes)(nil)):
 		hexBytes, err := hex.DecodeString(rawVal)
 		if err != nil {
 			return err
 		}
-		hb := bytes.HexBytes(hexBytes)
+		hb := cmbytes.HexBytes(hexBytes)
 		field.Set(reflect.ValueOf(&hb))
 	default:
 		return fmt.Errorf("unsupported pointer type: %v", field.Type())
@@ -4,24 +4,57 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sync"
 	"time"
 
+	"github.com/cometbft/cometbft/config"
 	cmjson "github.com/cometbft/cometbft/libs/json"
 
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
-	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/rollkit/rollkit/block"
+	rollconf "github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/rpc/access"
+	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
 )
 
+// subscribeTimeout is the time limit for subscribing to events.
+const subscribeTimeout = 5 * time.Second
+
+// errNotWebSocket is returned by subscription methods called over plain HTTP, which can't deliver events.
+var errNotWebSocket = errors.New("subscriptions are only supported over WebSocket")
+
 // GetHTTPHandler returns handler configured to serve Tendermint-compatible RPC.
-func GetHTTPHandler(l rpcclient.Client, logger log.Logger) (http.Handler, error) {
-	return newHandler(newService(l, logger), json2.NewCodec(), logger), nil
+// Authentication, method access control and rate limiting of callers, and response size limit are configured by
+// rollkitConf.
+func GetHTTPHandler(l rpcclient.Client, conf *config.RPCConfig, rollkitConf rollconf.RPCConfig, logger log.Logger) (http.Handler, error) {
+	ac, err := access.New(rollkitConf, Methods())
+	if err != nil {
+		return nil, err
+	}
+	subs := access.NewSubscriptions(conf.MaxSubscriptionClients, conf.MaxSubscriptionsPerClient)
+	return NewHTTPHandler(l, conf, ac, subs, rollkitConf.MaxResponseBytes, logger), nil
+}
+
+// NewHTTPHandler returns handler serving Tendermint-compatible RPC with given access control and subscription
+// counter, so that they can be shared with other servers and limits of callers apply to all of them.
+func NewHTTPHandler(l rpcclient.Client, conf *config.RPCConfig, ac *access.Control, subs *access.Subscriptions, maxResponseBytes uint64, logger log.Logger) http.Handler {
+	return newHandler(newService(l, conf, subs, logger), json2.NewCodec(), ac, maxResponseBytes, logger)
+}
+
+// Methods returns names of all served RPC methods.
+func Methods() []string {
+	return newService(nil, nil, nil, nil).methodNames()
 }
 
 type method struct {
@@ -44,14 +77,24 @@ func newMethod(m interface{}) *method {
 
 type service struct {
 	client  rpcclient.Client
+	config  *config.RPCConfig
 	methods map[string]*method
 	logger  log.Logger
+
+	// subscriptions is the number of event subscriptions of every connection, identified by remote address, and
+	// clientSubscriptions counts them for every client, identified by IP address, to apply subscription limits.
+	subscriptions       map[string]int
+	clientSubscriptions *access.Subscriptions
+	subsMtx             sync.Mutex
 }
 
-func newService(c rpcclient.Client, l log.Logger) *service {
+func newService(c rpcclient.Client, conf *config.RPCConfig, subs *access.Subscriptions, l log.Logger) *service {
 	s := service{
-		client: c,
-		logger: l,
+		client:              c,
+		config:              conf,
+		logger:              l,
+		subscriptions:       make(map[string]int),
+		clientSubscriptions: subs,
 	}
 	s.methods = map[string]*method{
 		"subscribe":            newMethod(s.Subscribe),
@@ -85,38 +128,54 @@ func newService(c rpcclient.Client, l log.Logger) *service {
 		"abci_query":           newMethod(s.ABCIQuery),
 		"abci_info":            newMethod(s.ABCIInfo),
 		"broadcast_evidence":   newMethod(s.BroadcastEvidence),
+		"da_inclusion_proof":   newMethod(s.DAInclusionProof),
+		"peers":                newMethod(s.Peers),
+		"p2p_diag":             newMethod(s.P2PDiagnostics),
+		"dial_peer":            newMethod(s.DialPeer),
+		"block_peer":           newMethod(s.BlockPeer),
+		"unblock_peer":         newMethod(s.UnblockPeer),
+		"disconnect_peer":      newMethod(s.DisconnectPeer),
+
+		"rollkit_da_status":        newMethod(s.RollkitDAStatus),
+		"rollkit_sequencer_status": newMethod(s.RollkitSequencerStatus),
 	}
 	return &s
 }
 
-func (s *service) Subscribe(req *http.Request, args *subscribeArgs, wsConn *wsConn) (*ctypes.ResultSubscribe, error) {
-	// TODO(tzdybal): pass config and check subscriptions limits
-	// TODO(tzdybal): extract consts or configs
-	const SubscribeTimeout = 5 * time.Second
-	const subBufferSize = 100
+func (s *service) methodNames() []string {
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	return names
+}
 
+func (s *service) Subscribe(req *http.Request, args *subscribeArgs, wsConn *wsConn) (*ctypes.ResultSubscribe, error) {
+	if wsConn == nil {
+		return nil, errNotWebSocket
+	}
 	addr := req.RemoteAddr
 	var query string
 	if args.Query != nil {
 		query = *args.Query
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), SubscribeTimeout)
+	// subscriptions are released when the connection is closed
+	if err := s.addSubscription(addr); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), subscribeTimeout)
 	defer cancel()
 
-	sub, err := s.client.Subscribe(ctx, addr, query, subBufferSize)
+	sub, err := s.client.Subscribe(ctx, addr, query, s.config.SubscriptionBufferSize)
 	if err != nil {
+		s.removeSubscription(addr)
 		return nil, fmt.Errorf("failed to subscribe: %w", err)
 	}
 
+	codecReq := wsConn.codecReq
 	go func() {
-		var codecReq rpc.CodecRequest
-		if wsConn != nil {
-			codecReq = wsConn.codecReq
-		} else {
-			codecReq = json2.NewCodec().NewRequest(req)
-		}
-
 		for msg := range sub {
 			var raw json.RawMessage
 			raw, err = cmjson.Marshal(msg.Data)
@@ -127,18 +186,17 @@ func (s *service) Subscribe(req *http.Request, args *subscribeArgs, wsConn *wsCo
 				return
 			}
 			codecReq.WriteResponse(w, raw)
-
-			data := btz.Bytes()
-			if wsConn != nil {
-				wsConn.queue <- data
-			}
+			wsConn.sendEvent(btz.Bytes())
 		}
 	}()
 
 	return &ctypes.ResultSubscribe{}, nil
 }
 
-func (s *service) Unsubscribe(req *http.Request, args *unsubscribeArgs) (*emptyResult, error) {
+func (s *service) Unsubscribe(req *http.Request, args *unsubscribeArgs, wsConn *wsConn) (*emptyResult, error) {
+	if wsConn == nil {
+		return nil, errNotWebSocket
+	}
 	s.logger.Debug("unsubscribe from query", "remote", req.RemoteAddr, "query", args.Query)
 
 	var query string
@@ -150,25 +208,105 @@ func (s *service) Unsubscribe(req *http.Request, args *unsubscribeArgs) (*emptyR
 	if err != nil {
 		return nil, fmt.Errorf("failed to unsubscribe: %w", err)
 	}
+	s.removeSubscription(req.RemoteAddr)
 	return &emptyResult{}, nil
 }
 
-func (s *service) UnsubscribeAll(req *http.Request, args *unsubscribeAllArgs) (*emptyResult, error) {
+func (s *service) UnsubscribeAll(req *http.Request, args *unsubscribeAllArgs, wsConn *wsConn) (*emptyResult, error) {
+	if wsConn == nil {
+		return nil, errNotWebSocket
+	}
 	s.logger.Debug("unsubscribe from all queries", "remote", req.RemoteAddr)
 	err := s.client.UnsubscribeAll(context.Background(), req.RemoteAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unsubscribe all: %w", err)
 	}
+	s.removeSubscriptions(req.RemoteAddr)
 	return &emptyResult{}, nil
 }
 
+// addSubscription counts new subscription of the connection, unless subscription limits of its client are reached.
+func (s *service) addSubscription(addr string) error {
+	s.subsMtx.Lock()
+	defer s.subsMtx.Unlock()
+
+	if err := s.clientSubscriptions.Add(addrIP(addr)); err != nil {
+		return err
+	}
+	s.subscriptions[addr]++
+	return nil
+}
+
+func (s *service) removeSubscription(addr string) {
+	s.subsMtx.Lock()
+	defer s.subsMtx.Unlock()
+
+	s.releaseSubscriptions(addr, 1)
+}
+
+func (s *service) removeSubscriptions(addr string) {
+	s.subsMtx.Lock()
+	defer s.subsMtx.Unlock()
+
+	s.releaseSubscriptions(addr, s.subscriptions[addr])
+}
+
+// releaseSubscriptions uncounts up to n subscriptions of the connection. It must be called with subsMtx held.
+func (s *service) releaseSubscriptions(addr string, n int) {
+	subscribed := s.subscriptions[addr]
+	n = min(n, subscribed)
+	if n == 0 {
+		return
+	}
+	if subscribed > n {
+		s.subscriptions[addr] = subscribed - n
+	} else {
+		delete(s.subscriptions, addr)
+	}
+	s.clientSubscriptions.Release(addrIP(addr), n)
+}
+
+// unsubscribeClient cancels all subscriptions of the connection, after it was closed.
+func (s *service) unsubscribeClient(addr string) {
+	s.subsMtx.Lock()
+	_, subscribed := s.subscriptions[addr]
+	s.releaseSubscriptions(addr, s.subscriptions[addr])
+	s.subsMtx.Unlock()
+
+	if !subscribed {
+		return
+	}
+	if err := s.client.UnsubscribeAll(context.Background(), addr); err != nil {
+		s.logger.Error("failed to unsubscribe disconnected client", "remote", addr, "error", err)
+	}
+}
+
 // info API
 func (s *service) Health(req *http.Request, args *healthArgs) (*ctypes.ResultHealth, error) {
 	return s.client.Health(req.Context())
 }
 
-func (s *service) Status(req *http.Request, args *statusArgs) (*ctypes.ResultStatus, error) {
-	return s.client.Status(req.Context())
+// indexerStatusClient is implemented by clients of nodes reporting indexing progress.
+type indexerStatusClient interface {
+	IndexerStatus(ctx context.Context) (*txindex.Status, error)
+}
+
+func (s *service) Status(req *http.Request, args *statusArgs) (*statusResult, error) {
+	status, err := s.client.Status(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	result := &statusResult{
+		NodeInfo:      status.NodeInfo,
+		SyncInfo:      status.SyncInfo,
+		ValidatorInfo: status.ValidatorInfo,
+	}
+	if client, ok := s.client.(indexerStatusClient); ok {
+		if result.IndexerInfo, err = client.IndexerStatus(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (s *service) NetInfo(req *http.Request, args *netInfoArgs) (*ctypes.ResultNetInfo, error) {
@@ -236,6 +374,136 @@ func (s *service) Header(req *http.Request, args *headerArgs) (*ctypes.ResultHea
 	return s.client.Header(req.Context(), height)
 }
 
+// daInclusionProofClient is implemented by clients of nodes storing DA inclusion proofs.
+type daInclusionProofClient interface {
+	DAInclusionProof(ctx context.Context, height *int64) (*types.DAInclusionProof, error)
+}
+
+func (s *service) DAInclusionProof(req *http.Request, args *daInclusionProofArgs) (*types.DAInclusionProof, error) {
+	client, ok := s.client.(daInclusionProofClient)
+	if !ok {
+		return nil, errors.New("DA inclusion proofs are not supported by this node")
+	}
+	var height *int64
+	if args.Height != nil {
+		h := int64(*args.Height)
+		height = &h
+	}
+	return client.DAInclusionProof(req.Context(), height)
+}
+
+// rollkitStatusClient is implemented by clients of nodes reporting status of Rollkit internals.
+type rollkitStatusClient interface {
+	DAStatus(ctx context.Context) (*block.DAStatus, error)
+	SequencerStatus(ctx context.Context) (*block.SequencerStatus, error)
+}
+
+func (s *service) rollkitStatusClient() (rollkitStatusClient, error) {
+	client, ok := s.client.(rollkitStatusClient)
+	if !ok {
+		return nil, errors.New("rollkit status is not supported by this node")
+	}
+	return client, nil
+}
+
+func (s *service) RollkitDAStatus(req *http.Request, args *rollkitDAStatusArgs) (*block.DAStatus, error) {
+	client, err := s.rollkitStatusClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.DAStatus(req.Context())
+}
+
+func (s *service) RollkitSequencerStatus(req *http.Request, args *rollkitSequencerStatusArgs) (*block.SequencerStatus, error) {
+	client, err := s.rollkitStatusClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.SequencerStatus(req.Context())
+}
+
+// peerManagementClient is implemented by clients of nodes supporting runtime peer management.
+type peerManagementClient interface {
+	Peers(ctx context.Context) ([]p2p.PeerInfo, error)
+	P2PDiagnostics(ctx context.Context) (*p2p.Diagnostics, error)
+	DialPeer(ctx context.Context, addr string) error
+	BlockPeer(ctx context.Context, id string) error
+	UnblockPeer(ctx context.Context, id string) error
+	DisconnectPeer(ctx context.Context, id string) error
+}
+
+func (s *service) peerManagementClient() (peerManagementClient, error) {
+	client, ok := s.client.(peerManagementClient)
+	if !ok {
+		return nil, errors.New("peer management is not supported by this node")
+	}
+	return client, nil
+}
+
+func (s *service) Peers(req *http.Request, args *peersArgs) (*peersResult, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	peers, err := client.Peers(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &peersResult{Peers: peers}, nil
+}
+
+func (s *service) P2PDiagnostics(req *http.Request, args *p2pDiagArgs) (*p2p.Diagnostics, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.P2PDiagnostics(req.Context())
+}
+
+func (s *service) DialPeer(req *http.Request, args *dialPeerArgs) (*emptyResult, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.DialPeer(req.Context(), args.Address); err != nil {
+		return nil, fmt.Errorf("failed to dial peer: %w", err)
+	}
+	return &emptyResult{}, nil
+}
+
+func (s *service) BlockPeer(req *http.Request, args *peerArgs) (*emptyResult, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.BlockPeer(req.Context(), args.ID); err != nil {
+		return nil, fmt.Errorf("failed to block peer: %w", err)
+	}
+	return &emptyResult{}, nil
+}
+
+func (s *service) UnblockPeer(req *http.Request, args *peerArgs) (*emptyResult, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.UnblockPeer(req.Context(), args.ID); err != nil {
+		return nil, fmt.Errorf("failed to unblock peer: %w", err)
+	}
+	return &emptyResult{}, nil
+}
+
+func (s *service) DisconnectPeer(req *http.Request, args *peerArgs) (*emptyResult, error) {
+	client, err := s.peerManagementClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.DisconnectPeer(req.Context(), args.ID); err != nil {
+		return nil, fmt.Errorf("failed to disconnect peer: %w", err)
+	}
+	return &emptyResult{}, nil
+}
+
 func (s *service) HeaderByHash(req *http.Request, args *headerByHashArgs) (*ctypes.ResultHeader, error) {
 	return s.client.HeaderByHash(req.Context(), args.Hash)
 }
@@ -264,7 +532,24 @@ func (s *service) TxSearch(req *http.Request, args *txSearchArgs) (*ctypes.Resul
 		orderBy = *args.OrderBy
 	}
 
-	return s.client.TxSearch(req.Context(), args.Query, args.Prove, page, perPage, orderBy)
+	if args.AfterHeight == nil {
+		if args.AfterIndex != nil {
+			return nil, errors.New("after_index requires after_height")
+		}
+		return s.client.TxSearch(req.Context(), args.Query, args.Prove, page, perPage, orderBy)
+	}
+	client, err := s.searchCursorClient()
+	if err != nil {
+		return nil, err
+	}
+	after := indexer.Position{Height: int64(*args.AfterHeight)}
+	if args.AfterIndex != nil {
+		if *args.AfterIndex < 0 {
+			return nil, errors.New("after_index must not be negative")
+		}
+		after.Index = uint32(*args.AfterIndex) //nolint:gosec
+	}
+	return client.TxSearchAfter(req.Context(), args.Query, args.Prove, after, page, perPage, orderBy)
 }
 
 func (s *service) BlockSearch(req *http.Request, args *blockSearchArgs) (*ctypes.ResultBlockSearch, error) {
@@ -283,7 +568,28 @@ func (s *service) BlockSearch(req *http.Request, args *blockSearchArgs) (*ctypes
 		orderBy = *args.OrderBy
 	}
 
-	return s.client.BlockSearch(req.Context(), args.Query, page, perPage, orderBy)
+	if args.AfterHeight == nil {
+		return s.client.BlockSearch(req.Context(), args.Query, page, perPage, orderBy)
+	}
+	client, err := s.searchCursorClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.BlockSearchAfter(req.Context(), args.Query, int64(*args.AfterHeight), page, perPage, orderBy)
+}
+
+// searchCursorClient is implemented by clients of nodes paginating search results with a cursor.
+type searchCursorClient interface {
+	TxSearchAfter(ctx context.Context, query string, prove bool, after indexer.Position, page, perPage *int, orderBy string) (*ctypes.ResultTxSearch, error)
+	BlockSearchAfter(ctx context.Context, query string, afterHeight int64, page, perPage *int, orderBy string) (*ctypes.ResultBlockSearch, error)
+}
+
+func (s *service) searchCursorClient() (searchCursorClient, error) {
+	client, ok := s.client.(searchCursorClient)
+	if !ok {
+		return nil, errors.New("search cursors are not supported by this node")
+	}
+	return client, nil
 }
 
 func (s *service) Validators(req *http.Request, args *validatorsArgs) (*ctypes.ResultValidators, error) {
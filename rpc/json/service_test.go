@@ -15,11 +15,14 @@ import (
 	"testing"
 	"time"
 
+	cmconfig "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/crypto/ed25519"
 	"github.com/cometbft/cometbft/p2p"
 	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/node"
 	"github.com/rollkit/rollkit/test/mocks"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +30,7 @@ import (
 
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/gorilla/rpc/v2/json2"
+	"github.com/gorilla/websocket"
 )
 
 func TestHandlerMapping(t *testing.T) {
@@ -34,7 +38,7 @@ func TestHandlerMapping(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t, "TestHandlerMapping")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
 
 	jsonReq, err := json2.EncodeClientRequest("health", &healthArgs{})
@@ -85,7 +89,7 @@ func TestREST(t *testing.T) {
 	}
 
 	_, local := getRPC(t, "TestREST")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
 
 	// wait for blocks
@@ -129,7 +133,7 @@ func TestEmptyRequest(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t, "TestEmptyRequest")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -144,7 +148,7 @@ func TestStringyRequest(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t, "TestStringyRequest")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
 
 	// `starport chain faucet ...` generates broken JSON (ints are "quoted" as strings)
@@ -160,6 +164,138 @@ func TestStringyRequest(t *testing.T) {
 	assert.Equal(respJSON, resp.Body.String())
 }
 
+func TestP2PDiagRequiresUnsafeRPC(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, local := getRPC(t, "TestP2PDiagRequiresUnsafeRPC")
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
+	require.NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"p2p_diag","params":{}}`))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(http.StatusOK, resp.Code)
+	assert.Contains(resp.Body.String(), `"error"`)
+	assert.Contains(resp.Body.String(), node.ErrUnsafeRPCDisabled.Error())
+	assert.NotContains(resp.Body.String(), `"result"`)
+}
+
+func TestBatchRequest(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("Health", mock.Anything).Return(&coretypes.ResultHealth{}, nil)
+	client.On("NumUnconfirmedTxs", mock.Anything).Return(&coretypes.ResultUnconfirmedTxs{Count: 1, Total: 2}, nil)
+	conf := cmconfig.DefaultRPCConfig()
+	conf.MaxRequestBatchSize = 3
+	conf.MaxBodyBytes = 200
+
+	cases := []struct {
+		name             string
+		body             string
+		maxResponseBytes uint64
+		code             int
+		expectedResp     string
+	}{
+		{
+			"calls and notification",
+			`[{"jsonrpc":"2.0","id":1,"method":"health"},{"jsonrpc":"2.0","method":"health"},{"jsonrpc":"2.0","id":"a","method":"num_unconfirmed_txs"}]`,
+			0, http.StatusOK,
+			`[{"jsonrpc":"2.0","result":{},"id":1},{"jsonrpc":"2.0","result":{"n_txs":"1","total":"2","total_bytes":"0","txs":null},"id":"a"}]`,
+		},
+		{
+			"unknown method",
+			`[{"jsonrpc":"2.0","id":1,"method":"health"},{"jsonrpc":"2.0","id":2,"method":"unknown"}]`,
+			0, http.StatusOK,
+			`[{"jsonrpc":"2.0","result":{},"id":1},{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found: unknown","data":null},"id":2}]`,
+		},
+		{
+			"only notifications",
+			`[{"jsonrpc":"2.0","method":"health"}]`,
+			0, http.StatusOK, ``,
+		},
+		{
+			"response size limit",
+			`[{"jsonrpc":"2.0","id":1,"method":"health"},{"jsonrpc":"2.0","id":2,"method":"num_unconfirmed_txs"},{"jsonrpc":"2.0","id":3,"method":"health"}]`,
+			50, http.StatusOK,
+			`[{"jsonrpc":"2.0","result":{},"id":1},{"jsonrpc":"2.0","error":{"code":-32000,"message":"response size limit exceeded","data":null},"id":2},{"jsonrpc":"2.0","error":{"code":-32000,"message":"response size limit exceeded","data":null},"id":3}]`,
+		},
+		{
+			"empty batch",
+			`[]`,
+			0, http.StatusOK,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch","data":null},"id":null}`,
+		},
+		{
+			"too many calls",
+			`[{"method":"health"},{"method":"health"},{"method":"health"},{"method":"health"}]`,
+			0, http.StatusOK,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"too many calls in batch: 4 calls, limit is 3","data":null},"id":null}`,
+		},
+		{
+			"body too large",
+			`[` + strings.Repeat(`{"jsonrpc":"2.0","id":1,"method":"health"},`, 5) + `{}]`,
+			0, http.StatusRequestEntityTooLarge, ``,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			handler, err := GetHTTPHandler(client, conf, config.RPCConfig{MaxResponseBytes: c.maxResponseBytes}, log.TestingLogger())
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			require.Equal(t, c.code, resp.Code)
+			if c.code != http.StatusOK {
+				return
+			}
+			if c.expectedResp == "" {
+				assert.Empty(t, resp.Body.String())
+				return
+			}
+			assert.JSONEq(t, c.expectedResp, resp.Body.String())
+		})
+	}
+}
+
+func TestBatchResponseSizeLimit(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("Health", mock.Anything).Return(&coretypes.ResultHealth{}, nil)
+	client.On("NumUnconfirmedTxs", mock.Anything).Return(&coretypes.ResultUnconfirmedTxs{Count: 1, Total: 2}, nil)
+	handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{MaxResponseBytes: 50}, log.TestingLogger())
+	require.NoError(t, err)
+
+	body := `[{"jsonrpc":"2.0","id":1,"method":"health"},{"jsonrpc":"2.0","id":2,"method":"num_unconfirmed_txs"},{"jsonrpc":"2.0","id":3,"method":"health"}]`
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	// call exceeding the limit is executed, but calls following it are not
+	client.AssertNumberOfCalls(t, "NumUnconfirmedTxs", 1)
+	client.AssertNumberOfCalls(t, "Health", 1)
+}
+
+func TestResponseSizeLimit(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("NumUnconfirmedTxs", mock.Anything).Return(&coretypes.ResultUnconfirmedTxs{Count: 1, Total: 2}, nil)
+	handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{MaxResponseBytes: 10}, log.TestingLogger())
+	require.NoError(t, err)
+
+	jsonReq, err := json2.EncodeClientRequest("num_unconfirmed_txs", nil)
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(jsonReq)))
+	var result interface{}
+	assert.ErrorContains(t, json2.DecodeClientResponse(resp.Body, &result), errResponseTooLarge.Error())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/num_unconfirmed_txs", nil))
+	assert.Contains(t, resp.Body.String(), errResponseTooLarge.Error())
+}
+
 func TestSubscription(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -198,88 +334,109 @@ func TestSubscription(t *testing.T) {
 	require.NotEmpty(unsubscribeAllReq)
 
 	_, local := getRPC(t, "TestSubscription")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
 
-	var (
-		jsonResp response
-	)
+	conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(srv.URL, "http://", "ws://", 1)+"/websocket", nil)
+	require.NoError(err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	call := func(req []byte) *json2.Error {
+		require.NoError(conn.WriteMessage(websocket.TextMessage, req))
+		require.NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, msg, err := conn.ReadMessage()
+		require.NoError(err)
+		jsonResp := response{}
+		require.NoError(json.Unmarshal(msg, &jsonResp))
+		return jsonResp.Error
+	}
 
 	// test valid subscription
-	req := httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(subscribeReq))
-	resp := httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	assert.Nil(jsonResp.Error)
+	assert.Nil(call(subscribeReq))
 
 	// test valid subscription with second query
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(subscribeReq2))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	assert.Nil(jsonResp.Error)
+	assert.Nil(call(subscribeReq2))
 
 	// test subscription with invalid query
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(invalidSubscribeReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	require.NotNil(jsonResp.Error)
-	assert.Contains(jsonResp.Error.Message, "failed to parse query")
+	jsonErr := call(invalidSubscribeReq)
+	require.NotNil(jsonErr)
+	assert.Contains(jsonErr.Message, "failed to parse query")
 
 	// test valid, but duplicate subscription
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(subscribeReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	require.NotNil(jsonResp.Error)
-	assert.Contains(jsonResp.Error.Message, "already subscribed")
+	jsonErr = call(subscribeReq)
+	require.NotNil(jsonErr)
+	assert.Contains(jsonErr.Message, "already subscribed")
 
 	// test unsubscribing
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(unsubscribeReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	assert.Nil(jsonResp.Error)
+	assert.Nil(call(unsubscribeReq))
 
 	// test unsubscribing again
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(unsubscribeReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	require.NotNil(jsonResp.Error)
-	assert.Contains(jsonResp.Error.Message, "subscription not found")
+	jsonErr = call(unsubscribeReq)
+	require.NotNil(jsonErr)
+	assert.Contains(jsonErr.Message, "subscription not found")
 
 	// test unsubscribe all
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(unsubscribeAllReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	assert.Nil(jsonResp.Error)
+	assert.Nil(call(unsubscribeAllReq))
 
 	// test unsubscribing all again
-	req = httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(unsubscribeAllReq))
-	resp = httptest.NewRecorder()
-	handler.ServeHTTP(resp, req)
-	assert.Equal(http.StatusOK, resp.Code)
-	jsonResp = response{}
-	assert.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
-	require.NotNil(jsonResp.Error)
-	assert.Contains(jsonResp.Error.Message, "subscription not found")
+	jsonErr = call(unsubscribeAllReq)
+	require.NotNil(jsonErr)
+	assert.Contains(jsonErr.Message, "subscription not found")
+}
+
+func TestSubscriptionOverHTTP(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client := &mocks.Client{}
+	handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
+	require.NoError(err)
+
+	query := "tm.event='NewBlock'"
+	calls := make([]json.RawMessage, 0, 3)
+	for _, c := range []struct {
+		method string
+		args   interface{}
+	}{
+		{"subscribe", &subscribeArgs{Query: &query}},
+		{"unsubscribe", &unsubscribeArgs{Query: &query}},
+		{"unsubscribe_all", &unsubscribeAllArgs{}},
+	} {
+		req, err := json2.EncodeClientRequest(c.method, c.args)
+		require.NoError(err)
+		calls = append(calls, req)
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(req)))
+		jsonResp := response{}
+		require.NoError(json.Unmarshal(resp.Body.Bytes(), &jsonResp))
+		require.NotNil(jsonResp.Error, c.method)
+		assert.Contains(jsonResp.Error.Message, errNotWebSocket.Error(), c.method)
+
+		resp = httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/"+c.method+"?query=%22tm.event%3D%27NewBlock%27%22", nil))
+		assert.Contains(resp.Body.String(), errNotWebSocket.Error(), c.method)
+	}
+
+	// calls in batches are rejected as well
+	batch, err := json.Marshal(calls)
+	require.NoError(err)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(batch)))
+	var responses []response
+	require.NoError(json.Unmarshal(resp.Body.Bytes(), &responses))
+	require.Len(responses, 3)
+	for _, r := range responses {
+		require.NotNil(r.Error)
+		assert.Contains(r.Error.Message, errNotWebSocket.Error())
+	}
+	client.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "Unsubscribe", mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "UnsubscribeAll", mock.Anything, mock.Anything)
 }
 
 func TestRESTSerialization(t *testing.T) {
@@ -392,7 +549,7 @@ func TestRESTSerialization(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &mocks.Client{}
 			client.On(tt.name, mock.Anything).Return(tt.mockResp, tt.mockError)
-			handler, err := GetHTTPHandler(client, log.TestingLogger())
+			handler, err := GetHTTPHandler(client, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 			require.NoError(t, err)
 			require.NotNil(t, handler)
 
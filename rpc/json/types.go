@@ -7,8 +7,13 @@ import (
 	"strings"
 
 	"github.com/cometbft/cometbft/libs/bytes"
+	cmp2p "github.com/cometbft/cometbft/p2p"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cometbft/cometbft/types"
 	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/state/txindex"
 )
 
 type subscribeArgs struct {
@@ -26,6 +31,15 @@ type healthArgs struct {
 }
 type statusArgs struct {
 }
+
+// statusResult extends coretypes.ResultStatus with status of Rollkit-specific components.
+type statusResult struct {
+	NodeInfo      cmp2p.DefaultNodeInfo   `json:"node_info"`
+	SyncInfo      coretypes.SyncInfo      `json:"sync_info"`
+	ValidatorInfo coretypes.ValidatorInfo `json:"validator_info"`
+	IndexerInfo   *txindex.Status         `json:"indexer_info,omitempty"`
+}
+
 type netInfoArgs struct {
 }
 type blockchainInfoArgs struct {
@@ -59,6 +73,10 @@ type headerArgs struct {
 	Height *StrInt64 `json:"height"`
 }
 
+type daInclusionProofArgs struct {
+	Height *StrInt64 `json:"height"`
+}
+
 type headerByHashArgs struct {
 	Hash []byte `json:"hash"`
 }
@@ -78,6 +96,9 @@ type txSearchArgs struct {
 	Page    *StrInt `json:"page"`
 	PerPage *StrInt `json:"per_page"`
 	OrderBy *string `json:"order_by"`
+	// AfterHeight and AfterIndex are an optional cursor: position of the last transaction of the previous page.
+	AfterHeight *StrInt64 `json:"after_height"`
+	AfterIndex  *StrInt   `json:"after_index"`
 }
 
 type blockSearchArgs struct {
@@ -85,6 +106,8 @@ type blockSearchArgs struct {
 	Page    *StrInt `json:"page"`
 	PerPage *StrInt `json:"per_page"`
 	OrderBy *string `json:"order_by"`
+	// AfterHeight is an optional cursor: height of the last block of the previous page.
+	AfterHeight *StrInt64 `json:"after_height"`
 }
 
 type validatorsArgs struct {
@@ -140,6 +163,27 @@ type broadcastEvidenceArgs struct {
 
 type emptyResult struct{}
 
+// peer management API
+type peersArgs struct{}
+
+type peersResult struct {
+	Peers []p2p.PeerInfo `json:"peers"`
+}
+
+type p2pDiagArgs struct{}
+
+type rollkitDAStatusArgs struct{}
+
+type rollkitSequencerStatusArgs struct{}
+
+type dialPeerArgs struct {
+	Address string `json:"address"`
+}
+
+type peerArgs struct {
+	ID string `json:"id"`
+}
+
 // JSON-deserialization specific types
 
 // StrInt is an proper int or quoted "int"
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -12,30 +14,106 @@ import (
 	"github.com/rollkit/rollkit/third_party/log"
 )
 
+const (
+	// wsWriteWait is the time limit for writing a message to the client.
+	wsWriteWait = 10 * time.Second
+	// wsReadWait is the time limit for receiving a message or a pong from the client.
+	wsReadWait = 30 * time.Second
+	// wsPingPeriod is the interval of pinging the client. It must be shorter than wsReadWait.
+	wsPingPeriod = (wsReadWait * 9) / 10
+)
+
 type wsConn struct {
 	conn     *websocket.Conn
 	codecReq rpc.CodecRequest
 	queue    chan []byte
 	logger   log.Logger
+
+	// closeOnSlowClient causes the connection to be closed if the client can't receive events fast enough.
+	// Otherwise, events that don't fit in the queue are dropped.
+	closeOnSlowClient bool
+
+	// authenticated is set if the client was authenticated when connection was upgraded to WebSocket.
+	authenticated bool
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-func (wsc *wsConn) sendLoop() {
-	for msg := range wsc.queue {
-		writer, err := wsc.conn.NextWriter(websocket.TextMessage)
-		if err != nil {
-			wsc.logger.Error("failed to create writer", "error", err)
-			continue
+// send queues the message for the client, waiting for space in the queue if needed.
+// It returns false if the connection is closed.
+func (wsc *wsConn) send(msg []byte) bool {
+	select {
+	case wsc.queue <- msg:
+		return true
+	case <-wsc.done:
+		return false
+	}
+}
+
+// sendEvent queues the event for the client without waiting, so that a slow client doesn't block delivery of
+// events to other clients. If the queue is full, the event is dropped or the connection is closed.
+func (wsc *wsConn) sendEvent(msg []byte) {
+	select {
+	case wsc.queue <- msg:
+	case <-wsc.done:
+	default:
+		if wsc.closeOnSlowClient {
+			wsc.logger.Error("closing connection to slow WebSocket client", "remote", wsc.conn.RemoteAddr())
+			wsc.close()
+			return
 		}
-		_, err = writer.Write(msg)
-		if err != nil {
-			wsc.logger.Error("failed to write message", "error", err)
+		wsc.logger.Error("dropping event for slow WebSocket client", "remote", wsc.conn.RemoteAddr())
+	}
+}
+
+func (wsc *wsConn) close() {
+	wsc.closeOnce.Do(func() {
+		close(wsc.done)
+		if err := wsc.conn.Close(); err != nil {
+			wsc.logger.Error("failed to close WebSocket connection", "error", err)
 		}
-		if err = writer.Close(); err != nil {
-			wsc.logger.Error("failed to close writer", "error", err)
+	})
+}
+
+func (wsc *wsConn) sendLoop() {
+	pingTicker := time.NewTicker(wsPingPeriod)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-wsc.done:
+			return
+		case <-pingTicker.C:
+			if err := wsc.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				wsc.logger.Error("failed to send ping", "error", err)
+				wsc.close()
+				return
+			}
+		case msg := <-wsc.queue:
+			if err := wsc.write(msg); err != nil {
+				wsc.logger.Error("failed to write message", "error", err)
+				wsc.close()
+				return
+			}
 		}
 	}
 }
 
+func (wsc *wsConn) write(msg []byte) error {
+	if err := wsc.conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	writer, err := wsc.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(msg); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
 func (h *handler) wsHandler(w http.ResponseWriter, r *http.Request) {
 	// TODO(tzdybal): configuration options
 	upgrader := websocket.Upgrader{
@@ -50,21 +128,33 @@ func (h *handler) wsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	remoteAddr := wsc.RemoteAddr().String()
-	defer func() {
-		err := wsc.Close()
-		if err != nil {
-			h.logger.Error("failed to close WebSocket connection", "err")
-		}
-	}()
+	if h.srv.config.MaxBodyBytes > 0 {
+		wsc.SetReadLimit(h.srv.config.MaxBodyBytes)
+	}
 
 	ws := &wsConn{
-		conn:   wsc,
-		queue:  make(chan []byte),
-		logger: h.logger,
+		conn:              wsc,
+		queue:             make(chan []byte, h.srv.config.WebSocketWriteBufferSize),
+		logger:            h.logger,
+		closeOnSlowClient: h.srv.config.CloseOnSlowClient,
+		authenticated:     isAuthenticated(r),
+		done:              make(chan struct{}),
 	}
+	defer func() {
+		ws.close()
+		h.srv.unsubscribeClient(remoteAddr)
+	}()
 	go ws.sendLoop()
 
+	wsc.SetPongHandler(func(string) error {
+		return wsc.SetReadDeadline(time.Now().Add(wsReadWait))
+	})
+
 	for {
+		if err := wsc.SetReadDeadline(time.Now().Add(wsReadWait)); err != nil {
+			h.logger.Error("failed to set read deadline", "error", err)
+			break
+		}
 		mt, r, err := wsc.NextReader()
 		if err != nil {
 			h.logger.Error("failed to read next WebSocket message", "error", err)
@@ -85,9 +175,10 @@ func (h *handler) wsHandler(w http.ResponseWriter, r *http.Request) {
 
 		writer := new(bytes.Buffer)
 		h.serveJSONRPCforWS(newResponseWriter(writer), req, ws)
-		ws.queue <- writer.Bytes()
+		if !ws.send(writer.Bytes()) {
+			break
+		}
 	}
-
 }
 
 func newResponseWriter(w io.Writer) http.ResponseWriter {
@@ -1,7 +1,6 @@
 package json
 
 import (
-	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	cmconfig "github.com/cometbft/cometbft/config"
 	cmjson "github.com/cometbft/cometbft/libs/json"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/go-kit/kit/transport/http/jsonrpc"
 
 	"github.com/cometbft/cometbft/libs/log"
@@ -17,7 +18,12 @@ import (
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/rpc/access"
+	"github.com/rollkit/rollkit/test/mocks"
 )
 
 func TestWebSockets(t *testing.T) {
@@ -25,7 +31,7 @@ func TestWebSockets(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t, "TestWebSockets")
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, cmconfig.DefaultRPCConfig(), config.RPCConfig{}, log.TestingLogger())
 	require.NoError(err)
 
 	srv := httptest.NewServer(handler)
@@ -80,12 +86,103 @@ func TestWebSockets(t *testing.T) {
 	unsubscribeAllReq, err := json2.EncodeClientRequest("unsubscribe_all", &unsubscribeAllArgs{})
 	require.NoError(err)
 	require.NotEmpty(unsubscribeAllReq)
-	req := httptest.NewRequest(http.MethodGet, "/", bytes.NewReader(unsubscribeAllReq))
-	req.RemoteAddr = conn.LocalAddr().String()
-	rsp := httptest.NewRecorder()
-	handler.ServeHTTP(rsp, req)
-	assert.Equal(http.StatusOK, rsp.Code)
-	jsonResp := response{}
-	require.NoError(json.Unmarshal(rsp.Body.Bytes(), &jsonResp))
-	assert.Nil(jsonResp.Error)
+	require.NoError(conn.WriteMessage(websocket.TextMessage, unsubscribeAllReq))
+	// events published before unsubscribing may precede the response
+	require.Eventually(func() bool {
+		_, msg, err = conn.ReadMessage()
+		require.NoError(err)
+		jsonResp := response{}
+		require.NoError(json.Unmarshal(msg, &jsonResp))
+		return jsonResp.Error == nil && string(jsonResp.Result) == "{}"
+	}, 3*time.Second, time.Millisecond)
+}
+
+func TestSubscriptionLimits(t *testing.T) {
+	require := require.New(t)
+
+	client := &mocks.Client{}
+	client.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return((<-chan coretypes.ResultEvent)(make(chan coretypes.ResultEvent)), nil)
+	client.On("UnsubscribeAll", mock.Anything, mock.Anything).Return(nil)
+
+	s := newService(client, cmconfig.DefaultRPCConfig(), access.NewSubscriptions(1, 2), log.TestingLogger())
+
+	query := "tm.event='NewBlock'"
+	subscribe := func(addr string) error {
+		req := httptest.NewRequest(http.MethodGet, "/subscribe", nil)
+		req.RemoteAddr = addr
+		_, err := s.Subscribe(req, &subscribeArgs{Query: &query}, &wsConn{})
+		return err
+	}
+
+	// subscriptions over plain HTTP are rejected
+	req := httptest.NewRequest(http.MethodGet, "/subscribe", nil)
+	req.RemoteAddr = "client3:1234"
+	_, err := s.Subscribe(req, &subscribeArgs{Query: &query}, nil)
+	require.ErrorIs(err, errNotWebSocket)
+
+	require.NoError(subscribe("client1"))
+	require.NoError(subscribe("client1"))
+	require.ErrorContains(subscribe("client1"), "max_subscriptions_per_client 2 reached")
+	require.ErrorContains(subscribe("client2"), "max_subscription_clients 1 reached")
+
+	req = httptest.NewRequest(http.MethodGet, "/unsubscribe_all", nil)
+	req.RemoteAddr = "client1"
+	_, err = s.UnsubscribeAll(req, &unsubscribeAllArgs{}, &wsConn{})
+	require.NoError(err)
+	require.NoError(subscribe("client2"))
+
+	// subscriptions of disconnected client are canceled
+	s.unsubscribeClient("client2")
+	require.NoError(subscribe("client1"))
+	client.AssertCalled(t, "UnsubscribeAll", mock.Anything, "client2")
+
+	// limits apply to client IP addresses, regardless of the number of connections
+	s.unsubscribeClient("client1")
+	require.NoError(subscribe("10.0.0.1:1000"))
+	require.NoError(subscribe("10.0.0.1:1001"))
+	require.ErrorContains(subscribe("10.0.0.1:1002"), "max_subscriptions_per_client 2 reached")
+	require.ErrorContains(subscribe("10.0.0.2:1000"), "max_subscription_clients 1 reached")
+	s.unsubscribeClient("10.0.0.1:1000")
+	require.NoError(subscribe("10.0.0.1:1002"))
+	require.ErrorContains(subscribe("10.0.0.1:1003"), "max_subscriptions_per_client 2 reached")
+}
+
+func TestWebSocketSlowClient(t *testing.T) {
+	for _, closeOnSlowClient := range []bool{false, true} {
+		conns := make(chan *websocket.Conn, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			require.NoError(t, err)
+			conns <- conn
+		}))
+		defer srv.Close()
+
+		client, _, err := websocket.DefaultDialer.Dial(strings.Replace(srv.URL, "http://", "ws://", 1), nil)
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close()
+		}()
+
+		ws := &wsConn{
+			conn:              <-conns,
+			queue:             make(chan []byte, 1),
+			logger:            log.TestingLogger(),
+			closeOnSlowClient: closeOnSlowClient,
+			done:              make(chan struct{}),
+		}
+		ws.sendEvent([]byte("event1"))
+		// queue is full, as messages are not sent to the client
+		ws.sendEvent([]byte("event2"))
+
+		select {
+		case <-ws.done:
+			assert.True(t, closeOnSlowClient, "connection closed")
+		default:
+			assert.False(t, closeOnSlowClient, "connection not closed")
+			assert.Len(t, ws.queue, 1)
+			ws.close()
+		}
+		assert.False(t, ws.send([]byte("response")))
+	}
 }
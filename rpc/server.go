@@ -14,26 +14,52 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	"github.com/rs/cors"
 	"golang.org/x/net/netutil"
+	"google.golang.org/grpc"
 
+	rollconf "github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/rpc/access"
 	"github.com/rollkit/rollkit/rpc/json"
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
 )
 
+// queryServerProvider is implemented by nodes serving the gRPC query service.
+type queryServerProvider interface {
+	QueryServer() pb.QueryServiceServer
+}
+
 // Server handles HTTP and JSON-RPC requests, exposing Tendermint-compatible API.
+// If Rollkit gRPC listen address is configured, it also serves the gRPC query service of the node.
 type Server struct {
 	*service.BaseService
 
 	config *config.RPCConfig
 	client rpcclient.Client
+	node   node.Node
+
+	// rollkitConfig configures the gRPC listener, authentication, method access control and rate limiting of
+	// JSON-RPC and gRPC callers, and response size limit.
+	rollkitConfig rollconf.RPCConfig
+
+	// access and subscriptions are shared by JSON-RPC and gRPC servers, so that limits of callers apply to both
+	// of them together.
+	access        *access.Control
+	subscriptions *access.Subscriptions
 
-	server http.Server
+	server     http.Server
+	grpcServer *grpc.Server
 }
 
 // NewServer creates new instance of Server with given configuration.
-func NewServer(node node.Node, config *config.RPCConfig, logger log.Logger) *Server {
+//
+// CometBFT config configures the JSON-RPC listener and limits of the server, Rollkit config configures the gRPC
+// listener, access control and response size limit.
+func NewServer(node node.Node, config *config.RPCConfig, rollkitConfig rollconf.RPCConfig, logger log.Logger) *Server {
 	srv := &Server{
-		config: config,
-		client: node.GetClient(),
+		config:        config,
+		rollkitConfig: rollkitConfig,
+		client:        node.GetClient(),
+		node:          node,
 	}
 	srv.BaseService = service.NewBaseService(logger, "RPC", srv)
 	return srv
@@ -48,7 +74,23 @@ func (s *Server) Client() rpcclient.Client {
 
 // OnStart is called when Server is started (see service.BaseService for details).
 func (s *Server) OnStart() error {
-	return s.startRPC()
+	ac, err := access.NewGRPC(s.rollkitConfig, json.Methods(), grpcMethods)
+	if err != nil {
+		return err
+	}
+	s.access = ac
+	s.subscriptions = access.NewSubscriptions(s.config.MaxSubscriptionClients, s.config.MaxSubscriptionsPerClient)
+
+	if err := s.startGRPC(); err != nil {
+		return err
+	}
+	if err := s.startRPC(); err != nil {
+		if s.grpcServer != nil {
+			s.grpcServer.Stop()
+		}
+		return err
+	}
+	return nil
 }
 
 // OnStop is called when Server is stopped (see service.BaseService for details).
@@ -58,6 +100,73 @@ func (s *Server) OnStop() {
 	if err := s.server.Shutdown(ctx); err != nil {
 		s.Logger.Error("error while shutting down RPC server", "error", err)
 	}
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+}
+
+func (s *Server) startGRPC() error {
+	if s.rollkitConfig.GRPCListenAddress == "" {
+		return nil
+	}
+	provider, ok := s.node.(queryServerProvider)
+	if !ok {
+		s.Logger.Info("gRPC query service is not supported by the node")
+		return nil
+	}
+	proto, addr, err := splitListenAddress(s.rollkitConfig.GRPCListenAddress)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen(proto, addr)
+	if err != nil {
+		return err
+	}
+	if s.config.GRPCMaxOpenConnections != 0 {
+		s.Logger.Debug("limiting number of gRPC connections", "limit", s.config.GRPCMaxOpenConnections)
+		listener = netutil.LimitListener(listener, s.config.GRPCMaxOpenConnections)
+	}
+
+	var opts []grpc.ServerOption
+	if s.access != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(s.access.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(s.access.StreamServerInterceptor()),
+		)
+	}
+	// block subscriptions count towards the same limits as JSON-RPC subscriptions
+	opts = append(opts, grpc.ChainStreamInterceptor(s.subscriptions.StreamServerInterceptor("/rollkit.QueryService/SubscribeBlocks")))
+	s.grpcServer = grpc.NewServer(opts...)
+	pb.RegisterQueryServiceServer(s.grpcServer, provider.QueryServer())
+
+	s.Logger.Info("serving gRPC", "listen address", listener.Addr())
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.Logger.Error("error while serving gRPC", "error", err)
+		}
+	}()
+	return nil
+}
+
+// grpcMethods maps methods of the gRPC query service to JSON-RPC methods returning the same data, so that JSON-RPC
+// access lists apply to them.
+var grpcMethods = map[string]string{
+	"/rollkit.QueryService/GetBlock":        "block",
+	"/rollkit.QueryService/GetHeader":       "header",
+	"/rollkit.QueryService/GetTx":           "tx",
+	"/rollkit.QueryService/GetState":        "status",
+	"/rollkit.QueryService/GetDAInclusion":  "da_inclusion_proof",
+	"/rollkit.QueryService/SubscribeBlocks": "subscribe",
+}
+
+// splitListenAddress splits listen address in proto://host:port format.
+func splitListenAddress(listenAddress string) (string, string, error) {
+	parts := strings.SplitN(listenAddress, "://", 2)
+	if len(parts) != 2 {
+		return "", "", errors.New("invalid listen address: expecting tcp://host:port")
+	}
+	return parts[0], parts[1], nil
 }
 
 func (s *Server) startRPC() error {
@@ -65,12 +174,10 @@ func (s *Server) startRPC() error {
 		s.Logger.Info("Listen address not specified - RPC will not be exposed")
 		return nil
 	}
-	parts := strings.SplitN(s.config.ListenAddress, "://", 2)
-	if len(parts) != 2 {
-		return errors.New("invalid RPC listen address: expecting tcp://host:port")
+	proto, addr, err := splitListenAddress(s.config.ListenAddress)
+	if err != nil {
+		return err
 	}
-	proto := parts[0]
-	addr := parts[1]
 
 	listener, err := net.Listen(proto, addr)
 	if err != nil {
@@ -82,10 +189,7 @@ func (s *Server) startRPC() error {
 		listener = netutil.LimitListener(listener, s.config.MaxOpenConnections)
 	}
 
-	handler, err := json.GetHTTPHandler(s.client, s.Logger)
-	if err != nil {
-		return err
-	}
+	handler := json.NewHTTPHandler(s.client, s.config, s.access, s.subscriptions, s.rollkitConfig.MaxResponseBytes, s.Logger)
 
 	if s.config.IsCorsEnabled() {
 		s.Logger.Debug("CORS enabled",
@@ -116,6 +220,7 @@ func (s *Server) serve(listener net.Listener, handler http.Handler) error {
 	s.server = http.Server{
 		Handler:           handler,
 		ReadHeaderTimeout: time.Second * 2,
+		MaxHeaderBytes:    s.config.MaxHeaderBytes,
 	}
 	if s.config.TLSCertFile != "" && s.config.TLSKeyFile != "" {
 		return s.server.ServeTLS(listener, s.config.CertFile(), s.config.KeyFile())
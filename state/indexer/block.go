@@ -20,3 +20,14 @@ type BlockIndexer interface {
 	// and Endblock event search criteria.
 	Search(ctx context.Context, q *query.Query) ([]int64, error)
 }
+
+// PaginatedBlockIndexer is a BlockIndexer able to sort and paginate search results at the storage layer, so that
+// only blocks from the selected page are loaded by the caller.
+type PaginatedBlockIndexer interface {
+	BlockIndexer
+
+	// SearchPage returns heights of blocks matching the query, selected by pagination, and the total number
+	// of matching blocks. Indexers may stop searching as soon as the page is complete; the total then counts
+	// blocks up to the end of the page, and one more if any follows the page.
+	SearchPage(ctx context.Context, q *query.Query, p Pagination) ([]int64, int, error)
+}
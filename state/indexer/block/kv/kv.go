@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
-	"sort"
 	"strings"
 
 	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
 
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
@@ -20,13 +21,17 @@ import (
 	"github.com/rollkit/rollkit/store"
 )
 
-var _ indexer.BlockIndexer = (*BlockerIndexer)(nil)
+var (
+	_ indexer.PaginatedBlockIndexer = (*BlockerIndexer)(nil)
+	_ indexer.LegacyEntriesPruner   = (*BlockerIndexer)(nil)
+)
 
 // BlockerIndexer implements a block indexer, indexing BeginBlock and EndBlock
 // events with an underlying KV store. Block events are indexed by their height,
 // such that matching search criteria returns the respective block height(s).
 type BlockerIndexer struct {
-	store ds.TxnDatastore
+	store  ds.TxnDatastore
+	filter *indexer.EventFilter
 
 	ctx context.Context
 }
@@ -38,6 +43,11 @@ func New(ctx context.Context, store ds.TxnDatastore) *BlockerIndexer {
 	}
 }
 
+// SetEventFilter limits event attributes indexed by the BlockerIndexer. Blocks are always indexed by height.
+func (idx *BlockerIndexer) SetEventFilter(filter *indexer.EventFilter) {
+	idx.filter = filter
+}
+
 // Has returns true if the given height has been indexed. An error is returned
 // upon database query failure.
 func (idx *BlockerIndexer) Has(height int64) (bool, error) {
@@ -81,22 +91,44 @@ func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockEvents) error {
 	return batch.Commit(idx.ctx)
 }
 
+// PruneLegacyEntries deletes height and event index entries written by previous versions, whose heights are not
+// zero-padded. Such entries are not found by searches, and are not overwritten when blocks are reindexed.
+func (idx *BlockerIndexer) PruneLegacyEntries(ctx context.Context) (int, error) {
+	return indexer.PruneEntries(ctx, idx.store, isLegacyKey)
+}
+
 // Search performs a query for block heights that match a given BeginBlock
 // and Endblock event search criteria. The given query can match against zero,
 // one or more block heights. In the case of height queries, i.e. block.height=H,
 // if the height is indexed, that height alone will be returned. An error and
 // nil slice is returned. Otherwise, a non-nil slice and nil error is returned.
+// Heights are returned in ascending order.
 func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64, error) {
+	results, _, err := idx.SearchPage(ctx, q, indexer.Pagination{})
+	return results, err
+}
+
+// SearchPage performs a query for block heights like Search, and returns heights selected by pagination,
+// along with the total number of matching blocks. If all conditions are equalities of event attributes
+// or height comparisons, a single index is scanned in the selected order from the height bound (or the
+// cursor) until a block following the page is found (see searchOrdered); the total then counts blocks up
+// to the end of the page, and one more if any follows the page. Otherwise, the total is exact.
+func (idx *BlockerIndexer) SearchPage(ctx context.Context, q *query.Query, p indexer.Pagination) ([]int64, int, error) {
 	results := make([]int64, 0)
 	select {
 	case <-ctx.Done():
-		return results, nil
+		return results, 0, nil
 
 	default:
 	}
 
 	conditions := q.Syntax()
 
+	// scan a single index in order if possible, stopping as soon as the page is complete
+	if results, total, ok, err := idx.searchOrdered(ctx, conditions, p); err != nil || ok {
+		return results, total, err
+	}
+
 	// conditions to skip because they're handled before "everything else"
 	skipIndexes := make([]int, 0)
 
@@ -121,14 +153,15 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 	if ok && heightInfo.onlyHeightEq {
 		ok, err := idx.Has(heightInfo.height)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if ok {
-			return []int64{heightInfo.height}, nil
+			results, total := indexer.Paginate([]int64{heightInfo.height}, heightPosition, p)
+			return results, total, nil
 		}
 
-		return results, nil
+		return results, 0, nil
 	}
 
 	var heightsInitialized bool
@@ -159,7 +192,7 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 			if !heightsInitialized {
 				filteredHeights, err = idx.matchRange(ctx, qr, ds.NewKey(qr.Key).String(), filteredHeights, true, heightInfo)
 				if err != nil {
-					return nil, err
+					return nil, 0, err
 				}
 
 				heightsInitialized = true
@@ -172,7 +205,7 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 			} else {
 				filteredHeights, err = idx.matchRange(ctx, qr, ds.NewKey(qr.Key).String(), filteredHeights, false, heightInfo)
 				if err != nil {
-					return nil, err
+					return nil, 0, err
 				}
 			}
 		}
@@ -189,7 +222,7 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 		if !heightsInitialized {
 			filteredHeights, err = idx.match(ctx, c, ds.NewKey(startKey).String(), filteredHeights, true)
 			if err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 
 			heightsInitialized = true
@@ -202,44 +235,128 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 		} else {
 			filteredHeights, err = idx.match(ctx, c, ds.NewKey(string(startKey)).String(), filteredHeights, false)
 			if err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		}
 	}
 
-	// fetch matching heights
+	// collect matching heights; index entries are written along with the height entry of the block,
+	// so matched blocks are known to be indexed
 	results = make([]int64, 0, len(filteredHeights))
 	resultMap := make(map[int64]struct{})
 	for _, hBz := range filteredHeights {
-		cont := true
-
 		h := int64FromBytes(hBz)
+		if _, ok := resultMap[h]; !ok {
+			resultMap[h] = struct{}{}
+			results = append(results, h)
+		}
+	}
+
+	results, total := indexer.Paginate(results, heightPosition, p)
+	return results, total, nil
+}
+
+// searchOrdered selects heights of blocks matching conditions by scanning a single index in the order selected by
+// pagination. The scan starts at the height bound of the conditions (or at the cursor), and stops at the end of the
+// height range or as soon as a block following the page is found. It supports conditions matching event attributes
+// by equality and height comparisons: the index of the first event condition (or the height index) is scanned, and
+// other conditions are checked for every entry. It returns false if conditions are not supported.
+func (idx *BlockerIndexer) searchOrdered(
+	ctx context.Context,
+	conditions []syntax.Condition,
+	p indexer.Pagination,
+) (results []int64, total int, ok bool, err error) {
+	lo, hi, ok := indexer.HeightBounds(conditions, types.BlockHeightKey)
+	if !ok {
+		return nil, 0, false, nil
+	}
+	var events []syntax.Condition
+	for _, c := range conditions {
+		if c.Tag == types.BlockHeightKey {
+			continue
+		}
+		if c.Op != syntax.TEq {
+			return nil, 0, false, nil
+		}
+		events = append(events, c)
+	}
+	results = make([]int64, 0)
+	lo, hi = p.HeightRange(lo, hi)
+	if lo > hi {
+		return results, 0, true, nil
+	}
 
-		ok, err := idx.Has(h)
+	pager := indexer.NewPager(p)
+	if len(events) == 0 && lo == hi {
+		has, err := idx.Has(lo)
 		if err != nil {
-			return nil, err
+			return nil, 0, false, err
 		}
-		if ok {
-			if _, ok := resultMap[h]; !ok {
-				resultMap[h] = struct{}{}
-				results = append(results, h)
-			}
+		if has && pager.Add(heightPosition(lo)) {
+			results = append(results, lo)
 		}
+		return results, pager.Total(), true, nil
+	}
 
-		select {
-		case <-ctx.Done():
-			cont = false
+	// entries of the height index are keyed by base/height, and entries of event indexes by base/height/type
+	byHeight := len(events) == 0
+	var base string
+	if !byHeight {
+		base = store.GenerateKey([]string{events[0].Tag, events[0].Arg.Value()})
+		events = events[1:]
+	} else {
+		base = store.GenerateKey([]string{types.BlockHeightKey})
+	}
+	var start string
+	if !p.OrderDesc {
+		start = store.GenerateKey([]string{base, indexer.HeightKey(lo)})
+	} else if hi < math.MaxInt64 {
+		start = store.GenerateKey([]string{base, indexer.HeightKey(hi + 1)})
+	}
 
-		default:
+	last := int64(-1)
+	err = store.PrefixEntriesOrdered(ctx, idx.store, base, start, true, p.OrderDesc, func(entry dsq.Entry) (bool, error) {
+		parts := strings.Split(strings.TrimPrefix(entry.Key, base+"/"), "/")
+		if byHeight && len(parts) != 1 || !byHeight && len(parts) != 2 {
+			return true, nil
 		}
-		if !cont {
-			break
+		height, ok := indexer.ParseHeightKey(parts[0])
+		if !ok || height == last {
+			// entries written by previous versions are not ordered; events are indexed twice per block
+			return true, nil
 		}
+		last = height
+		if height < lo || height > hi {
+			// entries beyond the range follow all entries in the range
+			return (height > hi) == p.OrderDesc, nil
+		}
+		matches, err := idx.matchesEvents(ctx, events, height)
+		if err != nil {
+			return false, err
+		}
+		if matches && pager.Add(heightPosition(height)) {
+			results = append(results, height)
+		}
+		return !pager.More() && ctx.Err() == nil, nil
+	})
+	if err != nil {
+		return nil, 0, false, err
 	}
+	return results, pager.Total(), true, nil
+}
 
-	sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
-
-	return results, nil
+// matchesEvents returns true if the block at given height is indexed by all events.
+func (idx *BlockerIndexer) matchesEvents(ctx context.Context, events []syntax.Condition, height int64) (bool, error) {
+	for _, c := range events {
+		has, err := idx.store.Has(ctx, ds.NewKey(eventKey(c.Tag, "begin_block", c.Arg.Value(), height)))
+		if err == nil && !has {
+			has, err = idx.store.Has(ctx, ds.NewKey(eventKey(c.Tag, "end_block", c.Arg.Value(), height)))
+		}
+		if err != nil || !has {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
 // matchRange returns all matching block heights that match a given QueryRange
@@ -539,13 +656,13 @@ func (idx *BlockerIndexer) indexEvents(batch ds.Txn, events []abci.Event, typ st
 				continue
 			}
 
-			// index iff the event specified index:true and it's not a reserved event
+			// index iff the event specified index:true, it's accepted by the filter and it's not a reserved event
 			compositeKey := event.Type + "." + attr.Key
 			if compositeKey == types.BlockHeightKey {
 				return fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeKey)
 			}
 
-			if attr.GetIndex() {
+			if attr.GetIndex() && idx.filter.Accepts(event.Type, attr.Key) {
 				key := eventKey(compositeKey, typ, string(attr.Value), height)
 
 				if err := batch.Put(idx.ctx, ds.NewKey(key), heightBz); err != nil {
@@ -557,3 +674,7 @@ func (idx *BlockerIndexer) indexEvents(batch ds.Txn, events []abci.Event, typ st
 
 	return nil
 }
+
+func heightPosition(height int64) indexer.Position {
+	return indexer.Position{Height: height}
+}
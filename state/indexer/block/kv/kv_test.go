@@ -10,8 +10,10 @@ import (
 	"github.com/cometbft/cometbft/types"
 	ds "github.com/ipfs/go-datastore"
 	ktds "github.com/ipfs/go-datastore/keytransform"
+	dsq "github.com/ipfs/go-datastore/query"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rollkit/rollkit/state/indexer"
 	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
 	"github.com/rollkit/rollkit/store"
 )
@@ -144,3 +146,115 @@ func TestBlockIndexer(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockIndexerEventFilter(t *testing.T) {
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	blockIndexer := blockidxkv.New(context.Background(), kvStore)
+	blockIndexer.SetEventFilter(indexer.NewEventFilter(nil, []string{"end_event"}))
+
+	require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+		Height: 1,
+		Events: []abci.Event{
+			{Type: "begin_event", Attributes: []abci.EventAttribute{{Key: "proposer", Value: "FCAA001", Index: true}}},
+			{Type: "end_event", Attributes: []abci.EventAttribute{{Key: "foo", Value: "100", Index: true}}},
+		},
+	}))
+
+	for q, expected := range map[string]int{
+		"begin_event.proposer = 'FCAA001'": 1,
+		"end_event.foo = 100":              0,
+		"block.height = 1":                 1,
+	} {
+		results, err := blockIndexer.Search(context.Background(), query.MustCompile(q))
+		require.NoError(t, err)
+		require.Len(t, results, expected, q)
+	}
+}
+
+func TestBlockIndexerSearchPage(t *testing.T) {
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	blockIndexer := blockidxkv.New(context.Background(), kvStore)
+
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+			Height: height,
+			Events: []abci.Event{
+				{Type: "begin_event", Attributes: []abci.EventAttribute{{Key: "proposer", Value: "FCAA001", Index: true}}},
+			},
+		}))
+	}
+
+	for height := int64(9); height <= 12; height++ {
+		require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+			Height: height,
+			Events: []abci.Event{
+				{Type: "begin_event", Attributes: []abci.EventAttribute{
+					{Key: "proposer", Value: "FCAA002", Index: true},
+					{Key: "parity", Value: fmt.Sprint(height % 2), Index: true},
+				}},
+			},
+		}))
+	}
+	// entry written by previous versions, without zero-padded height
+	require.NoError(t, kvStore.Put(context.Background(), ds.NewKey("begin_event.proposer/FCAA001/6/begin_block"), []byte{12}))
+
+	cases := []struct {
+		query    string
+		p        indexer.Pagination
+		expected []int64
+		total    int
+	}{
+		{"begin_event.proposer = 'FCAA001'", indexer.Pagination{OrderDesc: true, Offset: 1, Limit: 2}, []int64{4, 3}, 4},
+		{"block.height > 0 AND block.height < 6", indexer.Pagination{OrderDesc: true, Offset: 1, Limit: 2}, []int64{4, 3}, 4},
+		{"begin_event.proposer = 'FCAA001'", indexer.Pagination{Offset: 3}, []int64{4, 5}, 5},
+		{"begin_event.proposer = 'FCAA001'", indexer.Pagination{After: &indexer.Position{Height: 3}, Limit: 2}, []int64{4, 5}, 2},
+		{"block.height > 0", indexer.Pagination{OrderDesc: true, Limit: 3}, []int64{12, 11, 10}, 4},
+		{"begin_event.proposer = 'FCAA002' AND begin_event.parity = 1", indexer.Pagination{}, []int64{9, 11}, 2},
+		{"begin_event.parity = 0 AND block.height < 12", indexer.Pagination{OrderDesc: true}, []int64{10}, 1},
+		{"block.height = 10", indexer.Pagination{}, []int64{10}, 1},
+		{"block.height = 10", indexer.Pagination{Offset: 1}, []int64{}, 1},
+		{"begin_event.proposer EXISTS", indexer.Pagination{OrderDesc: true, Limit: 2}, []int64{12, 11}, 10},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			results, total, err := blockIndexer.SearchPage(context.Background(), query.MustCompile(c.query), c.p)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, results)
+			require.Equal(t, c.total, total)
+		})
+	}
+}
+
+func TestBlockIndexerPruneLegacyEntries(t *testing.T) {
+	ctx := context.Background()
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	blockIndexer := blockidxkv.New(ctx, kvStore)
+
+	require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+		Height: 1,
+		Events: []abci.Event{
+			{Type: "begin_event", Attributes: []abci.EventAttribute{{Key: "proposer", Value: "FCAA001", Index: true}}},
+		},
+	}))
+	// entries written by previous versions, without zero-padded height
+	for _, key := range []string{"block.height/2", "begin_event.proposer/FCAA001/2/begin_block", "begin_event.proposer/FCAA001/2/end_block"} {
+		require.NoError(t, kvStore.Put(ctx, ds.NewKey(key), []byte{4}))
+	}
+
+	deleted, err := blockIndexer.PruneLegacyEntries(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+
+	results, err := kvStore.Query(ctx, dsq.Query{KeysOnly: true})
+	require.NoError(t, err)
+	entries, err := results.Rest()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	heights, err := blockIndexer.Search(ctx, query.MustCompile("begin_event.proposer = 'FCAA001'"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, heights)
+}
@@ -45,11 +45,25 @@ func int64ToBytes(i int64) []byte {
 }
 
 func heightKey(height int64) string {
-	return store.GenerateKey([]string{types.BlockHeightKey, strconv.FormatInt(height, 10)})
+	return store.GenerateKey([]string{types.BlockHeightKey, indexer.HeightKey(height)})
 }
 
 func eventKey(compositeKey, typ, eventValue string, height int64) string {
-	return store.GenerateKey([]string{compositeKey, eventValue, strconv.FormatInt(height, 10), typ})
+	return store.GenerateKey([]string{compositeKey, eventValue, indexer.HeightKey(height), typ})
+}
+
+// isLegacyKey returns true if key is a height or event index entry written by previous versions, keyed by
+// block.height/height or by base/height/type without zero-padding.
+func isLegacyKey(key string) bool {
+	parts := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	switch {
+	case len(parts) == 2:
+		return parts[0] == types.BlockHeightKey && indexer.IsLegacyHeightKey(parts[1])
+	case len(parts) >= 4:
+		typ := parts[len(parts)-1]
+		return (typ == "begin_block" || typ == "end_block") && indexer.IsLegacyHeightKey(parts[len(parts)-2])
+	}
+	return false
 }
 
 func parseValueFromPrimaryKey(key string) string {
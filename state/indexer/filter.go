@@ -0,0 +1,49 @@
+package indexer
+
+// EventFilter selects event attributes that are indexed. Filters contain event types (e.g. "transfer") or
+// composite keys of event type and attribute key (e.g. "transfer.recipient").
+// A nil EventFilter accepts all attributes.
+type EventFilter struct {
+	include map[string]struct{}
+	exclude map[string]struct{}
+}
+
+// NewEventFilter creates an EventFilter accepting attributes matching any entry of include (or all attributes
+// if include is empty) and not matching any entry of exclude. Empty entries are ignored.
+func NewEventFilter(include, exclude []string) *EventFilter {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	return &EventFilter{
+		include: toSet(include),
+		exclude: toSet(exclude),
+	}
+}
+
+// Accepts returns true if attribute of given event type and key should be indexed.
+func (f *EventFilter) Accepts(eventType, attrKey string) bool {
+	if f == nil {
+		return true
+	}
+	compositeKey := eventType + "." + attrKey
+	if matches(f.exclude, eventType, compositeKey) {
+		return false
+	}
+	return len(f.include) == 0 || matches(f.include, eventType, compositeKey)
+}
+
+func matches(set map[string]struct{}, eventType, compositeKey string) bool {
+	_, typeOk := set[eventType]
+	_, keyOk := set[compositeKey]
+	return typeOk || keyOk
+}
+
+func toSet(entries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if e != "" {
+			set[e] = struct{}{}
+		}
+	}
+	return set
+}
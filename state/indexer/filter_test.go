@@ -0,0 +1,40 @@
+package indexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFilter(t *testing.T) {
+	cases := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		accepted []string
+		rejected []string
+	}{
+		{"all", nil, nil, []string{"transfer.sender", "message.action"}, nil},
+		{"include type", []string{"transfer"}, nil, []string{"transfer.sender", "transfer.amount"}, []string{"message.action"}},
+		{"include key", []string{"transfer.sender", ""}, nil, []string{"transfer.sender"}, []string{"transfer.amount", "message.action"}},
+		{"exclude type", nil, []string{"message"}, []string{"transfer.sender"}, []string{"message.action", "message.sender"}},
+		{"exclude key", []string{"transfer"}, []string{"transfer.amount"}, []string{"transfer.sender"}, []string{"transfer.amount", "message.action"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := NewEventFilter(c.include, c.exclude)
+			for _, key := range c.accepted {
+				assert.True(t, f.Accepts(split(key)), key)
+			}
+			for _, key := range c.rejected {
+				assert.False(t, f.Accepts(split(key)), key)
+			}
+		})
+	}
+}
+
+func split(compositeKey string) (string, string) {
+	eventType, attrKey, _ := strings.Cut(compositeKey, ".")
+	return eventType, attrKey
+}
@@ -0,0 +1,218 @@
+package indexer
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/cometbft/cometbft/libs/pubsub/query/syntax"
+)
+
+// Widths of zero-padded heights and transaction indexes in index keys. Padding makes the order of keys
+// sharing a prefix equal to the order of positions, so that search results can be scanned in order.
+const (
+	heightKeyWidth = 19
+	indexKeyWidth  = 10
+)
+
+// KeyFormatVersion is the version of the format of KV index keys. Version 1 zero-pads heights and transaction
+// indexes; KV indexes written by previous versions (version 0) have to be rebuilt.
+const KeyFormatVersion = 1
+
+// Position is the position of a block (Index is 0) or transaction in the chain.
+type Position struct {
+	Height int64
+	Index  uint32
+}
+
+// Less returns true if position p precedes position o.
+func (p Position) Less(o Position) bool {
+	if p.Height == o.Height {
+		return p.Index < o.Index
+	}
+	return p.Height < o.Height
+}
+
+// Pagination selects a page of search results ordered by their position.
+type Pagination struct {
+	// OrderDesc sorts results in descending order (ascending by default).
+	OrderDesc bool
+	// After is an optional cursor; only results following it in the selected order are returned.
+	After *Position
+	// Offset is the number of skipped results (following the cursor, if set).
+	Offset int
+	// Limit is the maximum number of returned results; 0 means no limit.
+	Limit int
+}
+
+// follows returns true if pos follows the cursor in the selected order.
+func (p Pagination) follows(pos Position) bool {
+	if p.After == nil {
+		return true
+	}
+	if p.OrderDesc {
+		return pos.Less(*p.After)
+	}
+	return p.After.Less(pos)
+}
+
+// Paginate sorts items by position and returns the page selected by p, along with the number of items following
+// the cursor. Items are sorted in place.
+func Paginate[T any](items []T, position func(T) Position, p Pagination) ([]T, int) {
+	sort.Slice(items, func(i, j int) bool {
+		if p.OrderDesc {
+			return position(items[j]).Less(position(items[i]))
+		}
+		return position(items[i]).Less(position(items[j]))
+	})
+
+	start := 0
+	if p.After != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return p.follows(position(items[i]))
+		})
+	}
+	total := len(items) - start
+	start = min(start+max(p.Offset, 0), len(items))
+	end := len(items)
+	if p.Limit > 0 {
+		end = min(start+p.Limit, end)
+	}
+	return items[start:end], total
+}
+
+// HeightRange narrows the range [lo, hi] of heights to heights of results that may follow the cursor, so that ordered
+// scans can start at the cursor. If no height follows the cursor, lo is greater than hi.
+func (p Pagination) HeightRange(lo, hi int64) (int64, int64) {
+	if p.After == nil {
+		return lo, hi
+	}
+	if p.OrderDesc {
+		return lo, min(hi, p.After.Height)
+	}
+	return max(lo, p.After.Height), hi
+}
+
+// Pager selects a page from results of a scan ordered by position, so that the scan can stop as soon as the page is
+// complete and a result following it is found.
+type Pager struct {
+	p       Pagination
+	skipped int
+	taken   int
+	more    bool
+}
+
+// NewPager returns a Pager selecting the page described by p.
+func NewPager(p Pagination) *Pager {
+	return &Pager{p: p}
+}
+
+// Add returns true if the result at pos belongs to the page. Results must be added in the order selected by the
+// pagination.
+func (pg *Pager) Add(pos Position) bool {
+	if pg.more || !pg.p.follows(pos) {
+		return false
+	}
+	if pg.skipped < pg.p.Offset {
+		pg.skipped++
+		return false
+	}
+	if pg.p.Limit > 0 && pg.taken == pg.p.Limit {
+		pg.more = true
+		return false
+	}
+	pg.taken++
+	return true
+}
+
+// More returns true if the page is complete and a result following it was added, so the scan can stop.
+func (pg *Pager) More() bool {
+	return pg.more
+}
+
+// Total returns the number of added results following the cursor, up to the end of the page and one more if any
+// result follows the page. Unless the scan is complete, it is a lower bound of the number of all results.
+func (pg *Pager) Total() int {
+	total := pg.skipped + pg.taken
+	if pg.more {
+		total++
+	}
+	return total
+}
+
+// HeightKey returns height formatted for index keys.
+func HeightKey(height int64) string {
+	return fmt.Sprintf("%0*d", heightKeyWidth, height)
+}
+
+// IndexKey returns transaction index formatted for index keys.
+func IndexKey(index uint32) string {
+	return fmt.Sprintf("%0*d", indexKeyWidth, index)
+}
+
+// ParseHeightKey parses height formatted by HeightKey. Heights of keys written by previous versions, which are not
+// zero-padded, are rejected.
+func ParseHeightKey(s string) (int64, bool) {
+	if len(s) != heightKeyWidth {
+		return 0, false
+	}
+	height, err := strconv.ParseInt(s, 10, 64)
+	return height, err == nil
+}
+
+// ParseIndexKey parses transaction index formatted by IndexKey. Indexes of keys written by previous versions,
+// which are not zero-padded, are rejected.
+func ParseIndexKey(s string) (uint32, bool) {
+	if len(s) != indexKeyWidth {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(s, 10, 32)
+	return uint32(index), err == nil
+}
+
+// HeightBounds returns the range [lo, hi] of heights satisfying all conditions on heightTag. It returns false if any
+// of these conditions is not a comparison with a non-negative number. If no height satisfies the conditions, lo
+// is greater than hi.
+func HeightBounds(conditions []syntax.Condition, heightTag string) (lo, hi int64, ok bool) {
+	lo, hi = 0, math.MaxInt64
+	for _, c := range conditions {
+		if c.Tag != heightTag {
+			continue
+		}
+		f := c.Arg.Number()
+		if f == nil || f.Sign() < 0 || f.Cmp(big.NewFloat(math.MaxInt64)) >= 0 {
+			return 0, 0, false
+		}
+		h, acc := f.Int64() // truncated towards zero
+		exact := acc == big.Exact
+		switch c.Op {
+		case syntax.TEq:
+			if exact {
+				lo, hi = max(lo, h), min(hi, h)
+			} else {
+				lo, hi = 1, 0
+			}
+		case syntax.TGt:
+			lo = max(lo, h+1)
+		case syntax.TGeq:
+			if exact {
+				lo = max(lo, h)
+			} else {
+				lo = max(lo, h+1)
+			}
+		case syntax.TLt:
+			if exact {
+				hi = min(hi, h-1)
+			} else {
+				hi = min(hi, h)
+			}
+		case syntax.TLeq:
+			hi = min(hi, h)
+		default:
+			return 0, 0, false
+		}
+	}
+	return lo, hi, true
+}
@@ -0,0 +1,114 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/pubsub/query"
+	"github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	positions := func() []Position {
+		return []Position{{2, 1}, {1, 0}, {3, 0}, {2, 0}, {1, 1}}
+	}
+	identity := func(p Position) Position { return p }
+
+	// following is the number of all results following the cursor, and total the number counted by an ordered scan
+	cases := []struct {
+		name      string
+		p         Pagination
+		expected  []Position
+		following int
+		total     int
+	}{
+		{"all", Pagination{}, []Position{{1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}}, 5, 5},
+		{"desc", Pagination{OrderDesc: true}, []Position{{3, 0}, {2, 1}, {2, 0}, {1, 1}, {1, 0}}, 5, 5},
+		{"page", Pagination{Offset: 1, Limit: 2}, []Position{{1, 1}, {2, 0}}, 5, 4},
+		{"last page", Pagination{Offset: 4, Limit: 2}, []Position{{3, 0}}, 5, 5},
+		{"beyond last page", Pagination{Offset: 10, Limit: 2}, []Position{}, 5, 5},
+		{"desc page", Pagination{OrderDesc: true, Offset: 3}, []Position{{1, 1}, {1, 0}}, 5, 5},
+		{"cursor", Pagination{After: &Position{1, 1}, Limit: 2}, []Position{{2, 0}, {2, 1}}, 3, 3},
+		{"cursor desc", Pagination{OrderDesc: true, After: &Position{2, 0}}, []Position{{1, 1}, {1, 0}}, 2, 2},
+		{"cursor and offset", Pagination{After: &Position{2, 0}, Offset: 1}, []Position{{3, 0}}, 2, 2},
+		{"missing cursor", Pagination{After: &Position{1, 5}}, []Position{{2, 0}, {2, 1}, {3, 0}}, 3, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			page, total := Paginate(positions(), identity, c.p)
+			assert.Equal(t, c.expected, page)
+			assert.Equal(t, c.following, total)
+
+			// ordered scan selects the same page
+			sorted, _ := Paginate(positions(), identity, Pagination{OrderDesc: c.p.OrderDesc})
+			pager := NewPager(c.p)
+			scanned := []Position{}
+			for _, pos := range sorted {
+				if pager.Add(pos) {
+					scanned = append(scanned, pos)
+				}
+				if pager.More() {
+					break
+				}
+			}
+			assert.Equal(t, c.expected, scanned)
+			assert.Equal(t, c.total, pager.Total())
+		})
+	}
+}
+
+func TestHeightRange(t *testing.T) {
+	lo, hi := Pagination{}.HeightRange(2, 8)
+	assert.Equal(t, []int64{2, 8}, []int64{lo, hi})
+	lo, hi = Pagination{After: &Position{5, 1}}.HeightRange(2, 8)
+	assert.Equal(t, []int64{5, 8}, []int64{lo, hi})
+	lo, hi = Pagination{OrderDesc: true, After: &Position{5, 1}}.HeightRange(2, 8)
+	assert.Equal(t, []int64{2, 5}, []int64{lo, hi})
+	lo, hi = Pagination{After: &Position{9, 0}}.HeightRange(2, 8)
+	assert.Greater(t, lo, hi)
+}
+
+func TestHeightKey(t *testing.T) {
+	assert.Less(t, HeightKey(9), HeightKey(10))
+	assert.Less(t, IndexKey(9), IndexKey(10))
+
+	height, ok := ParseHeightKey(HeightKey(42))
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), height)
+	index, ok := ParseIndexKey(IndexKey(7))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), index)
+
+	// keys written by previous versions
+	_, ok = ParseHeightKey("42")
+	assert.False(t, ok)
+	_, ok = ParseIndexKey("7")
+	assert.False(t, ok)
+}
+
+func TestHeightBounds(t *testing.T) {
+	cases := []struct {
+		query  string
+		lo, hi int64
+		ok     bool
+	}{
+		{"tx.height = 5", 5, 5, true},
+		{"tx.height > 5 AND tx.height <= 10", 6, 10, true},
+		{"tx.height >= 5 AND tx.height < 10", 5, 9, true},
+		{"tx.height > 2.5 AND tx.height < 7.5", 3, 7, true},
+		{"tx.height = 5 AND tx.height > 7", 8, 5, true},
+		{"tx.height = 2.5", 1, 0, true},
+		{"account.number = 1", 0, 9223372036854775807, true},
+		{"tx.height CONTAINS '5'", 0, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			lo, hi, ok := HeightBounds(query.MustCompile(c.query).Syntax(), types.TxHeightKey)
+			assert.Equal(t, c.ok, ok)
+			if c.ok {
+				assert.Equal(t, c.lo, lo)
+				assert.Equal(t, c.hi, hi)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package indexer
+
+import (
+	"context"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+)
+
+// pruneBatchSize is the number of entries deleted in a single transaction by PruneEntries.
+const pruneBatchSize = 1000
+
+// LegacyEntriesPruner is implemented by KV indexers, which can delete index entries written in the key format of
+// previous versions (see KeyFormatVersion). Such entries are not found by searches, and are not overwritten when
+// blocks are reindexed.
+type LegacyEntriesPruner interface {
+	// PruneLegacyEntries deletes index entries in the key format of previous versions and returns their number.
+	PruneLegacyEntries(ctx context.Context) (int, error)
+}
+
+// PruneEntries deletes entries of the store whose keys are selected by prune, and returns the number of deleted
+// entries. Entries are deleted in batches while the store is scanned.
+func PruneEntries(ctx context.Context, store ds.TxnDatastore, prune func(key string) bool) (int, error) {
+	results, err := store.Query(ctx, dsq.Query{KeysOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	defer results.Close()
+
+	deleted := 0
+	keys := make([]ds.Key, 0, pruneBatchSize)
+	deleteKeys := func() error {
+		txn, err := store.NewTransaction(ctx, false)
+		if err != nil {
+			return err
+		}
+		defer txn.Discard(ctx)
+		for _, key := range keys {
+			if err := txn.Delete(ctx, key); err != nil {
+				return err
+			}
+		}
+		if err := txn.Commit(ctx); err != nil {
+			return err
+		}
+		deleted += len(keys)
+		keys = keys[:0]
+		return nil
+	}
+	for {
+		result, ok := results.NextSync()
+		if !ok {
+			break
+		}
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		if !prune(result.Key) {
+			continue
+		}
+		keys = append(keys, ds.NewKey(result.Key))
+		if len(keys) == pruneBatchSize {
+			if err := deleteKeys(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if len(keys) > 0 {
+		if err := deleteKeys(); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// IsLegacyHeightKey returns true if s is a height formatted for index keys by previous versions, which is not
+// zero-padded.
+func IsLegacyHeightKey(s string) bool {
+	return isLegacyNumberKey(s, heightKeyWidth)
+}
+
+// IsLegacyIndexKey returns true if s is a transaction index formatted for index keys by previous versions, which
+// is not zero-padded.
+func IsLegacyIndexKey(s string) bool {
+	return isLegacyNumberKey(s, indexKeyWidth)
+}
+
+func isLegacyNumberKey(s string, width int) bool {
+	return s != "" && len(s) < width && strings.Trim(s, "0123456789") == ""
+}
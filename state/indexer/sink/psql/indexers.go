@@ -0,0 +1,72 @@
+package psql
+
+import (
+	"context"
+	"errors"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/pubsub/query"
+	"github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/state/txindex"
+)
+
+// errSearchNotSupported is returned by queries of indexers backed by the sink; events should be queried with SQL.
+var errSearchNotSupported = errors.New("search is not supported by the psql event sink, query the database directly")
+
+var _ txindex.TxIndexer = TxIndexer{}
+var _ indexer.BlockIndexer = BlockIndexer{}
+
+// TxIndexer returns a transaction indexer storing transaction results in the sink.
+func (es *EventSink) TxIndexer() TxIndexer {
+	return TxIndexer{psql: es}
+}
+
+// TxIndexer implements the txindex.TxIndexer interface by delegating indexing
+// operations to an underlying PostgreSQL event sink.
+type TxIndexer struct{ psql *EventSink }
+
+// AddBatch indexes a batch of transactions in PostgreSQL.
+func (t TxIndexer) AddBatch(batch *txindex.Batch) error {
+	return t.psql.IndexTxEvents(batch.Ops)
+}
+
+// Index indexes a single transaction result in PostgreSQL.
+func (t TxIndexer) Index(txr *abci.TxResult) error {
+	return t.psql.IndexTxEvents([]*abci.TxResult{txr})
+}
+
+// Get is not supported by the psql event sink and reports an error for all inputs.
+func (TxIndexer) Get([]byte) (*abci.TxResult, error) {
+	return nil, errSearchNotSupported
+}
+
+// Search is not supported by the psql event sink and reports an error for all inputs.
+func (TxIndexer) Search(context.Context, *query.Query) ([]*abci.TxResult, error) {
+	return nil, errSearchNotSupported
+}
+
+// BlockIndexer returns a block indexer storing blocks and their events in the sink.
+func (es *EventSink) BlockIndexer() BlockIndexer {
+	return BlockIndexer{psql: es}
+}
+
+// BlockIndexer implements the indexer.BlockIndexer interface by delegating
+// indexing operations to an underlying PostgreSQL event sink.
+type BlockIndexer struct{ psql *EventSink }
+
+// Has returns true if block at given height was indexed.
+func (b BlockIndexer) Has(height int64) (bool, error) {
+	return b.psql.HasBlock(height)
+}
+
+// Index indexes FinalizeBlock events for the specified block.
+func (b BlockIndexer) Index(block types.EventDataNewBlockEvents) error {
+	return b.psql.IndexBlockEvents(block)
+}
+
+// Search is not supported by the psql event sink and reports an error for all inputs.
+func (BlockIndexer) Search(context.Context, *query.Query) ([]int64, error) {
+	return nil, errSearchNotSupported
+}
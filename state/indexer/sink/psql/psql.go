@@ -0,0 +1,261 @@
+// Package psql implements an event sink backed by a PostgreSQL database.
+package psql
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	_ "github.com/lib/pq" // registers PostgreSQL driver
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/state/indexer"
+)
+
+const (
+	tableBlocks     = "blocks"
+	tableTxResults  = "tx_results"
+	tableEvents     = "events"
+	tableAttributes = "attributes"
+	driverName      = "postgres"
+)
+
+// Schema is the database schema that must be installed before events are indexed.
+//
+//go:embed schema.sql
+var Schema string
+
+// EventSink is an indexer backend storing blocks, transaction results and their events in a PostgreSQL
+// database, using the schema defined in schema.sql. The schema is compatible with the psql event sink of
+// CometBFT, so existing SQL queries and tools work with Rollkit.
+type EventSink struct {
+	store   *sql.DB
+	chainID string
+
+	txFilter    *indexer.EventFilter
+	blockFilter *indexer.EventFilter
+}
+
+// NewEventSink constructs an event sink associated with the PostgreSQL database specified by connStr.
+// Events written to the sink are attributed to the specified chainID.
+func NewEventSink(connStr, chainID string) (*EventSink, error) {
+	db, err := sql.Open(driverName, connStr)
+	if err != nil {
+		return nil, err
+	}
+	return newEventSink(db, chainID), nil
+}
+
+func newEventSink(db *sql.DB, chainID string) *EventSink {
+	return &EventSink{
+		store:   db,
+		chainID: chainID,
+	}
+}
+
+// SetTxEventFilter limits attributes of transaction events stored by the sink. Transaction results and their
+// meta-events (tx.hash and tx.height) are always stored.
+func (es *EventSink) SetTxEventFilter(filter *indexer.EventFilter) {
+	es.txFilter = filter
+}
+
+// SetBlockEventFilter limits attributes of block events stored by the sink. Blocks and their meta-event
+// (block.height) are always stored.
+func (es *EventSink) SetBlockEventFilter(filter *indexer.EventFilter) {
+	es.blockFilter = filter
+}
+
+// DB returns the underlying PostgreSQL connection used by the sink.
+func (es *EventSink) DB() *sql.DB { return es.store }
+
+// Close closes the underlying PostgreSQL database.
+func (es *EventSink) Close() error { return es.store.Close() }
+
+// IndexBlockEvents indexes the block and its FinalizeBlock events.
+// Blocks that were already indexed are skipped.
+func (es *EventSink) IndexBlockEvents(h types.EventDataNewBlockEvents) error {
+	ts := time.Now().UTC()
+
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		// Add the block to the blocks table and report back its row ID for use
+		// in indexing the events for the block.
+		blockID, err := queryWithID(dbtx, `
+INSERT INTO `+tableBlocks+` (height, chain_id, created_at)
+  VALUES ($1, $2, $3)
+  ON CONFLICT DO NOTHING
+  RETURNING rowid;
+`, h.Height, es.chainID, ts)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil // block was already indexed
+		} else if err != nil {
+			return fmt.Errorf("indexing block header: %w", err)
+		}
+
+		// Insert the special block meta-event for height.
+		if err := insertEvents(dbtx, nil, blockID, 0, []abci.Event{
+			makeIndexedEvent(types.BlockHeightKey, fmt.Sprint(h.Height)),
+		}); err != nil {
+			return fmt.Errorf("block meta-events: %w", err)
+		}
+		if err := insertEvents(dbtx, es.blockFilter, blockID, 0, h.Events); err != nil {
+			return fmt.Errorf("finalizeblock events: %w", err)
+		}
+		return nil
+	})
+}
+
+// IndexTxEvents indexes transaction results and their events. Block of every transaction must be indexed
+// before the transaction. Transactions that were already indexed are skipped.
+func (es *EventSink) IndexTxEvents(txrs []*abci.TxResult) error {
+	ts := time.Now().UTC()
+
+	for _, txr := range txrs {
+		// Encode the result message in protobuf wire format for indexing.
+		resultData, err := proto.Marshal(txr)
+		if err != nil {
+			return fmt.Errorf("marshaling tx_result: %w", err)
+		}
+
+		// Index the hash of the underlying transaction as a hex string.
+		txHash := fmt.Sprintf("%X", types.Tx(txr.Tx).Hash())
+
+		if err := runInTransaction(es.store, func(dbtx *sql.Tx) error {
+			blockID, err := queryWithID(dbtx, `
+SELECT rowid FROM `+tableBlocks+` WHERE height = $1 AND chain_id = $2;
+`, txr.Height, es.chainID)
+			if err != nil {
+				return fmt.Errorf("finding block ID: %w", err)
+			}
+
+			// Insert a record for this tx_result and capture its ID for indexing events.
+			txID, err := queryWithID(dbtx, `
+INSERT INTO `+tableTxResults+` (block_id, index, created_at, tx_hash, tx_result)
+  VALUES ($1, $2, $3, $4, $5)
+  ON CONFLICT DO NOTHING
+  RETURNING rowid;
+`, blockID, txr.Index, ts, txHash, resultData)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil // transaction was already indexed
+			} else if err != nil {
+				return fmt.Errorf("indexing tx_result: %w", err)
+			}
+
+			// Insert the special transaction meta-events for hash and height.
+			if err := insertEvents(dbtx, nil, blockID, txID, []abci.Event{
+				makeIndexedEvent(types.TxHashKey, txHash),
+				makeIndexedEvent(types.TxHeightKey, fmt.Sprint(txr.Height)),
+			}); err != nil {
+				return fmt.Errorf("indexing transaction meta-events: %w", err)
+			}
+			if err := insertEvents(dbtx, es.txFilter, blockID, txID, txr.Result.Events); err != nil {
+				return fmt.Errorf("indexing transaction events: %w", err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasBlock returns true if block at given height was indexed.
+func (es *EventSink) HasBlock(height int64) (bool, error) {
+	var exists bool
+	err := es.store.QueryRow(`
+SELECT EXISTS(SELECT 1 FROM `+tableBlocks+` WHERE height = $1 AND chain_id = $2);
+`, height, es.chainID).Scan(&exists)
+	return exists, err
+}
+
+// runInTransaction executes query in a fresh database transaction.
+// If query reports an error, the transaction is rolled back and the
+// error from query is reported to the caller.
+// Otherwise, the result of committing the transaction is returned.
+func runInTransaction(db *sql.DB, query func(*sql.Tx) error) error {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := query(dbtx); err != nil {
+		_ = dbtx.Rollback() // report the initial error, not the rollback
+		return err
+	}
+	return dbtx.Commit()
+}
+
+// queryWithID executes the specified SQL query with the given arguments,
+// expecting a single-row, single-column result containing an ID.
+func queryWithID(tx *sql.Tx, query string, args ...interface{}) (uint32, error) {
+	var id uint32
+	if err := tx.QueryRow(query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// insertEvents inserts a slice of events and their attributes flagged for indexing and accepted by the filter.
+//
+// If txID > 0, the event is attributed to the transaction with that
+// ID; otherwise it is recorded as a block event.
+func insertEvents(dbtx *sql.Tx, filter *indexer.EventFilter, blockID, txID uint32, evts []abci.Event) error {
+	// Populate the transaction ID field iff one is defined (> 0).
+	var txIDArg interface{}
+	if txID > 0 {
+		txIDArg = txID
+	}
+
+	const (
+		insertEventQuery = `
+			INSERT INTO ` + tableEvents + ` (block_id, tx_id, type)
+			VALUES ($1, $2, $3)
+			RETURNING rowid;
+		`
+		insertAttributeQuery = `
+			INSERT INTO ` + tableAttributes + ` (event_id, key, composite_key, value)
+			VALUES ($1, $2, $3, $4);
+		`
+	)
+
+	for _, evt := range evts {
+		// Skip events with an empty type.
+		if evt.Type == "" {
+			continue
+		}
+
+		eid, err := queryWithID(dbtx, insertEventQuery, blockID, txIDArg, evt.Type)
+		if err != nil {
+			return err
+		}
+
+		for _, attr := range evt.Attributes {
+			if !attr.Index || !filter.Accepts(evt.Type, attr.Key) {
+				continue
+			}
+			compositeKey := evt.Type + "." + attr.Key
+			if _, err := dbtx.Exec(insertAttributeQuery, eid, attr.Key, compositeKey, attr.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// makeIndexedEvent constructs an event from the specified composite key and
+// value. If the key has the form "type.name", the event will have a single
+// attribute with that name and the value; otherwise the event will have only
+// a type and no attributes.
+func makeIndexedEvent(compositeKey, value string) abci.Event {
+	i := strings.Index(compositeKey, ".")
+	if i < 0 {
+		return abci.Event{Type: compositeKey}
+	}
+	return abci.Event{Type: compositeKey[:i], Attributes: []abci.EventAttribute{
+		{Key: compositeKey[i+1:], Value: value, Index: true},
+	}}
+}
@@ -0,0 +1,153 @@
+package psql
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/types"
+)
+
+// psqlTestConnEnv is the environment variable with the connection string of a PostgreSQL database used by
+// integration tests, e.g. "postgresql://postgres@localhost:5432/postgres?sslmode=disable". Integration tests are
+// skipped if it's not set.
+const psqlTestConnEnv = "ROLLKIT_TEST_PSQL_CONN"
+
+// newIntegrationSink returns event sink backed by a PostgreSQL database, with Schema installed in a fresh schema
+// of the database, which is dropped once the test completes.
+func newIntegrationSink(t *testing.T) *EventSink {
+	connStr := os.Getenv(psqlTestConnEnv)
+	if connStr == "" {
+		t.Skipf("%s is not set", psqlTestConnEnv)
+	}
+	db, err := sql.Open(driverName, connStr)
+	require.NoError(t, err)
+	// search path is set per connection, so all statements must use the same one
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("rollkit_test_%d", time.Now().UnixNano())
+	_, err = db.Exec(`CREATE SCHEMA ` + schema + `; SET search_path TO ` + schema + `;`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := db.Exec(`DROP SCHEMA ` + schema + ` CASCADE;`)
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+	})
+	_, err = db.Exec(Schema)
+	require.NoError(t, err)
+	return newEventSink(db, testChainID)
+}
+
+// countRows returns the number of rows of the table or view matching the condition.
+func countRows(t *testing.T, sink *EventSink, table, cond string, args ...interface{}) int {
+	var n int
+	require.NoError(t, sink.DB().QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE `+cond+`;`, args...).Scan(&n))
+	return n
+}
+
+func TestIntegrationIndexBlockEvents(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	sink := newIntegrationSink(t)
+
+	block := types.EventDataNewBlockEvents{
+		Height: 1,
+		Events: []abci.Event{
+			{Type: "begin_event", Attributes: []abci.EventAttribute{
+				{Key: "proposer", Value: "FCAA001", Index: true},
+				{Key: "ignored", Value: "1", Index: false},
+			}},
+		},
+	}
+	require.NoError(sink.IndexBlockEvents(block))
+	// block that was already indexed is skipped, as the insert returns no row ID
+	require.NoError(sink.IndexBlockEvents(block))
+
+	ok, err := sink.HasBlock(1)
+	require.NoError(err)
+	assert.True(ok)
+	assert.Equal(1, countRows(t, sink, tableBlocks, `height = $1`, 1))
+	assert.Equal(1, countRows(t, sink, "block_events", `height = $1 AND composite_key = $2 AND value = $3`, 1, types.BlockHeightKey, "1"))
+	assert.Equal(1, countRows(t, sink, "block_events", `height = $1 AND composite_key = $2 AND value = $3`, 1, "begin_event.proposer", "FCAA001"))
+	assert.Equal(0, countRows(t, sink, "block_events", `composite_key = $1`, "begin_event.ignored"))
+
+	// failure to index events rolls back the block, so that it's indexed again later
+	failing := types.EventDataNewBlockEvents{
+		Height: 2,
+		Events: []abci.Event{
+			{Type: "duplicate", Attributes: []abci.EventAttribute{
+				{Key: "key", Value: "1", Index: true},
+				{Key: "key", Value: "2", Index: true},
+			}},
+		},
+	}
+	require.Error(sink.IndexBlockEvents(failing))
+	ok, err = sink.HasBlock(2)
+	require.NoError(err)
+	assert.False(ok)
+	assert.Equal(0, countRows(t, sink, tableEvents, `type = $1`, "duplicate"))
+}
+
+func TestIntegrationIndexTxEvents(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	sink := newIntegrationSink(t)
+
+	txr := &abci.TxResult{
+		Height: 1,
+		Index:  0,
+		Tx:     types.Tx("HELLO WORLD"),
+		Result: abci.ExecTxResult{
+			Events: []abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{
+					{Key: "amount", Value: "100", Index: true},
+				}},
+			},
+		},
+	}
+
+	// block of the transaction must be indexed first
+	require.ErrorContains(sink.IndexTxEvents([]*abci.TxResult{txr}), "finding block ID")
+	assert.Equal(0, countRows(t, sink, tableTxResults, `TRUE`))
+
+	require.NoError(sink.IndexBlockEvents(types.EventDataNewBlockEvents{Height: 1}))
+	require.NoError(sink.IndexTxEvents([]*abci.TxResult{txr}))
+	// transaction that was already indexed is skipped, as the insert returns no row ID
+	require.NoError(sink.IndexTxEvents([]*abci.TxResult{txr}))
+
+	txHash := fmt.Sprintf("%X", types.Tx(txr.Tx).Hash())
+	assert.Equal(1, countRows(t, sink, tableTxResults, `tx_hash = $1`, txHash))
+	var data []byte
+	require.NoError(sink.DB().QueryRow(`SELECT tx_result FROM `+tableTxResults+` WHERE tx_hash = $1;`, txHash).Scan(&data))
+	stored := new(abci.TxResult)
+	require.NoError(proto.Unmarshal(data, stored))
+	assert.Equal(txr.Tx, stored.Tx)
+	assert.Equal(1, countRows(t, sink, "tx_events", `height = $1 AND composite_key = $2 AND value = $3`, 1, types.TxHashKey, txHash))
+	assert.Equal(1, countRows(t, sink, "tx_events", `height = $1 AND composite_key = $2 AND value = $3`, 1, types.TxHeightKey, "1"))
+	assert.Equal(1, countRows(t, sink, "tx_events", `height = $1 AND composite_key = $2 AND value = $3`, 1, "transfer.amount", "100"))
+
+	// failure to index events rolls back the transaction result
+	failing := &abci.TxResult{
+		Height: 1,
+		Index:  1,
+		Tx:     types.Tx("FAILING"),
+		Result: abci.ExecTxResult{
+			Events: []abci.Event{
+				{Type: "duplicate", Attributes: []abci.EventAttribute{
+					{Key: "key", Value: "1", Index: true},
+					{Key: "key", Value: "2", Index: true},
+				}},
+			},
+		},
+	}
+	require.Error(sink.IndexTxEvents([]*abci.TxResult{failing}))
+	assert.Equal(0, countRows(t, sink, tableTxResults, `index = $1`, 1))
+	assert.Equal(0, countRows(t, sink, tableEvents, `type = $1`, "duplicate"))
+}
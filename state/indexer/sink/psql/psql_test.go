@@ -0,0 +1,271 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/pubsub/query"
+	"github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/state/txindex"
+)
+
+const testChainID = "test-chain"
+
+func TestIndexBlockEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	db := newFakeDB()
+	sink := newEventSink(sql.OpenDB(db), testChainID)
+	sink.SetBlockEventFilter(indexer.NewEventFilter(nil, []string{"begin_event.secret"}))
+	blockIndexer := sink.BlockIndexer()
+
+	ok, err := blockIndexer.Has(1)
+	require.NoError(err)
+	assert.False(ok)
+
+	block := types.EventDataNewBlockEvents{
+		Height: 1,
+		Events: []abci.Event{
+			{Type: "begin_event", Attributes: []abci.EventAttribute{
+				{Key: "proposer", Value: "FCAA001", Index: true},
+				{Key: "secret", Value: "42", Index: true},
+				{Key: "ignored", Value: "1", Index: false},
+			}},
+			{Type: "", Attributes: []abci.EventAttribute{{Key: "empty", Value: "1", Index: true}}},
+		},
+	}
+	require.NoError(blockIndexer.Index(block))
+	// indexing the same block again is a no-op
+	require.NoError(blockIndexer.Index(block))
+
+	ok, err = blockIndexer.Has(1)
+	require.NoError(err)
+	assert.True(ok)
+
+	assert.Len(db.blocks, 1)
+	assert.Equal([]string{"block", "begin_event"}, db.eventTypes())
+	assert.Equal(map[string]string{
+		"block.height":         "1",
+		"begin_event.proposer": "FCAA001",
+	}, db.attributes())
+
+	_, err = blockIndexer.Search(context.Background(), query.MustCompile("block.height = 1"))
+	assert.ErrorIs(err, errSearchNotSupported)
+}
+
+func TestIndexTxEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	db := newFakeDB()
+	sink := newEventSink(sql.OpenDB(db), testChainID)
+	txIndexer := sink.TxIndexer()
+
+	txResult := &abci.TxResult{
+		Height: 1,
+		Index:  0,
+		Tx:     types.Tx("HELLO WORLD"),
+		Result: abci.ExecTxResult{
+			Events: []abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "owner", Value: "Ivan", Index: true}}},
+			},
+		},
+	}
+	batch := txindex.NewBatch(1)
+	require.NoError(batch.Add(txResult))
+
+	// block must be indexed before its transactions
+	require.Error(txIndexer.AddBatch(batch))
+
+	require.NoError(sink.BlockIndexer().Index(types.EventDataNewBlockEvents{Height: 1}))
+	require.NoError(txIndexer.AddBatch(batch))
+	// indexing the same transaction again is a no-op
+	require.NoError(txIndexer.Index(txResult))
+
+	require.Len(db.txResults, 1)
+	for _, row := range db.txResults {
+		assert.Equal(fmt.Sprintf("%X", types.Tx("HELLO WORLD").Hash()), row.hash)
+		stored := new(abci.TxResult)
+		require.NoError(proto.Unmarshal(row.result, stored))
+		assert.True(proto.Equal(txResult, stored))
+	}
+	assert.Equal([]string{"block", "tx", "tx", "account"}, db.eventTypes())
+	assert.Equal(map[string]string{
+		"block.height":  "1",
+		"tx.hash":       fmt.Sprintf("%X", types.Tx("HELLO WORLD").Hash()),
+		"tx.height":     "1",
+		"account.owner": "Ivan",
+	}, db.attributes())
+
+	_, err := txIndexer.Get(types.Tx("HELLO WORLD").Hash())
+	assert.ErrorIs(err, errSearchNotSupported)
+}
+
+func TestSchema(t *testing.T) {
+	for _, table := range []string{tableBlocks, tableTxResults, tableEvents, tableAttributes} {
+		assert.Contains(t, Schema, "CREATE TABLE "+table+" (")
+	}
+}
+
+// fakeDB is an in-memory stand-in for PostgreSQL database with the sink schema installed.
+// It supports only the statements executed by the sink.
+type fakeDB struct {
+	mtx       sync.Mutex
+	lastID    int64
+	blocks    map[string]int64
+	txResults map[string]fakeTxResult
+	events    []fakeEvent
+	attrs     []fakeAttribute
+}
+
+type fakeTxResult struct {
+	id     int64
+	hash   string
+	result []byte
+}
+
+type fakeEvent struct {
+	id   int64
+	typ  string
+	txID driver.Value
+}
+
+type fakeAttribute struct {
+	eventID      int64
+	compositeKey string
+	value        string
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		blocks:    make(map[string]int64),
+		txResults: make(map[string]fakeTxResult),
+	}
+}
+
+func (db *fakeDB) eventTypes() []string {
+	var types []string
+	for _, e := range db.events {
+		types = append(types, e.typ)
+	}
+	return types
+}
+
+func (db *fakeDB) attributes() map[string]string {
+	attrs := make(map[string]string)
+	for _, a := range db.attrs {
+		attrs[a.compositeKey] = a.value
+	}
+	return attrs
+}
+
+// query executes a statement and returns resulting row, or nil if no row is returned.
+func (db *fakeDB) query(q string, args []driver.Value) ([]driver.Value, error) {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	q = strings.Join(strings.Fields(q), " ")
+	switch {
+	case strings.HasPrefix(q, "INSERT INTO blocks"):
+		key := fmt.Sprint(args[0], "/", args[1])
+		if _, ok := db.blocks[key]; ok {
+			return nil, nil
+		}
+		db.lastID++
+		db.blocks[key] = db.lastID
+		return []driver.Value{db.lastID}, nil
+	case strings.HasPrefix(q, "SELECT rowid FROM blocks"):
+		id, ok := db.blocks[fmt.Sprint(args[0], "/", args[1])]
+		if !ok {
+			return nil, nil
+		}
+		return []driver.Value{id}, nil
+	case strings.HasPrefix(q, "SELECT EXISTS"):
+		_, ok := db.blocks[fmt.Sprint(args[0], "/", args[1])]
+		return []driver.Value{ok}, nil
+	case strings.HasPrefix(q, "INSERT INTO tx_results"):
+		key := fmt.Sprint(args[0], "/", args[1])
+		if _, ok := db.txResults[key]; ok {
+			return nil, nil
+		}
+		db.lastID++
+		db.txResults[key] = fakeTxResult{id: db.lastID, hash: args[3].(string), result: args[4].([]byte)}
+		return []driver.Value{db.lastID}, nil
+	case strings.HasPrefix(q, "INSERT INTO events"):
+		db.lastID++
+		db.events = append(db.events, fakeEvent{id: db.lastID, txID: args[1], typ: args[2].(string)})
+		return []driver.Value{db.lastID}, nil
+	case strings.HasPrefix(q, "INSERT INTO attributes"):
+		db.attrs = append(db.attrs, fakeAttribute{eventID: args[0].(int64), compositeKey: args[2].(string), value: args[3].(string)})
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported query: %s", q)
+}
+
+// Connect is a part of driver.Connector interface.
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+
+// Driver is a part of driver.Connector interface.
+func (db *fakeDB) Driver() driver.Driver { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, err := s.db.query(s.query, args)
+	return driver.RowsAffected(1), err
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	row, err := s.db.query(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{row: row}, nil
+}
+
+type fakeRows struct {
+	row []driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return make([]string, len(r.row)) }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.row == nil {
+		return io.EOF
+	}
+	copy(dest, r.row)
+	r.row = nil
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
+
+	"github.com/rollkit/rollkit/state/indexer"
 )
 
 // XXX/TODO: These types should be moved to the indexer package.
@@ -26,6 +28,17 @@ type TxIndexer interface {
 	Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error)
 }
 
+// PaginatedTxIndexer is a TxIndexer able to sort and paginate search results at the storage layer, before
+// transactions are loaded, so that only transactions from the selected page are loaded and decoded.
+type PaginatedTxIndexer interface {
+	TxIndexer
+
+	// SearchPage returns transactions matching the query, selected by pagination, and the total number of
+	// matching transactions. Indexers may stop searching as soon as the page is complete; the total then counts
+	// transactions up to the end of the page, and one more if any follows the page.
+	SearchPage(ctx context.Context, q *query.Query, p indexer.Pagination) ([]*abci.TxResult, int, error)
+}
+
 // Batch groups together multiple Index operations to be performed at the same time.
 // NOTE: Batch is NOT thread-safe and must not be modified after starting its execution.
 type Batch struct {
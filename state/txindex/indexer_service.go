@@ -2,11 +2,17 @@ package txindex
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cometbft/cometbft/libs/service"
 	"github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
 
 	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/store"
 )
 
 // XXX/TODO: These types should be moved to the indexer package.
@@ -25,6 +31,46 @@ type IndexerService struct {
 	blockIdxr        indexer.BlockIndexer
 	eventBus         *types.EventBus
 	terminateOnError bool
+	metrics          *Metrics
+
+	// heightStore persists indexedHeight, the height up to which all blocks are indexed (optional).
+	heightStore   ds.Datastore
+	indexedHeight atomic.Uint64
+	// receivedHeight is the height of the latest block received from the event bus.
+	receivedHeight atomic.Uint64
+
+	// blocks is the store of blocks reindexed in the background if they are missing in indexes (optional).
+	blocks     store.Store
+	reindexing atomic.Bool
+	// upgradeFormat enables deleting index entries in the key format of previous versions before reindexing.
+	upgradeFormat bool
+	// heightMtx guards advancing indexedHeight and indexedAhead, heights of new blocks indexed while missing blocks
+	// are reindexed.
+	heightMtx     sync.Mutex
+	indexedAhead  map[uint64]struct{}
+	cancelReindex context.CancelFunc
+	reindexDone   chan struct{}
+
+	// err is the reason why blocks are missing in indexes or new blocks are not indexed, and errHeight is the
+	// height of the latest received block when it occurred. It's cleared once a later block is indexed or, if the
+	// indexed height is tracked, once the indexed height passes errHeight.
+	errMtx    sync.Mutex
+	err       error
+	errHeight uint64
+}
+
+// Status describes progress of the indexer service.
+type Status struct {
+	// IndexedHeight is the height up to which all blocks are indexed.
+	IndexedHeight uint64 `json:"indexed_height"`
+	// LatestHeight is the height of the latest block stored by the node.
+	LatestHeight uint64 `json:"latest_height"`
+	// Lag is the number of blocks stored by the node, but not indexed yet.
+	Lag uint64 `json:"lag"`
+	// Reindexing is true while blocks missing in indexes are reindexed in the background.
+	Reindexing bool `json:"reindexing,omitempty"`
+	// Error describes why indexing has stopped or why the latest blocks are missing in indexes, if they are.
+	Error string `json:"error,omitempty"`
 }
 
 // NewIndexerService returns a new service instance.
@@ -36,14 +82,87 @@ func NewIndexerService(
 	terminateOnError bool,
 ) *IndexerService {
 
-	is := &IndexerService{ctx: ctx, txIdxr: txIdxr, blockIdxr: blockIdxr, eventBus: eventBus, terminateOnError: terminateOnError}
+	is := &IndexerService{ctx: ctx, txIdxr: txIdxr, blockIdxr: blockIdxr, eventBus: eventBus, terminateOnError: terminateOnError, metrics: NopMetrics()}
 	is.BaseService = *service.NewBaseService(nil, "IndexerService", is)
 	return is
 }
 
+// TrackIndexedHeight enables persisting the height up to which all blocks are indexed, starting from given height.
+// Heights are persisted only if blocks are indexed without gaps, so gaps can be detected and reindexed on restart.
+// Latest height is the height of the latest block stored by the node, used to report lag until new blocks are
+// received. It must be called before the service is started.
+func (is *IndexerService) TrackIndexedHeight(store ds.Datastore, height, latestHeight uint64) {
+	is.heightStore = store
+	is.indexedHeight.Store(height)
+	is.receivedHeight.Store(latestHeight)
+}
+
+// ReindexMissingBlocks enables reindexing blocks stored in the store, but missing in indexes, in the background once
+// the service is started. Blocks between the indexed height and the latest height given to TrackIndexedHeight are
+// reindexed, while new blocks are indexed in the meantime. It must be called before the service is started.
+func (is *IndexerService) ReindexMissingBlocks(blocks store.Store) {
+	is.blocks = blocks
+}
+
+// UpgradeIndexFormat enables deleting index entries written in the key format of previous versions in the
+// background, before blocks missing in indexes are reindexed (see ReindexMissingBlocks). The current format version
+// is persisted once they are deleted. It must be called before the service is started.
+func (is *IndexerService) UpgradeIndexFormat() {
+	is.upgradeFormat = true
+}
+
+// ReportError records error that left some blocks missing in indexes, e.g. failure to reindex them on startup.
+// It's reported by Err until blocks up to the latest received block are indexed (see Err).
+func (is *IndexerService) ReportError(err error) {
+	is.setErr(err)
+}
+
+// SetMetrics sets the metrics updated by the service. It must be called before the service is started.
+func (is *IndexerService) SetMetrics(metrics *Metrics) {
+	is.metrics = metrics
+}
+
+// IndexedHeight returns the height up to which all blocks are indexed. It's tracked only if TrackIndexedHeight was
+// called.
+func (is *IndexerService) IndexedHeight() uint64 {
+	return is.indexedHeight.Load()
+}
+
+// Err returns the reason why the service has stopped indexing new blocks, or why some blocks are missing in indexes.
+// Errors are cleared once a block received after the error is indexed. If the indexed height is tracked, they are
+// reported until the indexed height passes the latest block received when they occurred instead, so errors that
+// left blocks missing in indexes are reported until the blocks are reindexed.
+func (is *IndexerService) Err() error {
+	if !is.IsRunning() {
+		return errors.New("indexer service is not running")
+	}
+	is.errMtx.Lock()
+	defer is.errMtx.Unlock()
+	return is.err
+}
+
+// Status returns progress of the service, given the height of the latest block stored by the node.
+func (is *IndexerService) Status(latestHeight uint64) Status {
+	status := Status{
+		IndexedHeight: is.IndexedHeight(),
+		LatestHeight:  latestHeight,
+	}
+	if latestHeight > status.IndexedHeight {
+		status.Lag = latestHeight - status.IndexedHeight
+	}
+	status.Reindexing = is.reindexing.Load()
+	if err := is.Err(); err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
 // OnStart implements service.Service by subscribing for all transactions
 // and indexing them by events.
 func (is *IndexerService) OnStart() error {
+	is.metrics.IndexedHeight.Set(float64(is.indexedHeight.Load()))
+	is.updateLag()
+
 	// Use SubscribeUnbuffered here to ensure both subscriptions does not get
 	// canceled due to not pulling messages fast enough. Cause this might
 	// sometimes happen when there are no other subscribers.
@@ -66,13 +185,18 @@ func (is *IndexerService) OnStart() error {
 			case <-is.ctx.Done():
 				return
 			case <-blockSub.Canceled():
+				is.setErr(fmt.Errorf("block events subscription canceled: %w", blockSub.Err()))
 				return
 			case msg := <-blockSub.Out():
 				eventNewBlockEvents := msg.Data().(types.EventDataNewBlockEvents)
 				height := eventNewBlockEvents.Height
 				numTxs := eventNewBlockEvents.NumTxs
+				if height > 0 {
+					is.receivedHeight.Store(uint64(height))
+				}
 
 				batch := NewBatch(numTxs)
+				indexed := true
 
 				for i := int64(0); i < numTxs; i++ {
 					select {
@@ -88,6 +212,8 @@ func (is *IndexerService) OnStart() error {
 								"index", txResult.Index,
 								"err", err,
 							)
+							indexed = false
+							is.failed(height, err)
 
 							if is.terminateOnError {
 								if err := is.Stop(); err != nil {
@@ -101,6 +227,8 @@ func (is *IndexerService) OnStart() error {
 
 				if err := is.blockIdxr.Index(eventNewBlockEvents); err != nil {
 					is.Logger.Error("failed to index block", "height", height, "err", err)
+					indexed = false
+					is.failed(height, err)
 					if is.terminateOnError {
 						if err := is.Stop(); err != nil {
 							is.Logger.Error("failed to stop", "err", err)
@@ -113,6 +241,8 @@ func (is *IndexerService) OnStart() error {
 
 				if err = is.txIdxr.AddBatch(batch); err != nil {
 					is.Logger.Error("failed to index block txs", "height", height, "err", err)
+					indexed = false
+					is.failed(height, err)
 					if is.terminateOnError {
 						if err := is.Stop(); err != nil {
 							is.Logger.Error("failed to stop", "err", err)
@@ -122,14 +252,158 @@ func (is *IndexerService) OnStart() error {
 				} else {
 					is.Logger.Debug("indexed transactions", "height", height, "num_txs", numTxs)
 				}
+
+				if indexed {
+					is.advanceIndexedHeight(height)
+					is.clearErr(height)
+				}
+				is.updateLag()
 			}
 		}
 	}()
+
+	if is.blocks != nil && is.heightStore != nil && is.receivedHeight.Load() > is.indexedHeight.Load() {
+		ctx, cancel := context.WithCancel(is.ctx)
+		is.cancelReindex = cancel
+		is.reindexDone = make(chan struct{})
+		is.indexedAhead = make(map[uint64]struct{})
+		is.reindexing.Store(true)
+		from, to := is.indexedHeight.Load()+1, is.receivedHeight.Load()
+		go func() {
+			defer close(is.reindexDone)
+			defer is.stopReindexing()
+			if is.upgradeFormat && !is.upgradeIndexFormat(ctx) {
+				return
+			}
+			is.reindexMissingBlocks(ctx, from, to)
+		}()
+	}
 	return nil
 }
 
-// OnStop implements service.Service by unsubscribing from all transactions.
+// upgradeIndexFormat deletes index entries in the key format of previous versions and persists the current format
+// version. It returns false if they couldn't be deleted.
+func (is *IndexerService) upgradeIndexFormat(ctx context.Context) bool {
+	err := PruneLegacyEntries(ctx, is.txIdxr, is.blockIdxr, is.Logger)
+	if err == nil {
+		err = SaveIndexFormat(ctx, is.heightStore, indexer.KeyFormatVersion)
+	}
+	if err != nil && ctx.Err() == nil {
+		is.Logger.Error("failed to upgrade index format", "err", err)
+		is.setErr(fmt.Errorf("failed to upgrade index format: %w", err))
+	}
+	return err == nil
+}
+
+// reindexMissingBlocks reindexes blocks in [from, to] range, advancing the indexed height with every reindexed block.
+func (is *IndexerService) reindexMissingBlocks(ctx context.Context, from, to uint64) {
+	is.Logger.Info("reindexing blocks missing in indexes", "from", from, "to", to)
+	for height := from; height <= to; height++ {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := reindexBlock(ctx, is.blocks, is.txIdxr, is.blockIdxr, height); err != nil {
+			is.Logger.Error("failed to reindex block missing in indexes", "height", height, "err", err)
+			is.setErr(fmt.Errorf("failed to reindex blocks missing in indexes: failed to reindex block %d: %w", height, err))
+			return
+		}
+		is.advanceIndexedHeight(int64(height)) //nolint:gosec
+		is.clearErr(int64(height))             //nolint:gosec
+		is.updateLag()
+		if (height-from+1)%reindexLogInterval == 0 {
+			is.Logger.Info("reindexing blocks missing in indexes", "height", height, "to", to)
+		}
+	}
+	is.Logger.Info("reindexed blocks missing in indexes", "from", from, "to", to)
+}
+
+func (is *IndexerService) stopReindexing() {
+	is.heightMtx.Lock()
+	defer is.heightMtx.Unlock()
+	is.reindexing.Store(false)
+	is.indexedAhead = nil
+}
+
+// advanceIndexedHeight persists the indexed height, if block at given height directly follows previously indexed
+// block. Otherwise, some blocks were not indexed and the height is not advanced until they are reindexed. While
+// missing blocks are reindexed in the background, heights of new blocks are remembered, so the indexed height
+// advances over them once the gap is reindexed.
+func (is *IndexerService) advanceIndexedHeight(height int64) {
+	is.heightMtx.Lock()
+	defer is.heightMtx.Unlock()
+	if is.heightStore == nil || height <= 0 {
+		return
+	}
+	h := uint64(height)
+	if h != is.indexedHeight.Load()+1 {
+		if is.indexedAhead != nil && h > is.indexedHeight.Load() {
+			is.indexedAhead[h] = struct{}{}
+		}
+		return
+	}
+	for {
+		if _, ok := is.indexedAhead[h+1]; !ok {
+			break
+		}
+		delete(is.indexedAhead, h+1)
+		h++
+	}
+	if err := SaveIndexedHeight(is.ctx, is.heightStore, h); err != nil {
+		is.Logger.Error("failed to save indexed height", "height", h, "err", err)
+		is.setErr(fmt.Errorf("failed to save indexed height %d: %w", h, err))
+		return
+	}
+	is.indexedHeight.Store(h)
+	is.metrics.IndexedHeight.Set(float64(h))
+}
+
+// updateLag updates the number of received blocks that are not indexed, if indexed height is tracked.
+func (is *IndexerService) updateLag() {
+	if is.heightStore == nil {
+		return
+	}
+	lag := uint64(0)
+	if received, indexed := is.receivedHeight.Load(), is.indexedHeight.Load(); received > indexed {
+		lag = received - indexed
+	}
+	is.metrics.Lag.Set(float64(lag))
+}
+
+// failed records failure to index block at given height. The block remains missing in indexes until it's
+// reindexed on restart, and the failure is reported by Err until then (see Err).
+func (is *IndexerService) failed(height int64, err error) {
+	is.metrics.Failures.Add(1)
+	is.setErr(fmt.Errorf("failed to index block %d: %w", height, err))
+}
+
+func (is *IndexerService) setErr(err error) {
+	is.errMtx.Lock()
+	defer is.errMtx.Unlock()
+	is.err = err
+	is.errHeight = is.receivedHeight.Load()
+}
+
+// clearErr clears the error if it occurred before the block at given height was received. If the indexed height is
+// tracked, it must pass the latest block received when the error occurred instead, so that errors are reported until
+// blocks left missing in indexes are reindexed.
+func (is *IndexerService) clearErr(height int64) {
+	if is.heightStore != nil {
+		height = int64(is.indexedHeight.Load()) //nolint:gosec
+	}
+	is.errMtx.Lock()
+	defer is.errMtx.Unlock()
+	if is.err != nil && height > 0 && uint64(height) > is.errHeight {
+		is.Logger.Info("indexing recovered", "height", height, "previous_err", is.err)
+		is.err = nil
+	}
+}
+
+// OnStop implements service.Service by unsubscribing from all transactions and stopping reindexing.
 func (is *IndexerService) OnStop() {
+	if is.cancelReindex != nil {
+		is.cancelReindex()
+		<-is.reindexDone
+	}
 	if is.eventBus.IsRunning() {
 		_ = is.eventBus.UnsubscribeAll(is.ctx, subscriber)
 	}
@@ -2,6 +2,7 @@ package txindex_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	ktds "github.com/ipfs/go-datastore/keytransform"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rollkit/rollkit/state/indexer"
 	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
 	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/state/txindex/kv"
@@ -85,3 +87,85 @@ func TestIndexerServiceIndexesBlocks(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, txResult2, res)
 }
+
+// failingBlockIndexer fails to index the block at given height.
+type failingBlockIndexer struct {
+	indexer.BlockIndexer
+	height int64
+}
+
+func (idx failingBlockIndexer) Index(events types.EventDataNewBlockEvents) error {
+	if events.Height == idx.height {
+		return errors.New("disk full")
+	}
+	return idx.BlockIndexer.Index(events)
+}
+
+func TestIndexerServiceStatus(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blockIndexer := failingBlockIndexer{BlockIndexer: blockidxkv.New(ctx, kvStore), height: 3}
+
+	service := txindex.NewIndexerService(ctx, kv.NewTxIndex(ctx, kvStore), blockIndexer, eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	service.TrackIndexedHeight(ds.NewMapDatastore(), 0, 0)
+	require.Error(service.Err())
+	require.NoError(service.Start())
+
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 1}))
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 2}))
+	require.Eventually(func() bool { return service.IndexedHeight() == 2 }, time.Second, 10*time.Millisecond)
+	require.NoError(service.Err())
+	require.Equal(txindex.Status{IndexedHeight: 2, LatestHeight: 5, Lag: 3}, service.Status(5))
+
+	// failure to index a block is reported while the block remains missing, even if later blocks are indexed
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 3}))
+	require.Eventually(func() bool { return service.Err() != nil }, time.Second, 10*time.Millisecond)
+	require.ErrorContains(service.Err(), "failed to index block 3: disk full")
+	status := service.Status(3)
+	require.Equal(uint64(2), status.IndexedHeight)
+	require.Equal(uint64(1), status.Lag)
+	require.NotEmpty(status.Error)
+
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 4}))
+	require.Eventually(func() bool { ok, _ := blockIndexer.Has(4); return ok }, time.Second, 10*time.Millisecond)
+	require.ErrorContains(service.Err(), "failed to index block 3: disk full")
+
+	require.NoError(service.Stop())
+	require.ErrorContains(service.Err(), "not running")
+
+	// failure to reindex blocks on startup is reported once the service is started, while blocks remain missing
+	service = txindex.NewIndexerService(ctx, kv.NewTxIndex(ctx, kvStore), blockIndexer, eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	service.TrackIndexedHeight(ds.NewMapDatastore(), 2, 4)
+	service.ReportError(errors.New("failed to reindex blocks"))
+	require.NoError(service.Start())
+	require.ErrorContains(service.Err(), "failed to reindex blocks")
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 5}))
+	require.Eventually(func() bool { ok, _ := blockIndexer.Has(5); return ok }, time.Second, 10*time.Millisecond)
+	require.ErrorContains(service.Err(), "failed to reindex blocks")
+	require.NoError(service.Stop())
+
+	// without tracked indexed height, errors are reported until a later block is indexed
+	service = txindex.NewIndexerService(ctx, kv.NewTxIndex(ctx, kvStore), blockIndexer, eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	service.ReportError(errors.New("failed to index block"))
+	require.NoError(service.Start())
+	require.ErrorContains(service.Err(), "failed to index block")
+	require.NoError(eventBus.PublishEventNewBlockEvents(types.EventDataNewBlockEvents{Height: 6}))
+	require.Eventually(func() bool { return service.Err() == nil }, time.Second, 10*time.Millisecond)
+	require.NoError(service.Stop())
+}
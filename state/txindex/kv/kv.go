@@ -5,12 +5,14 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/gogo/protobuf/proto"
 	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
 
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
@@ -27,11 +29,15 @@ const (
 	tagKeySeparator = "/"
 )
 
-var _ txindex.TxIndexer = (*TxIndex)(nil)
+var (
+	_ txindex.PaginatedTxIndexer  = (*TxIndex)(nil)
+	_ indexer.LegacyEntriesPruner = (*TxIndex)(nil)
+)
 
 // TxIndex is the simplest possible indexer, backed by key-value storage (levelDB).
 type TxIndex struct {
-	store ds.TxnDatastore
+	store  ds.TxnDatastore
+	filter *indexer.EventFilter
 
 	ctx context.Context
 }
@@ -44,6 +50,11 @@ func NewTxIndex(ctx context.Context, store ds.TxnDatastore) *TxIndex {
 	}
 }
 
+// SetEventFilter limits event attributes indexed by the TxIndex. Transactions are always indexed by hash and height.
+func (txi *TxIndex) SetEventFilter(filter *indexer.EventFilter) {
+	txi.filter = filter
+}
+
 // Get gets transaction from the TxIndex storage and returns it or nil if the
 // transaction is not found.
 func (txi *TxIndex) Get(hash []byte) (*abci.TxResult, error) {
@@ -161,10 +172,10 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store ds.Txn
 				continue
 			}
 
-			// index if `index: true` is set
+			// index if `index: true` is set and attribute is accepted by the filter
 			compositeTag := event.Type + "." + attr.Key
-			if attr.GetIndex() {
-				err := store.Put(txi.ctx, ds.NewKey(keyForEvent(compositeTag, attr.Value, result)), hash)
+			if attr.GetIndex() && txi.filter.Accepts(event.Type, attr.Key) {
+				err := store.Put(txi.ctx, ds.NewKey(keyForEvent(compositeTag, attr.Value, result.Height, result.Index)), hash)
 				if err != nil {
 					return err
 				}
@@ -175,27 +186,47 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store ds.Txn
 	return nil
 }
 
-// Search performs a search using the given query.
+// PruneLegacyEntries deletes event and height index entries written by previous versions, whose heights and
+// indexes are not zero-padded. Such entries are not found by searches, and are not overwritten when transactions
+// are reindexed.
+func (txi *TxIndex) PruneLegacyEntries(ctx context.Context) (int, error) {
+	return indexer.PruneEntries(ctx, txi.store, isLegacyKey)
+}
+
+// Search performs a search using the given query and returns all matching transactions, ordered by height
+// and index. See SearchPage for details.
+func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error) {
+	results, _, err := txi.SearchPage(ctx, q, indexer.Pagination{})
+	return results, err
+}
+
+// SearchPage performs a search using the given query and returns a page of matching transactions selected by
+// pagination, along with the total number of matching transactions. If the search stops as soon as the page is
+// complete, the total counts transactions up to the end of the page, and one more if any follows the page.
 //
-// It breaks the query into conditions (like "tx.height > 5"). For each
-// condition, it queries the DB index. One special use cases here: (1) if
-// "tx.hash" is found, it returns tx result for it (2) for range queries it is
-// better for the client to provide both lower and upper bounds, so we are not
-// performing a full scan. Results from querying indexes are then intersected
-// and returned to the caller, in no particular order.
+// It breaks the query into conditions (like "tx.height > 5"). One special use
+// case here: if "tx.hash" is found, it returns tx result for it. If all other
+// conditions are equalities of event attributes or height comparisons, a single
+// index is scanned in the selected order from the height bound (or the cursor)
+// until a result following the page is found (see searchOrdered). Otherwise,
+// for each condition, it queries the DB index; for range queries it is better
+// for the client to provide both lower and upper bounds, so we are not
+// performing a full scan. Results from querying indexes (hashes with heights
+// and indexes of transactions) are then intersected, sorted and paginated, and
+// the total is exact. Only transactions in the selected page are loaded.
 //
-// Search will exit early and return any result fetched so far,
+// SearchPage will exit early and return any result fetched so far,
 // when a message is received on the context chan.
-func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error) {
+func (txi *TxIndex) SearchPage(ctx context.Context, q *query.Query, p indexer.Pagination) ([]*abci.TxResult, int, error) {
 	select {
 	case <-ctx.Done():
-		return make([]*abci.TxResult, 0), nil
+		return make([]*abci.TxResult, 0), 0, nil
 
 	default:
 	}
 
 	var hashesInitialized bool
-	filteredHashes := make(map[string][]byte)
+	filteredHashes := make(map[string]txRef)
 
 	// get a list of conditions (like "tx.height > 5")
 	conditions := q.Syntax()
@@ -203,19 +234,28 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResul
 	// if there is a hash condition, return the result immediately
 	hash, ok, err := lookForHash(conditions)
 	if err != nil {
-		return nil, fmt.Errorf("error during searching for a hash in the query: %w", err)
+		return nil, 0, fmt.Errorf("error during searching for a hash in the query: %w", err)
 	} else if ok {
 		res, err := txi.Get(hash)
 		switch {
 		case err != nil:
-			return []*abci.TxResult{}, fmt.Errorf("error while retrieving the result: %w", err)
+			return []*abci.TxResult{}, 0, fmt.Errorf("error while retrieving the result: %w", err)
 		case res == nil:
-			return []*abci.TxResult{}, nil
+			return []*abci.TxResult{}, 0, nil
 		default:
-			return []*abci.TxResult{res}, nil
+			results, total := indexer.Paginate([]*abci.TxResult{res}, txResultPosition, p)
+			return results, total, nil
 		}
 	}
 
+	// scan a single index in order if possible, stopping as soon as the page is complete
+	if refs, total, ok, err := txi.searchOrdered(ctx, conditions, p); err != nil {
+		return nil, 0, err
+	} else if ok {
+		results, err := txi.loadResults(ctx, refs)
+		return results, total, err
+	}
+
 	// conditions to skip because they're handled before "everything else"
 	skipIndexes := make([]int, 0)
 	var heightInfo HeightInfo
@@ -279,20 +319,28 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResul
 		}
 	}
 
-	results := make([]*abci.TxResult, 0, len(filteredHashes))
-	resultMap := make(map[string]struct{})
-RESULTS_LOOP:
-	for _, h := range filteredHashes {
+	refs := make([]txRef, 0, len(filteredHashes))
+	for _, ref := range filteredHashes {
+		refs = append(refs, ref)
+	}
+	refs, total := indexer.Paginate(refs, txRef.position, p)
+	results, err := txi.loadResults(ctx, refs)
+	return results, total, err
+}
 
-		res, err := txi.Get(h)
+// loadResults loads transactions referenced by refs.
+//
+// loadResults will exit early and return any result fetched so far,
+// when a message is received on the context chan.
+func (txi *TxIndex) loadResults(ctx context.Context, refs []txRef) ([]*abci.TxResult, error) {
+	results := make([]*abci.TxResult, 0, len(refs))
+RESULTS_LOOP:
+	for _, ref := range refs {
+		res, err := txi.Get(ref.hash)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get Tx{%X}: %w", h, err)
-		}
-		hashString := string(h)
-		if _, ok := resultMap[hashString]; !ok {
-			resultMap[hashString] = struct{}{}
-			results = append(results, res)
+			return nil, fmt.Errorf("failed to get Tx{%X}: %w", ref.hash, err)
 		}
+		results = append(results, res)
 		// Potentially exit early.
 		select {
 		case <-ctx.Done():
@@ -300,10 +348,100 @@ RESULTS_LOOP:
 		default:
 		}
 	}
-
 	return results, nil
 }
 
+// searchOrdered selects transactions matching conditions by scanning a single index in the order selected by
+// pagination. The scan starts at the height bound of the conditions (or at the cursor), and stops at the end of the
+// height range or as soon as a result following the page is found. It supports conditions matching event attributes
+// by equality and height comparisons: the index of the first event condition (or the height index) is scanned, and
+// other conditions are checked for every entry. It returns false if conditions are not supported.
+func (txi *TxIndex) searchOrdered(
+	ctx context.Context,
+	conditions []syntax.Condition,
+	p indexer.Pagination,
+) (refs []txRef, total int, ok bool, err error) {
+	lo, hi, ok := indexer.HeightBounds(conditions, types.TxHeightKey)
+	if !ok {
+		return nil, 0, false, nil
+	}
+	var events []syntax.Condition
+	for _, c := range conditions {
+		if c.Tag == types.TxHeightKey {
+			continue
+		}
+		if c.Op != syntax.TEq {
+			return nil, 0, false, nil
+		}
+		events = append(events, c)
+	}
+	lo, hi = p.HeightRange(lo, hi)
+	if lo > hi {
+		return nil, 0, true, nil
+	}
+
+	// entries of the scanned index are keyed by base/height/index, and values are transaction hashes
+	byHeight := len(events) == 0
+	var base string
+	if !byHeight {
+		base = startKey(events[0].Tag, events[0].Arg.Value())
+		events = events[1:]
+	} else {
+		base = startKey(types.TxHeightKey)
+	}
+	var start string
+	if !p.OrderDesc {
+		start = startKey(base, indexer.HeightKey(lo))
+	} else if hi < math.MaxInt64 {
+		start = startKey(base, indexer.HeightKey(hi+1))
+	}
+
+	pager := indexer.NewPager(p)
+	err = store.PrefixEntriesOrdered(ctx, txi.store, base, start, false, p.OrderDesc, func(entry dsq.Entry) (bool, error) {
+		parts := strings.Split(strings.TrimPrefix(entry.Key, base+tagKeySeparator), tagKeySeparator)
+		if byHeight && len(parts) == 3 {
+			// height index repeats the height as the value
+			parts = parts[1:]
+		}
+		if len(parts) != 2 {
+			return true, nil
+		}
+		height, okHeight := indexer.ParseHeightKey(parts[0])
+		index, okIndex := indexer.ParseIndexKey(parts[1])
+		if !okHeight || !okIndex {
+			// entries written by previous versions are not ordered
+			return true, nil
+		}
+		if height < lo || height > hi {
+			// entries beyond the range follow all entries in the range
+			return (height > hi) == p.OrderDesc, nil
+		}
+		matches, err := txi.matchesEvents(ctx, events, height, index)
+		if err != nil {
+			return false, err
+		}
+		if matches && pager.Add(indexer.Position{Height: height, Index: index}) {
+			refs = append(refs, txRef{hash: entry.Value, height: height, index: index})
+		}
+		return !pager.More() && ctx.Err() == nil, nil
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return refs, pager.Total(), true, nil
+}
+
+// matchesEvents returns true if the transaction at given position is indexed by all events.
+func (txi *TxIndex) matchesEvents(ctx context.Context, events []syntax.Condition, height int64, index uint32) (bool, error) {
+	for _, c := range events {
+		has, err := txi.store.Has(ctx, ds.NewKey(keyForEvent(c.Tag, c.Arg.Value(), height, index)))
+		if err != nil || !has {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 func lookForHash(conditions []syntax.Condition) (hash []byte, ok bool, err error) {
 	for _, c := range conditions {
 		if c.Tag == types.TxHashKey {
@@ -323,16 +461,16 @@ func (txi *TxIndex) match(
 	ctx context.Context,
 	c syntax.Condition,
 	startKeyBz string,
-	filteredHashes map[string][]byte,
+	filteredHashes map[string]txRef,
 	firstRun bool,
-) map[string][]byte {
+) map[string]txRef {
 	// A previous match was attempted but resulted in no matches, so we return
 	// no matches (assuming AND operand).
 	if !firstRun && len(filteredHashes) == 0 {
 		return filteredHashes
 	}
 
-	tmpHashes := make(map[string][]byte)
+	tmpHashes := make(map[string]txRef)
 
 	switch {
 	case c.Op == syntax.TEq:
@@ -344,7 +482,7 @@ func (txi *TxIndex) match(
 		for result := range results.Next() {
 			cont := true
 
-			tmpHashes[string(result.Entry.Value)] = result.Entry.Value
+			tmpHashes[string(result.Entry.Value)] = newTxRef(result.Entry.Key, result.Entry.Value)
 
 			// Potentially exit early.
 			select {
@@ -369,7 +507,7 @@ func (txi *TxIndex) match(
 		for result := range results.Next() {
 			cont := true
 
-			tmpHashes[string(result.Entry.Value)] = result.Entry.Value
+			tmpHashes[string(result.Entry.Value)] = newTxRef(result.Entry.Key, result.Entry.Value)
 
 			// Potentially exit early.
 			select {
@@ -400,7 +538,7 @@ func (txi *TxIndex) match(
 			}
 
 			if strings.Contains(extractValueFromKey([]byte(result.Entry.Key)), c.Arg.Value()) {
-				tmpHashes[string(result.Entry.Value)] = result.Entry.Value
+				tmpHashes[string(result.Entry.Value)] = newTxRef(result.Entry.Key, result.Entry.Value)
 			}
 
 			// Potentially exit early.
@@ -434,7 +572,7 @@ func (txi *TxIndex) match(
 	for k := range filteredHashes {
 		cont := true
 
-		if tmpHashes[k] == nil {
+		if _, ok := tmpHashes[k]; !ok {
 			delete(filteredHashes, k)
 
 			// Potentially exit early.
@@ -462,17 +600,17 @@ func (txi *TxIndex) matchRange(
 	ctx context.Context,
 	qr indexer.QueryRange,
 	startKey string,
-	filteredHashes map[string][]byte,
+	filteredHashes map[string]txRef,
 	firstRun bool,
 	heightInfo HeightInfo,
-) map[string][]byte {
+) map[string]txRef {
 	// A previous match was attempted but resulted in no matches, so we return
 	// no matches (assuming AND operand).
 	if !firstRun && len(filteredHashes) == 0 {
 		return filteredHashes
 	}
 
-	tmpHashes := make(map[string][]byte)
+	tmpHashes := make(map[string]txRef)
 
 	results, err := store.PrefixEntries(ctx, txi.store, startKey)
 	if err != nil {
@@ -524,7 +662,7 @@ LOOP:
 
 			} else {
 				if withinBounds {
-					tmpHashes[string(result.Entry.Value)] = result.Entry.Value
+					tmpHashes[string(result.Entry.Value)] = newTxRef(result.Entry.Key, result.Entry.Value)
 				}
 			}
 
@@ -564,7 +702,7 @@ LOOP:
 	for k := range filteredHashes {
 		cont := true
 
-		if tmpHashes[k] == nil {
+		if _, ok := tmpHashes[k]; !ok {
 			delete(filteredHashes, k)
 
 			// Potentially exit early.
@@ -583,12 +721,49 @@ LOOP:
 	return filteredHashes
 }
 
+// txRef references an indexed transaction by its hash and position, parsed from an index key.
+type txRef struct {
+	hash   []byte
+	height int64
+	index  uint32
+}
+
+func newTxRef(key string, hash []byte) txRef {
+	ref := txRef{hash: hash}
+	parts := strings.Split(key, tagKeySeparator)
+	if len(parts) < 2 {
+		return ref
+	}
+	ref.height, _ = strconv.ParseInt(parts[len(parts)-2], 10, 64)
+	if index, err := strconv.ParseUint(parts[len(parts)-1], 10, 32); err == nil {
+		ref.index = uint32(index) //nolint:gosec
+	}
+	return ref
+}
+
+func (r txRef) position() indexer.Position {
+	return indexer.Position{Height: r.height, Index: r.index}
+}
+
+func txResultPosition(r *abci.TxResult) indexer.Position {
+	return indexer.Position{Height: r.Height, Index: r.Index}
+}
+
 // Keys
 
 func isTagKey(key []byte) bool {
 	return strings.Count(string(key), tagKeySeparator) == 4
 }
 
+// isLegacyKey returns true if key is an event or height index entry written by previous versions, keyed by
+// base/height/index without zero-padding.
+func isLegacyKey(key string) bool {
+	parts := strings.Split(strings.TrimPrefix(key, tagKeySeparator), tagKeySeparator)
+	return len(parts) >= 4 &&
+		indexer.IsLegacyHeightKey(parts[len(parts)-2]) &&
+		indexer.IsLegacyIndexKey(parts[len(parts)-1])
+}
+
 func extractValueFromKey(key []byte) string {
 	parts := strings.SplitN(string(key), tagKeySeparator, 4)
 	return parts[2]
@@ -600,27 +775,31 @@ func extractHeightFromKey(key []byte) (int64, error) {
 	return strconv.ParseInt(parts[len(parts)-2], 10, 64)
 }
 
-func keyForEvent(key string, value string, result *abci.TxResult) string {
-	return fmt.Sprintf("%s/%s/%d/%d",
+func keyForEvent(key string, value string, height int64, index uint32) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
 		key,
 		value,
-		result.Height,
-		result.Index,
+		indexer.HeightKey(height),
+		indexer.IndexKey(index),
 	)
 }
 
 func keyForHeight(result *abci.TxResult) string {
-	return fmt.Sprintf("%s/%d/%d/%d",
+	return fmt.Sprintf("%s/%s/%s/%s",
 		types.TxHeightKey,
-		result.Height,
-		result.Height,
-		result.Index,
+		indexer.HeightKey(result.Height),
+		indexer.HeightKey(result.Height),
+		indexer.IndexKey(result.Index),
 	)
 }
 
 func startKeyForCondition(c syntax.Condition, height int64) string {
 	if height > 0 {
-		return startKey(c.Tag, c.Arg.Value(), strconv.FormatInt(height, 10))
+		value := c.Arg.Value()
+		if c.Tag == types.TxHeightKey {
+			value = indexer.HeightKey(height)
+		}
+		return startKey(c.Tag, value, indexer.HeightKey(height))
 	}
 	return startKey(c.Tag, c.Arg.Value())
 }
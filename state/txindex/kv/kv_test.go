@@ -18,6 +18,7 @@ import (
 	cmrand "github.com/cometbft/cometbft/libs/rand"
 	"github.com/cometbft/cometbft/types"
 
+	"github.com/rollkit/rollkit/state/indexer"
 	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/store"
 )
@@ -269,6 +270,156 @@ func TestTxSearchOneTxWithMultipleSameTagsButDifferentValues(t *testing.T) {
 	}
 }
 
+func TestTxIndexEventFilter(t *testing.T) {
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txIndexer := NewTxIndex(ctx, kvStore)
+	txIndexer.SetEventFilter(indexer.NewEventFilter([]string{"account"}, []string{"account.owner"}))
+
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{
+			{Key: "number", Value: "1", Index: true},
+			{Key: "owner", Value: "Ivan", Index: true},
+		}},
+		{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "amount", Value: "10", Index: true}}},
+	})
+	require.NoError(t, txIndexer.Index(txResult))
+
+	for q, expected := range map[string]int{
+		"account.number = 1":     1,
+		"account.owner = 'Ivan'": 0,
+		"transfer.amount = 10":   0,
+		"tx.height = 1":          1,
+	} {
+		results, err := txIndexer.Search(ctx, query.MustCompile(q))
+		require.NoError(t, err)
+		assert.Len(t, results, expected, q)
+	}
+}
+
+func TestTxSearchPage(t *testing.T) {
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txIndexer := NewTxIndex(ctx, kvStore)
+	for height := int64(1); height <= 3; height++ {
+		for index := uint32(0); index < 2; index++ {
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: "1", Index: true}}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("HELLO WORLD %d %d", height, index))
+			txResult.Height = height
+			txResult.Index = index
+			require.NoError(t, txIndexer.Index(txResult))
+		}
+	}
+
+	positions := func(results []*abci.TxResult) []indexer.Position {
+		res := make([]indexer.Position, 0, len(results))
+		for _, r := range results {
+			res = append(res, indexer.Position{Height: r.Height, Index: r.Index})
+		}
+		return res
+	}
+
+	// ordered scans stop after the first result following the page
+	for q, expectedTotal := range map[string]int{
+		"account.number = 1":                   4,
+		"tx.height >= 1":                       4,
+		"account.number = 1 AND tx.height < 3": 4,
+		"account.number EXISTS":                6,
+	} {
+		results, total, err := txIndexer.SearchPage(ctx, query.MustCompile(q), indexer.Pagination{Offset: 1, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, expectedTotal, total, q)
+		assert.Equal(t, []indexer.Position{{Height: 1, Index: 1}, {Height: 2, Index: 0}}, positions(results), q)
+	}
+
+	results, total, err := txIndexer.SearchPage(ctx, query.MustCompile("account.number = 1"), indexer.Pagination{
+		OrderDesc: true,
+		Offset:    4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 6, total)
+	assert.Equal(t, []indexer.Position{{Height: 1, Index: 1}, {Height: 1, Index: 0}}, positions(results))
+
+	for q, expectedTotal := range map[string]int{"account.number = 1": 2, "account.number EXISTS": 2} {
+		results, total, err = txIndexer.SearchPage(ctx, query.MustCompile(q), indexer.Pagination{
+			OrderDesc: true,
+			After:     &indexer.Position{Height: 2, Index: 0},
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expectedTotal, total, q)
+		assert.Equal(t, []indexer.Position{{Height: 1, Index: 1}}, positions(results), q)
+	}
+
+	hash := types.Tx("HELLO WORLD 2 1").Hash()
+	results, total, err = txIndexer.SearchPage(ctx, query.MustCompile(fmt.Sprintf("tx.hash = '%X'", hash)), indexer.Pagination{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []indexer.Position{{Height: 2, Index: 1}}, positions(results))
+}
+
+func TestTxSearchOrdered(t *testing.T) {
+	kvStore, _ := store.NewDefaultInMemoryKVStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txIndexer := NewTxIndex(ctx, kvStore)
+	for height := int64(8); height <= 12; height++ {
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "account", Attributes: []abci.EventAttribute{
+				{Key: "number", Value: "1", Index: true},
+				{Key: "owner", Value: fmt.Sprintf("owner%d", height%2), Index: true},
+			}},
+		})
+		txResult.Tx = types.Tx(fmt.Sprintf("HELLO WORLD %d", height))
+		txResult.Height = height
+		require.NoError(t, txIndexer.Index(txResult))
+	}
+	// entry written by previous versions, without zero-padded height and index
+	require.NoError(t, kvStore.Put(ctx, ds.NewKey("account.number/1/13/0"), types.Tx("HELLO WORLD 13").Hash()))
+
+	heights := func(results []*abci.TxResult) []int64 {
+		res := make([]int64, 0, len(results))
+		for _, r := range results {
+			res = append(res, r.Height)
+		}
+		return res
+	}
+
+	cases := []struct {
+		query    string
+		p        indexer.Pagination
+		expected []int64
+		total    int
+	}{
+		{"account.number = 1", indexer.Pagination{}, []int64{8, 9, 10, 11, 12}, 5},
+		{"account.number = 1", indexer.Pagination{OrderDesc: true, Limit: 2}, []int64{12, 11}, 3},
+		{"account.owner = 'owner0' AND account.number = 1", indexer.Pagination{}, []int64{8, 10, 12}, 3},
+		{"account.owner = 'owner0' AND tx.height > 8", indexer.Pagination{Limit: 1}, []int64{10}, 2},
+		{"account.number = 1 AND tx.height = 10", indexer.Pagination{}, []int64{10}, 1},
+		{"tx.height >= 9 AND tx.height <= 11", indexer.Pagination{OrderDesc: true}, []int64{11, 10, 9}, 3},
+		{"tx.height < 10", indexer.Pagination{After: &indexer.Position{Height: 8}}, []int64{9}, 1},
+		{"account.number = 1 AND tx.height > 9 AND tx.height < 12", indexer.Pagination{OrderDesc: true}, []int64{11, 10}, 2},
+		{"account.number = 1", indexer.Pagination{OrderDesc: true, After: &indexer.Position{Height: 10}}, []int64{9, 8}, 2},
+		{"account.number = 1", indexer.Pagination{After: &indexer.Position{Height: 12}}, []int64{}, 0},
+		{"account.number = 1 AND tx.height > 12", indexer.Pagination{}, []int64{}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			results, total, err := txIndexer.SearchPage(ctx, query.MustCompile(c.query), c.p)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, heights(results))
+			assert.Equal(t, c.total, total)
+		})
+	}
+}
+
 func TestTxSearchMultipleTxs(t *testing.T) {
 	kvStore, _ := store.NewDefaultInMemoryKVStore()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -386,3 +537,42 @@ func BenchmarkTxIndex500(b *testing.B)   { benchmarkTxIndex(500, b) }
 func BenchmarkTxIndex1000(b *testing.B)  { benchmarkTxIndex(1000, b) }
 func BenchmarkTxIndex2000(b *testing.B)  { benchmarkTxIndex(2000, b) }
 func BenchmarkTxIndex10000(b *testing.B) { benchmarkTxIndex(10000, b) }
+
+func TestTxIndexPruneLegacyEntries(t *testing.T) {
+	ctx := context.Background()
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	txIndexer := NewTxIndex(ctx, kvStore)
+
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: "1", Index: true}}},
+	})
+	require.NoError(t, txIndexer.Index(txResult))
+	hash := types.Tx(txResult.Tx).Hash()
+	// entries written by previous versions, without zero-padded height and index, and entries of other indexes
+	for _, key := range []string{"tx.height/1/1/0", "account.number/1/1/0"} {
+		require.NoError(t, kvStore.Put(ctx, ds.NewKey(key), hash))
+	}
+	for _, key := range []string{"block_events/block.height/1", "block_events/account.number/1/1/end_block"} {
+		require.NoError(t, kvStore.Put(ctx, ds.NewKey(key), []byte{2}))
+	}
+
+	deleted, err := txIndexer.PruneLegacyEntries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	for _, key := range []string{"tx.height/1/1/0", "account.number/1/1/0"} {
+		has, err := kvStore.Has(ctx, ds.NewKey(key))
+		require.NoError(t, err)
+		assert.False(t, has, key)
+	}
+	for _, key := range []string{"block_events/block.height/1", "block_events/account.number/1/1/end_block"} {
+		has, err := kvStore.Has(ctx, ds.NewKey(key))
+		require.NoError(t, err)
+		assert.True(t, has, key)
+	}
+
+	results, err := txIndexer.Search(ctx, query.MustCompile("account.number = 1"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, proto.Equal(txResult, results[0]))
+}
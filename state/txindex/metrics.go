@@ -0,0 +1,63 @@
+package txindex
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "indexer"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Height up to which all blocks are indexed.
+	IndexedHeight metrics.Gauge
+	// Number of blocks received by the indexer service that are not indexed.
+	Lag metrics.Gauge `metrics_name:"lag_blocks"`
+	// Number of failures to index blocks or their transactions.
+	Failures metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		IndexedHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "indexed_height",
+			Help:      "Height up to which all blocks are indexed.",
+		}, labels).With(labelsAndValues...),
+		Lag: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "lag_blocks",
+			Help:      "Number of blocks received by the indexer service that are not indexed.",
+		}, labels).With(labelsAndValues...),
+		Failures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "failures",
+			Help:      "Number of failures to index blocks or their transactions.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		IndexedHeight: discard.NewGauge(),
+		Lag:           discard.NewGauge(),
+		Failures:      discard.NewCounter(),
+	}
+}
@@ -0,0 +1,141 @@
+package txindex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/rollkit/rollkit/state/indexer"
+	"github.com/rollkit/rollkit/store"
+)
+
+// reindexLogInterval defines how often (in blocks) progress of reindexing is logged.
+const reindexLogInterval = 1000
+
+// indexedHeightKey is the key of the height up to which all blocks are indexed.
+var indexedHeightKey = ds.NewKey("indexed_height")
+
+// indexFormatKey is the key of the version of the format of KV index keys.
+var indexFormatKey = ds.NewKey("index_format")
+
+// LoadIndexFormat returns the version of the format of KV index keys (see indexer.KeyFormatVersion), or 0 if
+// indexes were created by versions that didn't persist it.
+func LoadIndexFormat(ctx context.Context, store ds.Datastore) (uint64, error) {
+	value, err := store.Get(ctx, indexFormatKey)
+	if errors.Is(err, ds.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("invalid index format length: %d", len(value))
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// SaveIndexFormat persists the version of the format of KV index keys.
+func SaveIndexFormat(ctx context.Context, store ds.Datastore, version uint64) error {
+	return store.Put(ctx, indexFormatKey, binary.BigEndian.AppendUint64(nil, version))
+}
+
+// LoadIndexedHeight returns the height up to which all blocks are indexed, or 0 if it's not known.
+func LoadIndexedHeight(ctx context.Context, store ds.Datastore) (uint64, error) {
+	value, err := store.Get(ctx, indexedHeightKey)
+	if errors.Is(err, ds.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("invalid indexed height length: %d", len(value))
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// SaveIndexedHeight persists the height up to which all blocks are indexed.
+func SaveIndexedHeight(ctx context.Context, store ds.Datastore, height uint64) error {
+	return store.Put(ctx, indexedHeightKey, binary.BigEndian.AppendUint64(nil, height))
+}
+
+// PruneLegacyEntries deletes index entries written in the key format of previous versions from indexers
+// implementing indexer.LegacyEntriesPruner.
+func PruneLegacyEntries(ctx context.Context, txIdxr TxIndexer, blockIdxr indexer.BlockIndexer, logger log.Logger) error {
+	for _, idxr := range []interface{}{txIdxr, blockIdxr} {
+		pruner, ok := idxr.(indexer.LegacyEntriesPruner)
+		if !ok {
+			continue
+		}
+		deleted, err := pruner.PruneLegacyEntries(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete index entries in previous format: %w", err)
+		}
+		if deleted > 0 {
+			logger.Info("deleted index entries in previous format", "count", deleted)
+		}
+	}
+	return nil
+}
+
+// Reindex rebuilds transaction and block index entries of blocks in [from, to] range, using block data and
+// responses persisted in the store. Blocks are not re-executed. Existing index entries are overwritten.
+func Reindex(ctx context.Context, blocks store.Store, txIdxr TxIndexer, blockIdxr indexer.BlockIndexer, from, to uint64, logger log.Logger) error {
+	if from > to {
+		return fmt.Errorf("invalid height range: from %d is greater than to %d", from, to)
+	}
+	logger.Info("reindexing blocks", "from", from, "to", to)
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := reindexBlock(ctx, blocks, txIdxr, blockIdxr, height); err != nil {
+			return fmt.Errorf("failed to reindex block %d: %w", height, err)
+		}
+		if (height-from+1)%reindexLogInterval == 0 {
+			logger.Info("reindexing blocks", "height", height, "to", to)
+		}
+	}
+	logger.Info("reindexed blocks", "from", from, "to", to)
+	return nil
+}
+
+func reindexBlock(ctx context.Context, blocks store.Store, txIdxr TxIndexer, blockIdxr indexer.BlockIndexer, height uint64) error {
+	_, data, err := blocks.GetBlockData(ctx, height)
+	if err != nil {
+		return err
+	}
+	resp, err := blocks.GetBlockResponses(ctx, height)
+	if err != nil {
+		return err
+	}
+	if len(resp.TxResults) != len(data.Txs) {
+		return fmt.Errorf("number of transaction results (%d) doesn't match number of transactions (%d)", len(resp.TxResults), len(data.Txs))
+	}
+
+	batch := NewBatch(int64(len(data.Txs)))
+	for i, tx := range data.Txs {
+		if err := batch.Add(&abci.TxResult{
+			Height: int64(height), //nolint:gosec
+			Index:  uint32(i),     //nolint:gosec
+			Tx:     tx,
+			Result: *resp.TxResults[i],
+		}); err != nil {
+			return err
+		}
+	}
+	if err := blockIdxr.Index(types.EventDataNewBlockEvents{
+		Height: int64(height), //nolint:gosec
+		Events: resp.Events,
+		NumTxs: int64(len(data.Txs)),
+	}); err != nil {
+		return err
+	}
+	return txIdxr.AddBatch(batch)
+}
@@ -0,0 +1,171 @@
+package txindex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
+	"github.com/rollkit/rollkit/state/txindex"
+	"github.com/rollkit/rollkit/state/txindex/kv"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestReindex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	blocksKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blocks := store.New(blocksKV)
+	for height := uint64(1); height <= 3; height++ {
+		header, data := types.GetRandomBlock(height, 2, "TestReindex")
+		require.NoError(blocks.SaveBlockData(ctx, header, data, &types.Signature{}))
+		require.NoError(blocks.SaveBlockResponses(ctx, height, &abci.ResponseFinalizeBlock{
+			TxResults: []*abci.ExecTxResult{{Code: 0}, {Code: 1}},
+		}))
+	}
+
+	indexerKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	txIndexer := kv.NewTxIndex(ctx, indexerKV)
+	blockIndexer := blockidxkv.New(ctx, indexerKV)
+
+	require.Error(txindex.Reindex(ctx, blocks, txIndexer, blockIndexer, 3, 2, log.TestingLogger()))
+	require.Error(txindex.Reindex(ctx, blocks, txIndexer, blockIndexer, 3, 4, log.TestingLogger()))
+	require.NoError(txindex.Reindex(ctx, blocks, txIndexer, blockIndexer, 2, 3, log.TestingLogger()))
+
+	ok, err := blockIndexer.Has(1)
+	require.NoError(err)
+	assert.False(ok)
+	for height := uint64(2); height <= 3; height++ {
+		ok, err := blockIndexer.Has(int64(height))
+		require.NoError(err)
+		assert.True(ok)
+
+		_, data, err := blocks.GetBlockData(ctx, height)
+		require.NoError(err)
+		for i, tx := range data.Txs {
+			res, err := txIndexer.Get(cmtypes.Tx(tx).Hash())
+			require.NoError(err)
+			require.NotNil(res)
+			assert.Equal(int64(height), res.Height)
+			assert.Equal(uint32(i), res.Index)
+			assert.Equal(uint32(i), res.Result.Code)
+		}
+	}
+}
+
+func TestIndexerServiceTracksIndexedHeight(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventBus := cmtypes.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	kvStore, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	prefixStore := (ktds.Wrap(kvStore, ktds.PrefixTransform{Prefix: ds.NewKey("block_events")}).Children()[0]).(ds.TxnDatastore)
+	statusStore := ds.NewMapDatastore()
+
+	service := txindex.NewIndexerService(ctx, kv.NewTxIndex(ctx, kvStore), blockidxkv.New(ctx, prefixStore), eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	service.TrackIndexedHeight(statusStore, 1, 1)
+	require.NoError(service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	indexedHeight := func() uint64 {
+		height, err := txindex.LoadIndexedHeight(ctx, statusStore)
+		require.NoError(err)
+		return height
+	}
+
+	// block directly following indexed height advances it
+	require.NoError(eventBus.PublishEventNewBlockEvents(cmtypes.EventDataNewBlockEvents{Height: 2}))
+	require.Eventually(func() bool { return indexedHeight() == 2 }, time.Second, 10*time.Millisecond)
+
+	// gap in indexed blocks stops advancing the height
+	require.NoError(eventBus.PublishEventNewBlockEvents(cmtypes.EventDataNewBlockEvents{Height: 4}))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(uint64(2), indexedHeight())
+}
+
+func TestIndexerServiceReindexesMissingBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventBus := cmtypes.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	blocksKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blocks := store.New(blocksKV)
+	for height := uint64(1); height <= 3; height++ {
+		header, data := types.GetRandomBlock(height, 1, "TestIndexerServiceReindexesMissingBlocks")
+		require.NoError(blocks.SaveBlockData(ctx, header, data, &types.Signature{}))
+		require.NoError(blocks.SaveBlockResponses(ctx, height, &abci.ResponseFinalizeBlock{
+			TxResults: []*abci.ExecTxResult{{}},
+		}))
+	}
+
+	indexerKV, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	blockIndexer := blockidxkv.New(ctx, indexerKV)
+	statusStore := ds.NewMapDatastore()
+	service := txindex.NewIndexerService(ctx, kv.NewTxIndex(ctx, indexerKV), blockIndexer, eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	service.TrackIndexedHeight(statusStore, 1, 3)
+	service.ReindexMissingBlocks(blocks)
+	service.ReportError(errors.New("failed to index block 2"))
+	require.NoError(service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// blocks after indexed height are reindexed in the background, and new blocks are indexed after them; errors
+	// that left blocks missing are cleared once they are reindexed
+	require.NoError(eventBus.PublishEventNewBlockEvents(cmtypes.EventDataNewBlockEvents{Height: 4}))
+	require.Eventually(func() bool { return service.IndexedHeight() == 4 }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(func() bool { return !service.Status(4).Reindexing }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(func() bool { return service.Err() == nil }, time.Second, 10*time.Millisecond)
+	for height := int64(2); height <= 4; height++ {
+		ok, err := blockIndexer.Has(height)
+		require.NoError(err)
+		assert.True(ok)
+	}
+	saved, err := txindex.LoadIndexedHeight(ctx, statusStore)
+	require.NoError(err)
+	assert.Equal(uint64(4), saved)
+}
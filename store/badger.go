@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dgraph-io/badger/v4"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	badger4 "github.com/ipfs/go-ds-badger4"
+)
+
+// badgerDatastore is the BadgerDB datastore, able to query entries in order of keys starting from a given key.
+// Reverse prefix queries of go-ds-badger4 start before the prefix, and return nothing.
+type badgerDatastore struct {
+	*badger4.Datastore
+}
+
+var _ OrderedQuerier = badgerDatastore{}
+
+func newBadgerDatastore(path string, options *badger4.Options) (ds.TxnDatastore, error) {
+	d, err := badger4.NewDatastore(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return badgerDatastore{d}, nil
+}
+
+// QueryOrdered returns entries with given prefix in ascending or descending order of keys, starting from start if it
+// is not empty.
+func (d badgerDatastore) QueryOrdered(_ context.Context, prefix, start string, keysOnly, desc bool) (dsq.Results, error) {
+	prefix = ds.NewKey(prefix).String()
+	if prefix != "/" {
+		prefix += "/"
+	}
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = !keysOnly
+	opts.Reverse = desc
+	opts.Prefix = []byte(prefix)
+	txn := d.DB.NewTransaction(false)
+	it := txn.NewIterator(opts)
+	switch {
+	case !desc:
+		it.Seek([]byte(max(prefix, start)))
+	case start == "":
+		// reverse iterator seeks the last key not greater than the given one
+		it.Seek(append([]byte(prefix), 0xff))
+	default:
+		it.Seek([]byte(start))
+		if it.Valid() && string(it.Item().Key()) == start {
+			it.Next()
+		}
+	}
+
+	order := dsq.Order(dsq.OrderByKey{})
+	if desc {
+		order = dsq.OrderByKeyDescending{}
+	}
+	q := dsq.Query{Prefix: prefix, KeysOnly: keysOnly, Orders: []dsq.Order{order}}
+	return dsq.ResultsFromIterator(q, dsq.Iterator{
+		Next: func() (dsq.Result, bool) {
+			if txn == nil || !it.Valid() {
+				return dsq.Result{}, false
+			}
+			item := it.Item()
+			entry := dsq.Entry{Key: string(item.Key()), Size: int(item.ValueSize())}
+			if !keysOnly {
+				value, err := item.ValueCopy(nil)
+				if err != nil {
+					return dsq.Result{Error: err}, true
+				}
+				entry.Value = value
+			}
+			it.Next()
+			return dsq.Result{Entry: entry}, true
+		},
+		Close: func() error {
+			// Close may be called more than once
+			if txn != nil {
+				it.Close()
+				txn.Discard()
+				txn = nil
+			}
+			return nil
+		},
+	}), nil
+}
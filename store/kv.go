@@ -21,13 +21,13 @@ func NewDefaultInMemoryKVStore() (ds.TxnDatastore, error) {
 		GcSleep:        10 * time.Second,
 		Options:        badger4.DefaultOptions.WithInMemory(true),
 	}
-	return badger4.NewDatastore("", inMemoryOptions)
+	return newBadgerDatastore("", inMemoryOptions)
 }
 
 // NewDefaultKVStore creates instance of default key-value store.
 func NewDefaultKVStore(rootDir, dbPath, dbName string) (ds.TxnDatastore, error) {
 	path := filepath.Join(rootify(rootDir, dbPath), dbName)
-	return badger4.NewDatastore(path, nil)
+	return newBadgerDatastore(path, nil)
 }
 
 // PrefixEntries retrieves all entries in the datastore whose keys have the supplied prefix
@@ -39,6 +39,61 @@ func PrefixEntries(ctx context.Context, store ds.Datastore, prefix string) (dsq.
 	return results, nil
 }
 
+// OrderedQuerier is implemented by datastores that can query entries with given prefix in ascending or descending
+// order of keys, starting from a given key, without sorting them in memory. Datastores created by this package
+// implement it.
+type OrderedQuerier interface {
+	// QueryOrdered returns entries with given prefix in the selected order. If start is not empty, ascending queries
+	// return only entries with keys not less than start, and descending queries only entries with keys less than start.
+	QueryOrdered(ctx context.Context, prefix, start string, keysOnly, desc bool) (dsq.Results, error)
+}
+
+// PrefixEntriesOrdered calls fn for entries in the datastore whose keys have the supplied prefix, in ascending or
+// descending order of keys, until fn returns false or an error. If start is not empty, entries preceding it in the
+// selected order are skipped: in ascending order the scan starts at the first key not less than start, and in
+// descending order at the last key less than start. Values are not loaded if keysOnly is set.
+func PrefixEntriesOrdered(
+	ctx context.Context,
+	store ds.Datastore,
+	prefix, start string,
+	keysOnly, desc bool,
+	fn func(dsq.Entry) (bool, error),
+) error {
+	var (
+		results dsq.Results
+		err     error
+	)
+	q, seeks := store.(OrderedQuerier)
+	if seeks {
+		results, err = q.QueryOrdered(ctx, prefix, start, keysOnly, desc)
+	} else {
+		order := dsq.Order(dsq.OrderByKey{})
+		if desc {
+			order = dsq.OrderByKeyDescending{}
+		}
+		results, err = store.Query(ctx, dsq.Query{Prefix: prefix, KeysOnly: keysOnly, Orders: []dsq.Order{order}})
+	}
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+	for {
+		result, ok := results.NextSync()
+		if !ok {
+			return nil
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if !seeks && start != "" && (result.Entry.Key < start) != desc {
+			continue
+		}
+		if cont, err := fn(result.Entry); err != nil || !cont {
+			return err
+		}
+	}
+}
+
 // GenerateKey creates a key from a slice of string fields, joining them with slashes.
 func GenerateKey(fields []string) string {
 	key := "/" + strings.Join(fields, "/")
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixEntriesOrdered(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	badgerStore, err := NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+
+	for name, store := range map[string]ds.Datastore{"badger": badgerStore, "map": ds.NewMapDatastore()} {
+		for _, key := range []string{"/a", "/b/1", "/b/2", "/b/3", "/c/1"} {
+			require.NoError(t, store.Put(ctx, ds.NewKey(key), []byte(key)))
+		}
+		scan := func(start string, desc bool, limit int) []string {
+			var keys []string
+			err := PrefixEntriesOrdered(ctx, store, "/b", start, false, desc, func(entry dsq.Entry) (bool, error) {
+				assert.Equal(t, entry.Key, string(entry.Value))
+				keys = append(keys, entry.Key)
+				return len(keys) < limit, nil
+			})
+			require.NoError(t, err)
+			return keys
+		}
+		assert.Equal(t, []string{"/b/1", "/b/2", "/b/3"}, scan("", false, 10), name)
+		assert.Equal(t, []string{"/b/3", "/b/2", "/b/1"}, scan("", true, 10), name)
+		assert.Equal(t, []string{"/b/3", "/b/2"}, scan("", true, 2), name)
+		assert.Equal(t, []string{"/b/2", "/b/3"}, scan("/b/2", false, 10), name)
+		assert.Equal(t, []string{"/b/1"}, scan("/b/2", true, 10), name)
+		assert.Equal(t, []string{"/b/1", "/b/2", "/b/3"}, scan("/a", false, 10), name)
+		assert.Equal(t, []string{"/b/3", "/b/2", "/b/1"}, scan("/b/4", true, 10), name)
+		assert.Empty(t, scan("/c", false, 10), name)
+		assert.Empty(t, scan("/b/1", true, 10), name)
+	}
+}
@@ -23,6 +23,7 @@ var (
 	indexPrefix          = "i"
 	signaturePrefix      = "c"
 	extendedCommitPrefix = "ec"
+	daInclusionPrefix    = "da"
 	statePrefix          = "s"
 	responsesPrefix      = "r"
 	metaPrefix           = "m"
@@ -211,6 +212,29 @@ func (s *DefaultStore) GetExtendedCommit(ctx context.Context, height uint64) (*a
 	return extendedCommit, nil
 }
 
+// SaveDAInclusionProof saves DA inclusion proof of a block at given height in Store.
+func (s *DefaultStore) SaveDAInclusionProof(ctx context.Context, height uint64, proof *types.DAInclusionProof) error {
+	bytes, err := proof.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal DA inclusion proof: %w", err)
+	}
+	return s.db.Put(ctx, ds.NewKey(getDAInclusionKey(height)), bytes)
+}
+
+// GetDAInclusionProof returns DA inclusion proof of a block at given height, or error if it's not found in Store.
+func (s *DefaultStore) GetDAInclusionProof(ctx context.Context, height uint64) (*types.DAInclusionProof, error) {
+	bytes, err := s.db.Get(ctx, ds.NewKey(getDAInclusionKey(height)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load DA inclusion proof for height %v: %w", height, err)
+	}
+	proof := new(types.DAInclusionProof)
+	err = proof.UnmarshalBinary(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DA inclusion proof: %w", err)
+	}
+	return proof, nil
+}
+
 // UpdateState updates state saved in Store. Only one State is stored.
 // If there is no State in Store, state will be saved.
 func (s *DefaultStore) UpdateState(ctx context.Context, state types.State) error {
@@ -291,6 +315,10 @@ func getExtendedCommitKey(height uint64) string {
 	return GenerateKey([]string{extendedCommitPrefix, strconv.FormatUint(height, 10)})
 }
 
+func getDAInclusionKey(height uint64) string {
+	return GenerateKey([]string{daInclusionPrefix, strconv.FormatUint(height, 10)})
+}
+
 func getIndexKey(height uint64) string {
 	return GenerateKey([]string{indexPrefix, strconv.FormatUint(height, 10)})
 }
@@ -288,3 +288,38 @@ func TestExtendedCommits(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expected, commit)
 }
+
+func TestDAInclusionProofs(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kv, err := NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := New(kv)
+
+	// reading before saving returns error
+	proof, err := s.GetDAInclusionProof(ctx, 1)
+	require.Error(err)
+	require.ErrorIs(err, ds.ErrNotFound)
+	require.Nil(proof)
+
+	expected := &types.DAInclusionProof{
+		Height:     1,
+		DAHeight:   42,
+		Namespace:  types.GetRandomBytes(10),
+		ID:         types.GetRandomBytes(40),
+		Commitment: types.GetRandomBytes(32),
+		Proof:      types.GetRandomBytes(64),
+		Inclusions: []types.DAInclusion{{Endpoint: "grpc://a", DAHeight: 42}, {Endpoint: "grpc://b", DAHeight: 43}},
+	}
+
+	err = s.SaveDAInclusionProof(ctx, 1, expected)
+	require.NoError(err)
+	proof, err = s.GetDAInclusionProof(ctx, 1)
+	require.NoError(err)
+	require.Equal(expected, proof)
+}
@@ -41,6 +41,12 @@ type Store interface {
 	// GetExtendedCommit returns extended commit (commit with vote extensions) for a block at given height.
 	GetExtendedCommit(ctx context.Context, height uint64) (*abci.ExtendedCommitInfo, error)
 
+	// SaveDAInclusionProof saves DA inclusion proof of a block at given height in Store.
+	SaveDAInclusionProof(ctx context.Context, height uint64, proof *types.DAInclusionProof) error
+
+	// GetDAInclusionProof returns DA inclusion proof of a block at given height, or error if it's not found in Store.
+	GetDAInclusionProof(ctx context.Context, height uint64) (*types.DAInclusionProof, error)
+
 	// UpdateState updates state saved in Store. Only one State is stored.
 	// If there is no State in Store, state will be saved.
 	UpdateState(ctx context.Context, state types.State) error
@@ -145,6 +145,36 @@ func (_m *Store) GetBlockResponses(ctx context.Context, height uint64) (*abcityp
 	return r0, r1
 }
 
+// GetDAInclusionProof provides a mock function with given fields: ctx, height
+func (_m *Store) GetDAInclusionProof(ctx context.Context, height uint64) (*types.DAInclusionProof, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDAInclusionProof")
+	}
+
+	var r0 *types.DAInclusionProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*types.DAInclusionProof, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *types.DAInclusionProof); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.DAInclusionProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExtendedCommit provides a mock function with given fields: ctx, height
 func (_m *Store) GetExtendedCommit(ctx context.Context, height uint64) (*abcitypes.ExtendedCommitInfo, error) {
 	ret := _m.Called(ctx, height)
@@ -347,6 +377,24 @@ func (_m *Store) SaveBlockResponses(ctx context.Context, height uint64, response
 	return r0
 }
 
+// SaveDAInclusionProof provides a mock function with given fields: ctx, height, proof
+func (_m *Store) SaveDAInclusionProof(ctx context.Context, height uint64, proof *types.DAInclusionProof) error {
+	ret := _m.Called(ctx, height, proof)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDAInclusionProof")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *types.DAInclusionProof) error); ok {
+		r0 = rf(ctx, height, proof)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveExtendedCommit provides a mock function with given fields: ctx, height, commit
 func (_m *Store) SaveExtendedCommit(ctx context.Context, height uint64, commit *abcitypes.ExtendedCommitInfo) error {
 	ret := _m.Called(ctx, height, commit)
@@ -0,0 +1,30 @@
+package types
+
+import (
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+)
+
+// DAInclusion records that a block was included by a DA endpoint at given DA height.
+type DAInclusion struct {
+	Endpoint string `json:"endpoint"`
+	DAHeight uint64 `json:"da_height"`
+}
+
+// DAInclusionProof contains information required to prove that a block was published to DA layer.
+type DAInclusionProof struct {
+	// Height is the rollup block height.
+	Height uint64 `json:"height"`
+	// DAHeight is the height of DA block including the block header.
+	DAHeight uint64 `json:"da_height"`
+	// Namespace is the DA namespace block header was submitted to.
+	Namespace cmbytes.HexBytes `json:"namespace"`
+	// ID identifies the blob containing block header in DA layer.
+	ID cmbytes.HexBytes `json:"id"`
+	// Commitment is the DA commitment to the blob.
+	Commitment cmbytes.HexBytes `json:"commitment"`
+	// Proof is the DA inclusion proof of the blob. It can be verified with DA Validate method.
+	Proof cmbytes.HexBytes `json:"proof"`
+	// Inclusions lists DA endpoints that included the block, if it was submitted to multiple endpoints.
+	// ID, commitment and proof come from the first of them.
+	Inclusions []DAInclusion `json:"inclusions,omitempty"`
+}
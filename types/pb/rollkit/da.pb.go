@@ -0,0 +1,829 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: rollkit/da.proto
+
+package rollkit
+
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// DAInclusion records that a block was included by a DA endpoint at given DA height.
+type DAInclusion struct {
+	Endpoint string `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	DaHeight uint64 `protobuf:"varint,2,opt,name=da_height,json=daHeight,proto3" json:"da_height,omitempty"`
+}
+
+func (m *DAInclusion) Reset()         { *m = DAInclusion{} }
+func (m *DAInclusion) String() string { return proto.CompactTextString(m) }
+func (*DAInclusion) ProtoMessage()    {}
+func (*DAInclusion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bc8bf477dc1105a5, []int{0}
+}
+func (m *DAInclusion) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DAInclusion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DAInclusion.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DAInclusion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DAInclusion.Merge(m, src)
+}
+func (m *DAInclusion) XXX_Size() int {
+	return m.Size()
+}
+func (m *DAInclusion) XXX_DiscardUnknown() {
+	xxx_messageInfo_DAInclusion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DAInclusion proto.InternalMessageInfo
+
+func (m *DAInclusion) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *DAInclusion) GetDaHeight() uint64 {
+	if m != nil {
+		return m.DaHeight
+	}
+	return 0
+}
+
+// DAInclusionProof contains information required to prove that a block was published to DA layer.
+type DAInclusionProof struct {
+	// Rollup block height.
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// Height of DA block including the block header.
+	DaHeight uint64 `protobuf:"varint,2,opt,name=da_height,json=daHeight,proto3" json:"da_height,omitempty"`
+	// DA namespace block header was submitted to.
+	Namespace []byte `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// ID of the blob containing block header in DA layer.
+	Id []byte `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	// DA commitment to the blob.
+	Commitment []byte `protobuf:"bytes,5,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	// DA inclusion proof of the blob.
+	Proof []byte `protobuf:"bytes,6,opt,name=proof,proto3" json:"proof,omitempty"`
+	// DA endpoints that included the block, if it was submitted to multiple endpoints.
+	Inclusions []*DAInclusion `protobuf:"bytes,7,rep,name=inclusions,proto3" json:"inclusions,omitempty"`
+}
+
+func (m *DAInclusionProof) Reset()         { *m = DAInclusionProof{} }
+func (m *DAInclusionProof) String() string { return proto.CompactTextString(m) }
+func (*DAInclusionProof) ProtoMessage()    {}
+func (*DAInclusionProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bc8bf477dc1105a5, []int{1}
+}
+func (m *DAInclusionProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DAInclusionProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DAInclusionProof.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DAInclusionProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DAInclusionProof.Merge(m, src)
+}
+func (m *DAInclusionProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *DAInclusionProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_DAInclusionProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DAInclusionProof proto.InternalMessageInfo
+
+func (m *DAInclusionProof) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *DAInclusionProof) GetDaHeight() uint64 {
+	if m != nil {
+		return m.DaHeight
+	}
+	return 0
+}
+
+func (m *DAInclusionProof) GetNamespace() []byte {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+func (m *DAInclusionProof) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *DAInclusionProof) GetCommitment() []byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *DAInclusionProof) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *DAInclusionProof) GetInclusions() []*DAInclusion {
+	if m != nil {
+		return m.Inclusions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DAInclusion)(nil), "rollkit.DAInclusion")
+	proto.RegisterType((*DAInclusionProof)(nil), "rollkit.DAInclusionProof")
+}
+
+func init() { proto.RegisterFile("rollkit/da.proto", fileDescriptor_bc8bf477dc1105a5) }
+
+var fileDescriptor_bc8bf477dc1105a5 = []byte{
+	// 279 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xc1, 0x4a, 0xc3, 0x30,
+	0x18, 0xc7, 0x97, 0x6e, 0xeb, 0xd6, 0x6f, 0x22, 0x23, 0x0c, 0x09, 0x2a, 0xa1, 0xec, 0x54, 0x10,
+	0x5a, 0x50, 0x5f, 0x40, 0x51, 0xd1, 0x9b, 0xf4, 0xe8, 0x45, 0xda, 0x26, 0xae, 0xc1, 0x36, 0x09,
+	0x6d, 0x76, 0xf0, 0x2d, 0x7c, 0x2c, 0x8f, 0x3b, 0x7a, 0xd4, 0xf6, 0x45, 0xc4, 0xd8, 0x6a, 0x4f,
+	0x9e, 0xc2, 0xef, 0x97, 0x3f, 0x1f, 0x5f, 0xfe, 0x81, 0x65, 0xa5, 0x8a, 0xe2, 0x59, 0x98, 0x88,
+	0x25, 0xa1, 0xae, 0x94, 0x51, 0x78, 0xd6, 0x99, 0xf5, 0x0d, 0x2c, 0xae, 0x2e, 0xee, 0x64, 0x56,
+	0x6c, 0x6b, 0xa1, 0x24, 0x3e, 0x84, 0x39, 0x97, 0x4c, 0x2b, 0x21, 0x0d, 0x41, 0x3e, 0x0a, 0xbc,
+	0xf8, 0x97, 0xf1, 0x11, 0x78, 0x2c, 0x79, 0xcc, 0xb9, 0xd8, 0xe4, 0x86, 0x38, 0x3e, 0x0a, 0x26,
+	0xf1, 0x9c, 0x25, 0xb7, 0x96, 0xd7, 0x9f, 0x08, 0x96, 0x83, 0x41, 0xf7, 0x95, 0x52, 0x4f, 0xf8,
+	0x00, 0xdc, 0x2e, 0x8e, 0x6c, 0xbc, 0xa3, 0x7f, 0x27, 0xe1, 0x63, 0xf0, 0x64, 0x52, 0xf2, 0x5a,
+	0x27, 0x19, 0x27, 0x63, 0x1f, 0x05, 0x7b, 0xf1, 0x9f, 0xc0, 0xfb, 0xe0, 0x08, 0x46, 0x26, 0x56,
+	0x3b, 0x82, 0x61, 0x0a, 0x90, 0xa9, 0xb2, 0x14, 0xa6, 0xe4, 0xd2, 0x90, 0xa9, 0xf5, 0x03, 0x83,
+	0x57, 0x30, 0xd5, 0xdf, 0xbb, 0x10, 0xd7, 0x5e, 0xfd, 0x00, 0x3e, 0x07, 0x10, 0xfd, 0xaa, 0x35,
+	0x99, 0xf9, 0xe3, 0x60, 0x71, 0xba, 0x0a, 0xbb, 0x4e, 0xc2, 0xc1, 0x3b, 0xe2, 0x41, 0xee, 0xf2,
+	0xfa, 0xad, 0xa1, 0x68, 0xd7, 0x50, 0xf4, 0xd1, 0x50, 0xf4, 0xda, 0xd2, 0xd1, 0xae, 0xa5, 0xa3,
+	0xf7, 0x96, 0x8e, 0x1e, 0x4e, 0x36, 0xc2, 0xe4, 0xdb, 0x34, 0xcc, 0x54, 0x19, 0xf5, 0x5d, 0xf7,
+	0xa7, 0x79, 0xd1, 0xbc, 0x8e, 0x74, 0xda, 0x8b, 0xd4, 0xb5, 0x5f, 0x70, 0xf6, 0x35, 0x00, 0x2d,
+	0xef, 0x90, 0xaa, 0x96, 0x01, 0x00, 0x00,
+}
+
+func (m *DAInclusion) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DAInclusion) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DAInclusion) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DaHeight != 0 {
+		i = encodeVarintDa(dAtA, i, uint64(m.DaHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Endpoint) > 0 {
+		i -= len(m.Endpoint)
+		copy(dAtA[i:], m.Endpoint)
+		i = encodeVarintDa(dAtA, i, uint64(len(m.Endpoint)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DAInclusionProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DAInclusionProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DAInclusionProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Inclusions) > 0 {
+		for iNdEx := len(m.Inclusions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Inclusions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintDa(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Proof) > 0 {
+		i -= len(m.Proof)
+		copy(dAtA[i:], m.Proof)
+		i = encodeVarintDa(dAtA, i, uint64(len(m.Proof)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Commitment) > 0 {
+		i -= len(m.Commitment)
+		copy(dAtA[i:], m.Commitment)
+		i = encodeVarintDa(dAtA, i, uint64(len(m.Commitment)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintDa(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Namespace) > 0 {
+		i -= len(m.Namespace)
+		copy(dAtA[i:], m.Namespace)
+		i = encodeVarintDa(dAtA, i, uint64(len(m.Namespace)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.DaHeight != 0 {
+		i = encodeVarintDa(dAtA, i, uint64(m.DaHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintDa(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintDa(dAtA []byte, offset int, v uint64) int {
+	offset -= sovDa(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *DAInclusion) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Endpoint)
+	if l > 0 {
+		n += 1 + l + sovDa(uint64(l))
+	}
+	if m.DaHeight != 0 {
+		n += 1 + sovDa(uint64(m.DaHeight))
+	}
+	return n
+}
+
+func (m *DAInclusionProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovDa(uint64(m.Height))
+	}
+	if m.DaHeight != 0 {
+		n += 1 + sovDa(uint64(m.DaHeight))
+	}
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovDa(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovDa(uint64(l))
+	}
+	l = len(m.Commitment)
+	if l > 0 {
+		n += 1 + l + sovDa(uint64(l))
+	}
+	l = len(m.Proof)
+	if l > 0 {
+		n += 1 + l + sovDa(uint64(l))
+	}
+	if len(m.Inclusions) > 0 {
+		for _, e := range m.Inclusions {
+			l = e.Size()
+			n += 1 + l + sovDa(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovDa(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozDa(x uint64) (n int) {
+	return sovDa(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *DAInclusion) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDa
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DAInclusion: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DAInclusion: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DaHeight", wireType)
+			}
+			m.DaHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DaHeight |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDa(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDa
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DAInclusionProof) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDa
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DAInclusionProof: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DAInclusionProof: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DaHeight", wireType)
+			}
+			m.DaHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DaHeight |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = append(m.Namespace[:0], dAtA[iNdEx:postIndex]...)
+			if m.Namespace == nil {
+				m.Namespace = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commitment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commitment = append(m.Commitment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Commitment == nil {
+				m.Commitment = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Proof = append(m.Proof[:0], dAtA[iNdEx:postIndex]...)
+			if m.Proof == nil {
+				m.Proof = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inclusions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDa
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDa
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Inclusions = append(m.Inclusions, &DAInclusion{})
+			if err := m.Inclusions[len(m.Inclusions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDa(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDa
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipDa(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowDa
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDa
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthDa
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupDa
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthDa
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthDa        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowDa          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupDa = fmt.Errorf("proto: unexpected end of group")
+)
@@ -272,6 +272,59 @@ func (s *State) FromProto(other *pb.State) error {
 	return nil
 }
 
+// ToProto converts DAInclusionProof into protobuf representation and returns it.
+func (p *DAInclusionProof) ToProto() *pb.DAInclusionProof {
+	var inclusions []*pb.DAInclusion
+	for _, inclusion := range p.Inclusions {
+		inclusions = append(inclusions, &pb.DAInclusion{
+			Endpoint: inclusion.Endpoint,
+			DaHeight: inclusion.DAHeight,
+		})
+	}
+	return &pb.DAInclusionProof{
+		Height:     p.Height,
+		DaHeight:   p.DAHeight,
+		Namespace:  p.Namespace,
+		Id:         p.ID,
+		Commitment: p.Commitment,
+		Proof:      p.Proof,
+		Inclusions: inclusions,
+	}
+}
+
+// FromProto fills DAInclusionProof with data from its protobuf representation.
+func (p *DAInclusionProof) FromProto(other *pb.DAInclusionProof) {
+	p.Height = other.Height
+	p.DAHeight = other.DaHeight
+	p.Namespace = other.Namespace
+	p.ID = other.Id
+	p.Commitment = other.Commitment
+	p.Proof = other.Proof
+	p.Inclusions = nil
+	for _, inclusion := range other.Inclusions {
+		p.Inclusions = append(p.Inclusions, DAInclusion{
+			Endpoint: inclusion.Endpoint,
+			DAHeight: inclusion.DaHeight,
+		})
+	}
+}
+
+// MarshalBinary encodes DAInclusionProof into binary form and returns it.
+func (p *DAInclusionProof) MarshalBinary() ([]byte, error) {
+	return p.ToProto().Marshal()
+}
+
+// UnmarshalBinary decodes binary form of DAInclusionProof into object.
+func (p *DAInclusionProof) UnmarshalBinary(data []byte) error {
+	var pProof pb.DAInclusionProof
+	err := pProof.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	p.FromProto(&pProof)
+	return nil
+}
+
 func txsToByteSlices(txs Txs) [][]byte {
 	if txs == nil {
 		return nil